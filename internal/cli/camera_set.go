@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cameraSetFlags selects a group of cameras for multi-camera commands (wall, snapshot, mosaic, ...).
+//
+// Cameras can be picked by site, by a search query, by explicit references (camera_id, local label,
+// or exact name) or all at once. The selections are combined (union).
+type cameraSetFlags struct {
+	Site     string
+	Query    string
+	All      bool
+	Source   string
+	PageSize int
+}
+
+func addCameraSetFlags(cmd *cobra.Command, f *cameraSetFlags) {
+	cmd.Flags().StringVar(&f.Site, "site", "", "Select cameras whose site matches (case-insensitive)")
+	cmd.Flags().StringVar(&f.Query, "query", "", "Select cameras matching a search query (FTS when the local index is used)")
+	cmd.Flags().BoolVar(&f.All, "all", false, "Select every camera in the org")
+	cmd.Flags().StringVar(&f.Source, "source", "auto", "Camera source: auto|api|index (auto uses the local index when it exists)")
+	cmd.Flags().IntVar(&f.PageSize, "page-size", 200, "Page size when listing cameras from the API (max 200)")
+}
+
+// cameraInfo is the flattened subset of camera fields most commands display.
+type cameraInfo struct {
+	CameraID string `json:"camera_id"`
+	Name     string `json:"name,omitempty"`
+	Site     string `json:"site,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+func newCameraInfo(c map[string]any, labels *LocalLabels) cameraInfo {
+	id := pickString(c, "camera_id", "cameraId", "cameraID", "id")
+	info := cameraInfo{
+		CameraID: id,
+		Name:     pickString(c, "name", "device_name", "deviceName"),
+		Site:     pickString(c, "site", "site_name", "siteName"),
+		Timezone: pickString(c, "timezone", "time_zone", "timeZone"),
+	}
+	if labels != nil && labels.Cameras != nil {
+		info.Label = labels.Cameras[id]
+	}
+	return info
+}
+
+// DisplayName prefers the local label, then the camera name, then the id.
+func (c cameraInfo) DisplayName() string {
+	return firstNonEmpty(c.Label, c.Name, c.CameraID)
+}
+
+// splitCameraRefs flattens positional args and comma-separated values into a clean list.
+func splitCameraRefs(args []string) []string {
+	var out []string
+	for _, a := range args {
+		for _, p := range strings.Split(a, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// resolveCameraSet loads cameras (from the local index or the API) and applies the selection.
+func resolveCameraSet(client *http.Client, cfg *Config, rf *rootFlags, f cameraSetFlags, refs []string) ([]map[string]any, error) {
+	refs = splitCameraRefs(refs)
+	if strings.TrimSpace(f.Site) == "" && strings.TrimSpace(f.Query) == "" && !f.All && len(refs) == 0 {
+		return nil, errors.New("select cameras with --site, --query, --all, or camera references")
+	}
+
+	idxPath, err := cameraSourceIndex(rf, cfg, f.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	var cams []map[string]any
+	var queryHits map[string]bool
+	if idxPath != "" {
+		all, err := loadCamerasFromIndex(idxPath)
+		if err != nil {
+			return nil, err
+		}
+		cams = all
+		if q := strings.TrimSpace(f.Query); q != "" {
+			res, err := searchCamerasIndex(idxPath, q, 500)
+			if err != nil {
+				return nil, err
+			}
+			queryHits = map[string]bool{}
+			for _, r := range res.Results {
+				queryHits[r.CameraID] = true
+			}
+		}
+	} else {
+		all, err := fetchAllCameras(client, cfg, rf, f.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		cams = all
+	}
+
+	return selectCameras(cams, cfg.Labels, f, refs, queryHits)
+}
+
+// cameraSourceIndex interprets a --source flag: it returns the cameras index path to read from,
+// or "" when cameras should be fetched from the API (source api, or auto without an index).
+func cameraSourceIndex(rf *rootFlags, cfg *Config, source string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case "", "auto":
+		if p, err := camerasIndexPath(*rf, *cfg); err == nil {
+			if _, err := os.Stat(p); err == nil {
+				return p, nil
+			}
+		}
+		return "", nil
+	case "index":
+		p, err := camerasIndexPath(*rf, *cfg)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(p); err != nil {
+			return "", fmt.Errorf("index not found at %s (run: verkcli cameras index build)", p)
+		}
+		return p, nil
+	case "api":
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid --source %q (expected auto, api, or index)", source)
+	}
+}
+
+// selectCameras applies site/query/ref selection to an already-loaded camera list.
+// queryHits, when non-nil, holds camera_ids matched by an index search and replaces the
+// substring match used for API-sourced lists.
+func selectCameras(cams []map[string]any, labels *LocalLabels, f cameraSetFlags, refs []string, queryHits map[string]bool) ([]map[string]any, error) {
+	site := strings.TrimSpace(f.Site)
+	query := strings.TrimSpace(f.Query)
+
+	picked := map[string]bool{}
+	if f.All {
+		for _, c := range cams {
+			picked[pickString(c, "camera_id", "cameraId", "cameraID", "id")] = true
+		}
+	}
+	if site != "" || query != "" {
+		var substr map[string]bool
+		if query != "" && queryHits == nil {
+			substr = map[string]bool{}
+			for _, c := range filterCameras(cams, "", query, labels) {
+				substr[pickString(c, "camera_id", "cameraId", "cameraID", "id")] = true
+			}
+		}
+		for _, c := range cams {
+			info := newCameraInfo(c, labels)
+			if site != "" && !strings.EqualFold(strings.TrimSpace(info.Site), site) {
+				continue
+			}
+			if query != "" {
+				if queryHits != nil && !queryHits[info.CameraID] {
+					continue
+				}
+				if substr != nil && !substr[info.CameraID] {
+					continue
+				}
+			}
+			picked[info.CameraID] = true
+		}
+	}
+	for _, ref := range refs {
+		id, err := resolveCameraRef(cams, labels, ref)
+		if err != nil {
+			return nil, err
+		}
+		picked[id] = true
+	}
+
+	out := make([]map[string]any, 0, len(picked))
+	for _, c := range cams {
+		id := pickString(c, "camera_id", "cameraId", "cameraID", "id")
+		if id != "" && picked[id] {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no cameras matched the selection")
+	}
+	return out, nil
+}
+
+// errAmbiguousCameraRef is wrapped by errors for references matching more than one camera.
+var errAmbiguousCameraRef = errors.New("ambiguous")
+
+// resolveCameraRef maps a user-supplied reference to a camera_id. Exact ids win, then local
+// labels, then camera names (both case-insensitive). Ambiguous labels/names are rejected.
+func resolveCameraRef(cams []map[string]any, labels *LocalLabels, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	for _, c := range cams {
+		if pickString(c, "camera_id", "cameraId", "cameraID", "id") == ref {
+			return ref, nil
+		}
+	}
+	for _, field := range []string{"label", "name"} {
+		var matches []string
+		for _, c := range cams {
+			info := newCameraInfo(c, labels)
+			v := info.Name
+			if field == "label" {
+				v = info.Label
+			}
+			if v != "" && strings.EqualFold(strings.TrimSpace(v), ref) {
+				matches = append(matches, info.CameraID)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			sort.Strings(matches)
+			return "", fmt.Errorf("camera reference %q is %w (%s matches: %s)", ref, errAmbiguousCameraRef, field, strings.Join(matches, ", "))
+		}
+	}
+	return "", fmt.Errorf("camera reference %q not found", ref)
+}
+
+// resolveCameraArg maps a single --camera reference to a camera_id without listing the whole org:
+// local labels are checked first, then the local index (id, label, name). Anything else is
+// assumed to already be a camera_id.
+func resolveCameraArg(rf rootFlags, cfg Config, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", errors.New("--camera is required")
+	}
+	if cfg.Labels != nil {
+		if _, ok := cfg.Labels.Cameras[ref]; ok {
+			return ref, nil
+		}
+		var matches []string
+		for id, label := range cfg.Labels.Cameras {
+			if strings.EqualFold(strings.TrimSpace(label), ref) {
+				matches = append(matches, id)
+			}
+		}
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			sort.Strings(matches)
+			return "", fmt.Errorf("camera reference %q is %w (label matches: %s)", ref, errAmbiguousCameraRef, strings.Join(matches, ", "))
+		}
+	}
+	if idxPath, err := camerasIndexPath(rf, cfg); err == nil {
+		if cams, err := loadCamerasFromIndex(idxPath); err == nil {
+			id, err := resolveCameraRef(cams, cfg.Labels, ref)
+			if err == nil {
+				return id, nil
+			}
+			if errors.Is(err, errAmbiguousCameraRef) {
+				return "", err
+			}
+		}
+	}
+	return ref, nil
+}
+
+// lookupCamera returns the raw camera object for a camera_id from the local index when it knows
+// the camera, and from the camera list API otherwise.
+func lookupCamera(client *http.Client, cfg *Config, rf *rootFlags, cameraID string) (map[string]any, error) {
+	if idxPath, err := camerasIndexPath(*rf, *cfg); err == nil {
+		if cams, err := loadCamerasFromIndex(idxPath); err == nil {
+			if c := findCamera(cams, cameraID); c != nil {
+				return c, nil
+			}
+		}
+	}
+	cams, err := fetchAllCameras(client, cfg, rf, 200)
+	if err != nil {
+		return nil, err
+	}
+	if c := findCamera(cams, cameraID); c != nil {
+		return c, nil
+	}
+	return nil, fmt.Errorf("camera %s not found", cameraID)
+}
+
+func findCamera(cams []map[string]any, cameraID string) map[string]any {
+	for _, c := range cams {
+		if pickString(c, "camera_id", "cameraId", "cameraID", "id") == cameraID {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testCameraSetCams() []map[string]any {
+	return []map[string]any{
+		{"camera_id": "cam-1", "name": "North Door", "site": "HQ"},
+		{"camera_id": "cam-2", "name": "Lobby", "site": "hq"},
+		{"camera_id": "cam-3", "name": "Lobby", "site": "Warehouse"},
+		{"camera_id": "cam-4", "name": "Dock", "site": "Warehouse"},
+	}
+}
+
+func cameraIDs(cams []map[string]any) string {
+	ids := make([]string, 0, len(cams))
+	for _, c := range cams {
+		ids = append(ids, pickString(c, "camera_id"))
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSelectCameras_SiteIsCaseInsensitive(t *testing.T) {
+	got, err := selectCameras(testCameraSetCams(), nil, cameraSetFlags{Site: "HQ"}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if cameraIDs(got) != "cam-1,cam-2" {
+		t.Fatalf("got %s", cameraIDs(got))
+	}
+}
+
+func TestSelectCameras_UnionOfSiteAndRefs(t *testing.T) {
+	labels := &LocalLabels{Cameras: map[string]string{"cam-4": "Loading Dock"}}
+	got, err := selectCameras(testCameraSetCams(), labels, cameraSetFlags{Site: "hq"}, []string{"loading dock"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if cameraIDs(got) != "cam-1,cam-2,cam-4" {
+		t.Fatalf("got %s", cameraIDs(got))
+	}
+}
+
+func TestSelectCameras_QueryUsesIndexHits(t *testing.T) {
+	got, err := selectCameras(testCameraSetCams(), nil, cameraSetFlags{Query: "anything"}, nil, map[string]bool{"cam-3": true})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if cameraIDs(got) != "cam-3" {
+		t.Fatalf("got %s", cameraIDs(got))
+	}
+}
+
+func TestSelectCameras_NoMatch(t *testing.T) {
+	if _, err := selectCameras(testCameraSetCams(), nil, cameraSetFlags{Site: "Nowhere"}, nil, nil); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestResolveCameraRef_AmbiguousName(t *testing.T) {
+	_, err := resolveCameraRef(testCameraSetCams(), nil, "lobby")
+	if !errors.Is(err, errAmbiguousCameraRef) || !strings.Contains(err.Error(), `"lobby" is ambiguous (name matches: `) {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+}
+
+func TestResolveCameraArg_AmbiguousLabel(t *testing.T) {
+	cfg := Config{Labels: &LocalLabels{Cameras: map[string]string{"cam-1": "Gate", "cam-2": "gate"}}}
+	if _, err := resolveCameraArg(rootFlags{}, cfg, "GATE"); !errors.Is(err, errAmbiguousCameraRef) {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+}
+
+func TestSplitCameraRefs(t *testing.T) {
+	got := splitCameraRefs([]string{"a, b", "", "c"})
+	if strings.Join(got, "|") != "a|b|c" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// cameraTimezoneValue is the --tz value that interprets naive times in the camera's own timezone.
+const cameraTimezoneValue = "camera"
+
+func isCameraTimezone(tz string) bool {
+	return strings.EqualFold(strings.TrimSpace(tz), cameraTimezoneValue)
+}
+
+// resolveTimezoneFlag returns tz unchanged, unless it is "camera", in which case the camera's
+// IANA timezone is looked up (see lookupCameraTimezone).
+func resolveTimezoneFlag(client *http.Client, cfg *Config, rf *rootFlags, tz, cameraID string) (string, error) {
+	if !isCameraTimezone(tz) {
+		return tz, nil
+	}
+	return lookupCameraTimezone(client, cfg, rf, cameraID)
+}
+
+// lookupCameraTimezone returns the validated IANA timezone of a camera (see lookupCamera).
+func lookupCameraTimezone(client *http.Client, cfg *Config, rf *rootFlags, cameraID string) (string, error) {
+	c, err := lookupCamera(client, cfg, rf, cameraID)
+	if err != nil {
+		return "", fmt.Errorf("--tz camera: %w", err)
+	}
+	tz := newCameraInfo(c, nil).Timezone
+	if tz == "" {
+		return "", fmt.Errorf("--tz camera: camera %s has no timezone; pass an IANA timezone with --tz", cameraID)
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return "", fmt.Errorf("--tz camera: camera %s has an unknown timezone %q; pass an IANA timezone with --tz", cameraID, tz)
+	}
+	return tz, nil
+}
+
+// noteCameraTimezone tells the user which zone and UTC offset naive times were read in. The
+// offset is the one in effect at ts, so it is right on either side of a DST change.
+func noteCameraTimezone(w io.Writer, cameraID, tz string, ts int64) {
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return
+	}
+	t := time.Unix(ts, 0).In(loc)
+	fmt.Fprintf(w, "using camera %s timezone %s (UTC%s): %s\n", cameraID, tz, t.Format("-07:00"), t.Format("2006-01-02 15:04:05 MST"))
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupCameraTimezone_IndexThenAPI(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	apiCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCalls++
+		fmt.Fprint(w, `{"cameras":[{"camera_id":"CAM-API","timezone":"America/New_York"},{"camera_id":"CAM-NOTZ"}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := Config{BaseURL: srv.URL, OrgID: "ORG", Headers: map[string]string{}}
+	rf := &rootFlags{Profile: "default"}
+	idxPath, err := camerasIndexPath(*rf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cams := []map[string]any{{"camera_id": "CAM-IDX", "name": "Door", "timezone": "Europe/Berlin"}}
+	if err := rebuildCamerasIndex(idxPath, *rf, cfg, cams, nil); err != nil {
+		t.Fatalf("rebuildCamerasIndex: %v", err)
+	}
+
+	tz, err := lookupCameraTimezone(srv.Client(), &cfg, rf, "CAM-IDX")
+	if err != nil || tz != "Europe/Berlin" || apiCalls != 0 {
+		t.Fatalf("index lookup: tz=%q err=%v apiCalls=%d", tz, err, apiCalls)
+	}
+	tz, err = lookupCameraTimezone(srv.Client(), &cfg, rf, "CAM-API")
+	if err != nil || tz != "America/New_York" || apiCalls != 1 {
+		t.Fatalf("api lookup: tz=%q err=%v apiCalls=%d", tz, err, apiCalls)
+	}
+	if _, err := lookupCameraTimezone(srv.Client(), &cfg, rf, "CAM-NOTZ"); err == nil || !strings.Contains(err.Error(), "has no timezone") {
+		t.Fatalf("expected missing timezone error, got %v", err)
+	}
+	if _, err := lookupCameraTimezone(srv.Client(), &cfg, rf, "CAM-NOPE"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	// Anything other than "camera" passes through without a lookup.
+	if tz, err := resolveTimezoneFlag(srv.Client(), &cfg, rf, "UTC", "CAM-API"); err != nil || tz != "UTC" || apiCalls != 3 {
+		t.Fatalf("passthrough: tz=%q err=%v apiCalls=%d", tz, err, apiCalls)
+	}
+}
+
+func TestNoteCameraTimezone_OffsetFollowsDST(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	var buf bytes.Buffer
+	noteCameraTimezone(&buf, "CAM", "America/New_York", time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC).Unix())
+	noteCameraTimezone(&buf, "CAM", "America/New_York", time.Date(2026, 7, 15, 14, 0, 0, 0, time.UTC).Unix())
+	want := "using camera CAM timezone America/New_York (UTC-05:00): 2026-02-15 09:00:00 EST\n" +
+		"using camera CAM timezone America/New_York (UTC-04:00): 2026-07-15 10:00:00 EDT\n"
+	if buf.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseThumbnailTimestamp_CameraTimezoneNeedsResolution(t *testing.T) {
+	if _, err := parseThumbnailTimestamp("2026-02-15 09:00", "camera"); err == nil || !strings.Contains(err.Error(), "--tz camera") {
+		t.Fatalf("expected --tz camera error, got %v", err)
+	}
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,8 +31,13 @@ func NewCamerasCmd(rf *rootFlags) *cobra.Command {
 	cmd.AddCommand(newCamerasSearchCmd(rf))
 	cmd.AddCommand(newCamerasIndexCmd(rf))
 	cmd.AddCommand(newCamerasLabelCmd(rf))
+	cmd.AddCommand(newCamerasTagCmd(rf))
+	cmd.AddCommand(newCamerasNoteCmd(rf))
 	cmd.AddCommand(newCamerasThumbnailCmd(rf))
 	cmd.AddCommand(newCamerasFootageCmd(rf))
+	cmd.AddCommand(newCamerasWallCmd(rf))
+	cmd.AddCommand(newCamerasSnapshotCmd(rf))
+	cmd.AddCommand(newCamerasEvidenceCmd(rf))
 	return cmd
 }
 
@@ -97,7 +101,9 @@ func newCamerasListCmd(rf *rootFlags) *cobra.Command {
 					return fmt.Errorf("request failed with status %d", status)
 				}
 				if rf.Output == "json" {
-					if pretty, ok := tryPrettyJSON(b); ok {
+					if blob, ok := withCamerasMeta(b, cfg.Labels); ok {
+						_, _ = out.Write(blob)
+					} else if pretty, ok := tryPrettyJSON(b); ok {
 						_, _ = out.Write(pretty)
 					} else {
 						_, _ = out.Write(b)
@@ -180,6 +186,9 @@ func newCamerasListCmd(rf *rootFlags) *cobra.Command {
 			}
 
 			if rf.Output == "json" {
+				for i, c := range agg {
+					agg[i] = withCameraMeta(c, cfg.Labels)
+				}
 				blob, err := json.MarshalIndent(map[string]any{"cameras": agg}, "", "  ")
 				if err != nil {
 					return err
@@ -213,7 +222,7 @@ func newCamerasListCmd(rf *rootFlags) *cobra.Command {
 	cmd.Flags().BoolVar(&wide, "wide", false, "Include more columns in text output")
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "Output JSON (same as --output json)")
 	cmd.Flags().StringVar(&cameraID, "camera-id", "", "Filter by camera ID (exact match)")
-	cmd.Flags().StringVar(&q, "q", "", "Filter by substring match across id/name/site/label")
+	cmd.Flags().StringVar(&q, "q", "", "Filter by substring match across id/name/site/label/tags")
 	return cmd
 }
 
@@ -288,7 +297,7 @@ func newCamerasGetCmd(rf *rootFlags) *cobra.Command {
 					}
 
 					if rf.Output == "json" {
-						blob, err := json.MarshalIndent(c, "", "  ")
+						blob, err := json.MarshalIndent(withCameraMeta(c, cfg.Labels), "", "  ")
 						if err != nil {
 							return err
 						}
@@ -309,6 +318,11 @@ func newCamerasGetCmd(rf *rootFlags) *cobra.Command {
 						return nil
 					}
 					fmt.Fprint(out, s)
+					if cfg.Labels != nil {
+						for _, n := range cfg.Labels.Notes[cameraID] {
+							fmt.Fprintf(out, "note %s  %s\n", n.At, n.Text)
+						}
+					}
 					return nil
 				}
 
@@ -335,14 +349,31 @@ func newCamerasLabelCmd(rf *rootFlags) *cobra.Command {
 	cmd.AddCommand(newCamerasLabelSetCmd(rf))
 	cmd.AddCommand(newCamerasLabelRmCmd(rf))
 	cmd.AddCommand(newCamerasLabelListCmd(rf))
+	cmd.AddCommand(newCamerasLabelImportCmd(rf))
+	cmd.AddCommand(newCamerasLabelExportCmd(rf))
+	cmd.AddCommand(newCamerasLabelAutoCmd(rf))
+	cmd.AddCommand(newCamerasLabelPruneCmd(rf))
+	cmd.AddCommand(newCamerasLabelPullCmd(rf))
+	cmd.AddCommand(newCamerasLabelPushCmd(rf))
 	return cmd
 }
 
 func newCamerasLabelSetCmd(rf *rootFlags) *cobra.Command {
+	var force bool
+	var source string
+	var pageSize int
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "set CAMERA_ID LABEL",
 		Short: "Set a local label for a camera",
-		Args:  cobra.ExactArgs(2),
+		Long: strings.TrimSpace(`
+Set a local label for a camera. The camera_id is checked against the cameras index (or the API
+when there is no index, or the index does not know the camera) so that typos do not create labels
+that never match; unknown ids are rejected with the closest existing ids as suggestions. Use
+--force to skip the check, e.g. to label a camera before it is installed.
+`),
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cameraID := strings.TrimSpace(args[0])
 			label := strings.TrimSpace(args[1])
@@ -352,30 +383,40 @@ func newCamerasLabelSetCmd(rf *rootFlags) *cobra.Command {
 			if label == "" {
 				return errors.New("label is empty")
 			}
+			if !force {
+				cfg, err := effectiveConfig(*rf)
+				if err != nil {
+					return err
+				}
+				if err := checkLabelCameraID(&http.Client{Timeout: timeout}, &cfg, rf, source, pageSize, cameraID); err != nil {
+					return err
+				}
+			}
 
 			p, err := resolveConfigPath(rf.ConfigPath)
 			if err != nil {
 				return err
 			}
-			cf, err := loadConfig(p)
-			if err != nil {
-				return err
-			}
-
-			profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
-			profile, ok := cf.Profiles[profileName]
-			if !ok {
-				return fmt.Errorf("profile %q not found in %s", profileName, p)
-			}
-			if profile.Labels == nil {
-				profile.Labels = &LocalLabels{Cameras: map[string]string{}}
-			} else if profile.Labels.Cameras == nil {
-				profile.Labels.Cameras = map[string]string{}
-			}
+			err = updateConfig(p, func(cf *ConfigFile) error {
+				profileName, err := selectedProfileName(*rf, *cf)
+				if err != nil {
+					return err
+				}
+				profile, ok := cf.Profiles[profileName]
+				if !ok {
+					return fmt.Errorf("profile %q not found in %s", profileName, p)
+				}
+				if profile.Labels == nil {
+					profile.Labels = &LocalLabels{Cameras: map[string]string{}}
+				} else if profile.Labels.Cameras == nil {
+					profile.Labels.Cameras = map[string]string{}
+				}
 
-			profile.Labels.Cameras[cameraID] = label
-			cf.Profiles[profileName] = profile
-			if err := writeConfig(p, cf); err != nil {
+				profile.Labels.Cameras[cameraID] = label
+				cf.Profiles[profileName] = profile
+				return nil
+			})
+			if err != nil {
 				return err
 			}
 
@@ -390,6 +431,10 @@ func newCamerasLabelSetCmd(rf *rootFlags) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&force, "force", false, "Set the label without checking that the camera exists")
+	cmd.Flags().StringVar(&source, "source", "auto", "Where to check the camera_id: auto (index, then API), api, or index")
+	cmd.Flags().IntVar(&pageSize, "page-size", 200, "Page size when listing cameras from the API (max 200)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "HTTP timeout")
 	return cmd
 }
 
@@ -408,29 +453,45 @@ func newCamerasLabelRmCmd(rf *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			cf, err := loadConfig(p)
+			// What the camera is labelled once the profile's own label is gone: a label from a
+			// parent, the defaults or the project config can only be removed where it is set.
+			inherited, src, err := resolveInherited(*rf, func(l *LocalLabels) { delete(l.Cameras, cameraID) })
 			if err != nil {
 				return err
 			}
-
-			profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
-			profile, ok := cf.Profiles[profileName]
-			if !ok {
-				return fmt.Errorf("profile %q not found in %s", profileName, p)
+			var fallback *string
+			if inherited.Labels != nil {
+				if l, ok := inherited.Labels.Cameras[cameraID]; ok {
+					fallback = &l
+				}
 			}
+			err = updateConfig(p, func(cf *ConfigFile) error {
+				profileName, err := selectedProfileName(*rf, *cf)
+				if err != nil {
+					return err
+				}
+				profile, ok := cf.Profiles[profileName]
+				if !ok {
+					return fmt.Errorf("profile %q not found in %s", profileName, p)
+				}
+				if _, own := profileLabels(profile)[cameraID]; !own && fallback != nil {
+					return fmt.Errorf("label of %s comes from %s; remove it there", cameraID, src["labels.cameras."+cameraID])
+				}
 
-			if profile.Labels != nil && profile.Labels.Cameras != nil {
-				delete(profile.Labels.Cameras, cameraID)
-			}
-			cf.Profiles[profileName] = profile
-			if err := writeConfig(p, cf); err != nil {
+				if profile.Labels != nil && profile.Labels.Cameras != nil {
+					delete(profile.Labels.Cameras, cameraID)
+				}
+				cf.Profiles[profileName] = profile
+				return nil
+			})
+			if err != nil {
 				return err
 			}
 
 			// Best-effort: keep the local search index in sync if it exists.
 			if cfg, err := effectiveConfig(*rf); err == nil {
 				if idxPath, err := camerasIndexPath(*rf, cfg); err == nil {
-					tryUpdateIndexLabel(idxPath, cameraID, nil)
+					tryUpdateIndexLabel(idxPath, cameraID, fallback)
 				}
 			}
 
@@ -454,7 +515,10 @@ func newCamerasLabelListCmd(rf *rootFlags) *cobra.Command {
 				return err
 			}
 
-			profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
+			profileName, err := selectedProfileName(*rf, cf)
+			if err != nil {
+				return err
+			}
 			profile, ok := cf.Profiles[profileName]
 			if !ok {
 				return fmt.Errorf("profile %q not found in %s", profileName, p)
@@ -488,9 +552,11 @@ type camerasThumbnailFlags struct {
 	Resolution string
 	Timezone   string
 
-	OutPath string
-	View    bool
-	Timeout time.Duration
+	OutPath       string
+	View          bool
+	ViewProtocol  string
+	EmbedMetadata bool
+	Timeout       time.Duration
 }
 
 func newCamerasThumbnailCmd(rf *rootFlags) *cobra.Command {
@@ -509,14 +575,18 @@ when no recent motion exists.
 Behavior:
 - If stdout is not a terminal (redirected/piped), JPEG bytes are written to stdout.
 - If stdout is a terminal, this command will not write raw JPEG bytes to it (it looks like noise).
-  In terminals that support inline images (iTerm2/WezTerm), it will render inline by default.
-  Otherwise, use --out or redirect stdout to a file.
+  In terminals that support inline images (iTerm2/WezTerm, kitty/Ghostty, Sixel terminals such as
+  foot), it will render inline by default. Otherwise, use --out, redirect stdout to a file, or pass
+  --view to draw it with Unicode half blocks.
+- --view-protocol overrides detection (auto|iterm2|kitty|sixel|blocks). Inside tmux, image
+  protocols are wrapped for passthrough (needs "set -g allow-passthrough on").
 `),
 		Example: strings.TrimSpace(`
   verkcli cameras thumbnail --camera-id CAM123
   verkcli cameras thumbnail --camera-id CAM123 --timestamp 1736893300 --resolution hi-res --out thumb.jpg
   verkcli cameras thumbnail --camera-id CAM123 --timestamp 2026-02-15T14:30:00Z --out thumb.jpg
   verkcli cameras thumbnail --camera-id CAM123 --view
+  verkcli cameras thumbnail --camera-id CAM123 --timestamp "10 minutes ago" --embed-metadata --out incident.jpg
 `),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := effectiveConfig(*rf)
@@ -534,85 +604,31 @@ Behavior:
 				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
 			}
 
-			ts, err := parseThumbnailTimestamp(f.Timestamp, f.Timezone)
-			if err != nil {
+			if _, err := resolveViewProtocol(f.ViewProtocol, os.Getenv); err != nil {
 				return err
 			}
 
-			reqURL, err := buildCamerasThumbnailURL(cfg.BaseURL, f.CameraID, ts, f.Resolution)
-			if err != nil {
-				return err
-			}
-
-			req, err := http.NewRequest("GET", reqURL, nil)
-			if err != nil {
-				return err
-			}
-
-			applyDefaultHeaders(req, cfg)
-			if err := applyHeaderFlags(req, rf.Headers); err != nil {
-				return err
-			}
-			applyBestEffortAuth(req, cfg)
-
 			client := &http.Client{Timeout: f.Timeout}
-			start := time.Now()
-			resp, err := client.Do(req)
+			tz, err := resolveTimezoneFlag(client, &cfg, rf, f.Timezone, f.CameraID)
 			if err != nil {
 				return err
 			}
-			defer resp.Body.Close()
-
-			b, err := io.ReadAll(resp.Body)
+			ts, err := parseThumbnailTimestamp(f.Timestamp, tz)
 			if err != nil {
 				return err
 			}
-			if looksLikeHTML(resp.Header.Get("Content-Type"), b) {
-				return fmt.Errorf("received HTML instead of JPEG (check --base-url is https://api(.eu|.au).verkada.com and auth headers x-api-key / x-verkada-auth)")
+			if isCameraTimezone(f.Timezone) {
+				noteCameraTimezone(cmd.ErrOrStderr(), f.CameraID, tz, ts)
 			}
 
-			// Auto-fetch API token if required/expired and retry once.
-			if refreshed, err := maybeRefreshTokenOnAuthError(client, &cfg, rf, resp.StatusCode, b); err != nil {
+			b, hdr, status, err := doCamerasThumbnailRequest(client, &cfg, rf, f.CameraID, ts, f.Resolution)
+			if err != nil {
 				return err
-			} else if refreshed {
-				req2, err := http.NewRequest("GET", reqURL, nil)
-				if err != nil {
-					return err
-				}
-				applyDefaultHeaders(req2, cfg)
-				if err := applyHeaderFlags(req2, rf.Headers); err != nil {
-					return err
-				}
-				applyBestEffortAuth(req2, cfg)
-
-				start2 := time.Now()
-				resp2, err := client.Do(req2)
-				if err != nil {
-					return err
-				}
-				defer resp2.Body.Close()
-
-				b2, err := io.ReadAll(resp2.Body)
-				if err != nil {
-					return err
-				}
-				if looksLikeHTML(resp2.Header.Get("Content-Type"), b2) {
-					return fmt.Errorf("received HTML instead of JPEG (check --base-url is https://api(.eu|.au).verkada.com and auth headers x-api-key / x-verkada-auth)")
-				}
-				if rf.Debug {
-					fmt.Fprintf(cmd.ErrOrStderr(), "HTTP %s %s -> %d (%s)\n", req2.Method, req2.URL.String(), resp2.StatusCode, time.Since(start2))
-				}
-				resp = resp2
-				b = b2
-			}
-
-			if rf.Debug {
-				fmt.Fprintf(cmd.ErrOrStderr(), "HTTP %s %s -> %d (%s)\n", req.Method, req.URL.String(), resp.StatusCode, time.Since(start))
 			}
 
 			// Even if the server doesn't set Content-Type reliably, this endpoint is documented as JPEG bytes.
 			// If it returns JSON on error, surface it to the user.
-			if resp.StatusCode >= 400 || looksLikeJSON(resp.Header.Get("Content-Type"), b) {
+			if status >= 400 || looksLikeJSON(hdr.Get("Content-Type"), b) {
 				// Respect global output setting for JSON/text here.
 				out := cmd.OutOrStdout()
 				if pretty, ok := tryPrettyJSON(b); ok {
@@ -623,17 +639,23 @@ Behavior:
 						fmt.Fprintln(out)
 					}
 				}
-				if resp.StatusCode >= 400 {
-					return fmt.Errorf("request failed with status %d", resp.StatusCode)
+				if status >= 400 {
+					return fmt.Errorf("request failed with status %d", status)
 				}
 				// If it's JSON but 200, still treat as unexpected.
 				return errors.New("unexpected JSON response for thumbnail endpoint")
 			}
 
+			if f.EmbedMetadata {
+				if b, err = embedJPEGMetadata(b, thumbnailMetadataFor(client, &cfg, rf, f.CameraID, ts, f.Resolution, hdr, cmd.ErrOrStderr())); err != nil {
+					return fmt.Errorf("--embed-metadata: %w", err)
+				}
+			}
+
 			// Decide whether to write raw bytes to stdout. Writing JPEG bytes to an interactive terminal is almost
 			// never desired (it looks like "junk"), so prefer inline rendering or require explicit redirection.
 			stdoutIsTTY := isTerminalWriter(cmd.OutOrStdout())
-			inlineSupported := terminalSupportsInlineImages()
+			inlineSupported := terminalSupportsInlineImages() || viewProtocolExplicit(f.ViewProtocol)
 			writeStdout, viewEnabled, err := decideThumbnailOutput(stdoutIsTTY, inlineSupported, f.OutPath, f.View)
 			if err != nil {
 				return err
@@ -654,8 +676,8 @@ Behavior:
 
 			if viewEnabled {
 				// Prefer to render from the bytes we already fetched, regardless of --out.
-				// iTerm2 inline images protocol: https://iterm2.com/documentation-images.html
-				if err := iterm2InlineJPEG(cmd.ErrOrStderr(), b, f.CameraID, ts); err != nil {
+				name := fmt.Sprintf("thumbnail_%s_%d.jpg", f.CameraID, ts)
+				if err := renderInlineImage(cmd.ErrOrStderr(), f.ViewProtocol, b, name); err != nil {
 					return err
 				}
 			}
@@ -665,56 +687,37 @@ Behavior:
 	}
 
 	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (required)")
-	cmd.Flags().StringVar(&f.Timestamp, "timestamp", "", "Timestamp for thumbnail. Accepts Unix seconds (1736893300), RFC3339 (2026-02-15T14:30:00Z), RFC3339 without timezone, or local time (2026-02-15 14:30:00). Omit to use now.")
-	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive timestamps (RFC3339 without timezone and space-separated local time).")
+	cmd.Flags().StringVar(&f.Timestamp, "timestamp", "", "Timestamp for thumbnail: "+timeExprHelp+". Omit to use now.")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive and relative timestamps (local times, named days, -1d); \"camera\" uses the camera's own timezone.")
 	cmd.Flags().StringVar(&f.Resolution, "resolution", "low-res", "Thumbnail resolution: low-res|hi-res")
 	cmd.Flags().StringVarP(&f.OutPath, "out", "o", "", "Write JPEG to file instead of stdout")
-	cmd.Flags().BoolVar(&f.View, "view", false, "Render the image inline in terminal (iTerm2/WezTerm)")
+	cmd.Flags().BoolVar(&f.View, "view", false, "Render the image inline in terminal")
+	cmd.Flags().StringVar(&f.ViewProtocol, "view-protocol", "auto", "Inline image protocol: "+viewProtocolNames())
+	cmd.Flags().BoolVar(&f.EmbedMetadata, "embed-metadata", false, "Embed EXIF/XMP provenance (camera, site, label, times, resolution, verkcli version) in the JPEG")
 	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout")
 
+	cmd.AddCommand(newCamerasThumbnailMosaicCmd(rf))
+	cmd.AddCommand(newCamerasThumbnailSeriesCmd(rf))
+	cmd.AddCommand(newCamerasThumbnailCompareCmd(rf))
+	cmd.AddCommand(newCamerasThumbnailWatchCmd(rf))
 	return cmd
 }
 
+// parseThumbnailTimestamp parses a time expression (see parseTimeExpr) into Unix seconds, using
+// tz for naive and relative values. Empty input means now.
 func parseThumbnailTimestamp(raw string, tz string) (int64, error) {
-	ts := strings.TrimSpace(raw)
-	if ts == "" {
+	if strings.TrimSpace(raw) == "" {
 		return time.Now().Unix(), nil
 	}
-
-	if unix, err := strconv.ParseInt(ts, 10, 64); err == nil {
-		return unix, nil
-	}
-
-	for _, layout := range []string{time.RFC3339} {
-		parsed, err := time.Parse(layout, ts)
-		if err == nil {
-			return parsed.Unix(), nil
-		}
-	}
-
-	zoneLoc, locName, err := parseTimestampLocation(tz)
+	loc, _, err := parseTimestampLocation(tz)
 	if err != nil {
 		return 0, err
 	}
-
-	for _, layout := range []string{
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-	} {
-		parsed, err := time.ParseInLocation(layout, ts, zoneLoc)
-		if err == nil {
-			switch locName {
-			case "", "local":
-				// Keep behavior explicit for default local timezone.
-				return parsed.Unix(), nil
-			default:
-				// Ensure a stable conversion in canonical timezone names.
-				return parsed.In(zoneLoc).Unix(), nil
-			}
-		}
+	t, err := parseTimeExpr(raw, time.Now(), loc)
+	if err != nil {
+		return 0, err
 	}
-
-	return 0, fmt.Errorf("invalid --timestamp value %q (supported: Unix seconds, RFC3339, RFC3339 without timezone, YYYY-MM-DD HH:MM:SS)", raw)
+	return t.Unix(), nil
 }
 
 func parseTimestampLocation(raw string) (*time.Location, string, error) {
@@ -725,6 +728,8 @@ func parseTimestampLocation(raw string) (*time.Location, string, error) {
 		return time.Local, "local", nil
 	case "utc", "z", "utcz", "gmt":
 		return time.UTC, strings.ToLower(locName), nil
+	case cameraTimezoneValue:
+		return nil, "", errors.New("--tz camera needs a single camera and is not supported by this command; pass an IANA timezone instead")
 	default:
 		loc, err := time.LoadLocation(raw)
 		if err != nil {
@@ -742,21 +747,10 @@ func isTerminalWriter(w io.Writer) bool {
 	return term.IsTerminal(int(f.Fd()))
 }
 
+// terminalSupportsInlineImages reports whether the terminal speaks an image protocol, so
+// thumbnails render inline by default (see detectTermGraphics).
 func terminalSupportsInlineImages() bool {
-	// iTerm2 and WezTerm support the iTerm2 inline images protocol.
-	// Avoid attempting this in unknown terminals; escape sequences can be noisy.
-	if os.Getenv("ITERM_SESSION_ID") != "" {
-		return true
-	}
-	if os.Getenv("WEZTERM_PANE") != "" {
-		return true
-	}
-	switch os.Getenv("TERM_PROGRAM") {
-	case "iTerm.app", "WezTerm":
-		return true
-	default:
-		return false
-	}
+	return detectTermGraphics(os.Getenv) != ""
 }
 
 func decideThumbnailOutput(stdoutIsTTY bool, inlineSupported bool, outPath string, viewFlag bool) (writeStdout bool, viewEnabled bool, err error) {
@@ -802,6 +796,82 @@ func buildCamerasThumbnailURL(baseURL, cameraID string, ts int64, resolution str
 	return u.String(), nil
 }
 
+// doCamerasThumbnailRequest fetches a thumbnail, retrying once with a fresh API token when required.
+// It returns the raw body so callers can decide how to surface non-JPEG (JSON error) responses.
+func doCamerasThumbnailRequest(client *http.Client, cfg *Config, rf *rootFlags, cameraID string, ts int64, resolution string) ([]byte, http.Header, int, error) {
+	reqURL, err := buildCamerasThumbnailURL(cfg.BaseURL, cameraID, ts, resolution)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	doOnce := func() ([]byte, http.Header, int, error) {
+		req, err := http.NewRequest("GET", reqURL, nil)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		applyDefaultHeaders(req, *cfg)
+		if err := applyHeaderFlags(req, rf.Headers); err != nil {
+			return nil, nil, 0, err
+		}
+		applyBestEffortAuth(req, *cfg)
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if rf.Debug {
+			fmt.Fprintf(os.Stderr, "HTTP %s %s -> %d (%s)\n", req.Method, req.URL.String(), resp.StatusCode, time.Since(start))
+		}
+		if looksLikeHTML(resp.Header.Get("Content-Type"), b) {
+			return nil, nil, 0, fmt.Errorf("received HTML instead of JPEG (check --base-url is https://api(.eu|.au).verkada.com and auth headers x-api-key / x-verkada-auth)")
+		}
+		return b, resp.Header, resp.StatusCode, nil
+	}
+
+	b, hdr, status, err := doOnce()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Auto-fetch API token if required/expired and retry once.
+	if refreshed, err := maybeRefreshTokenOnAuthError(client, cfg, rf, status, b); err != nil {
+		return nil, nil, 0, err
+	} else if refreshed {
+		return doOnce()
+	}
+	return b, hdr, status, nil
+}
+
+// fetchThumbnailJPEG is the strict variant used by multi-camera commands: anything other than
+// a JPEG body is returned as an error carrying the API message when available. The response
+// headers are returned with the image (Last-Modified tells how old it is).
+func fetchThumbnailJPEG(client *http.Client, cfg *Config, rf *rootFlags, cameraID string, ts int64, resolution string) ([]byte, http.Header, error) {
+	b, hdr, status, err := doCamerasThumbnailRequest(client, cfg, rf, cameraID, ts, resolution)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status >= 400 {
+		if msg, ok := apiErrorMessage(b); ok {
+			return nil, nil, fmt.Errorf("thumbnail request failed with status %d: %s", status, msg)
+		}
+		return nil, nil, fmt.Errorf("thumbnail request failed with status %d", status)
+	}
+	if looksLikeJSON(hdr.Get("Content-Type"), b) {
+		return nil, nil, errors.New("unexpected JSON response for thumbnail endpoint")
+	}
+	if len(b) == 0 {
+		return nil, nil, errors.New("empty thumbnail response")
+	}
+	return b, hdr, nil
+}
+
 func buildCamerasDevicesURL(baseURL string) (string, error) {
 	bu, err := url.Parse(baseURL)
 	if err != nil {
@@ -895,21 +965,6 @@ func doCamerasDevicesRequest(client *http.Client, cfg *Config, rf *rootFlags, pa
 	return b, resp.Header.Get("Content-Type"), resp.StatusCode, nil
 }
 
-func iterm2InlineJPEG(w io.Writer, jpeg []byte, cameraID string, ts int64) error {
-	if len(jpeg) == 0 {
-		return errors.New("empty image")
-	}
-	name := fmt.Sprintf("thumbnail_%s_%d.jpg", cameraID, ts)
-	b64 := base64.StdEncoding.EncodeToString(jpeg)
-	// Emit to stderr so stdout can still be redirected to a file cleanly.
-	_, err := fmt.Fprintf(w, "\033]1337;File=name=%s;inline=1;size=%d;preserveAspectRatio=1:%s\a\n",
-		base64.StdEncoding.EncodeToString([]byte(name)),
-		len(jpeg),
-		b64,
-	)
-	return err
-}
-
 func formatCameraListText(body []byte, wide bool, labels *LocalLabels) (string, error) {
 	devs, err := extractDeviceArray(body)
 	if err != nil {
@@ -921,11 +976,11 @@ func formatCameraListText(body []byte, wide bool, labels *LocalLabels) (string,
 
 	var buf bytes.Buffer
 	if wide {
-		fmt.Fprintf(&buf, "%-36s  %-20s  %-28s  %-18s  %-10s  %-14s  %-15s  %-17s  %-10s  %-20s\n",
-			"camera_id", "label", "name", "site", "model", "serial", "local_ip", "mac", "status", "timezone")
+		fmt.Fprintf(&buf, "%-36s  %-20s  %-20s  %-28s  %-18s  %-10s  %-14s  %-15s  %-17s  %-10s  %-20s  %s\n",
+			"camera_id", "label", "tags", "name", "site", "model", "serial", "local_ip", "mac", "status", "timezone", "notes")
 	} else {
-		fmt.Fprintf(&buf, "%-36s  %-20s  %-32s  %-20s  %-10s  %-14s  %-10s\n",
-			"camera_id", "label", "name", "site", "model", "serial", "status")
+		fmt.Fprintf(&buf, "%-36s  %-20s  %-20s  %-32s  %-20s  %-10s  %-14s  %-10s\n",
+			"camera_id", "label", "tags", "name", "site", "model", "serial", "status")
 	}
 	for _, d := range devs {
 		id := pickString(d, "camera_id", "cameraId", "cameraID", "id")
@@ -933,6 +988,7 @@ func formatCameraListText(body []byte, wide bool, labels *LocalLabels) (string,
 		if labels != nil && labels.Cameras != nil {
 			label = labels.Cameras[id]
 		}
+		tags, notes := cameraMetaSummary(labels, id)
 		name := pickString(d, "name", "device_name", "deviceName")
 		site := pickString(d, "site", "site_name", "siteName")
 		model := pickString(d, "model", "device_model", "deviceModel")
@@ -943,9 +999,10 @@ func formatCameraListText(body []byte, wide bool, labels *LocalLabels) (string,
 		tz := pickString(d, "timezone", "time_zone", "timeZone")
 
 		if wide {
-			fmt.Fprintf(&buf, "%-36s  %-20s  %-28s  %-18s  %-10s  %-14s  %-15s  %-17s  %-10s  %-20s\n",
+			fmt.Fprintf(&buf, "%-36s  %-20s  %-20s  %-28s  %-18s  %-10s  %-14s  %-15s  %-17s  %-10s  %-20s  %d\n",
 				trunc(id, 36),
 				trunc(label, 20),
+				trunc(tags, 20),
 				trunc(name, 28),
 				trunc(site, 18),
 				trunc(model, 10),
@@ -954,11 +1011,13 @@ func formatCameraListText(body []byte, wide bool, labels *LocalLabels) (string,
 				trunc(mac, 17),
 				trunc(status, 10),
 				trunc(tz, 20),
+				notes,
 			)
 		} else {
-			fmt.Fprintf(&buf, "%-36s  %-20s  %-32s  %-20s  %-10s  %-14s  %-10s\n",
+			fmt.Fprintf(&buf, "%-36s  %-20s  %-20s  %-32s  %-20s  %-10s  %-14s  %-10s\n",
 				trunc(id, 36),
 				trunc(label, 20),
+				trunc(tags, 20),
 				trunc(name, 32),
 				trunc(site, 20),
 				trunc(model, 10),
@@ -989,7 +1048,8 @@ func filterCameras(cams []map[string]any, cameraID string, q string, labels *Loc
 			continue
 		}
 		if q != "" {
-			hay := strings.ToLower(strings.Join([]string{id, name, site, label}, " "))
+			tags, _ := cameraMetaSummary(labels, id)
+			hay := strings.ToLower(strings.Join([]string{id, name, site, label, tags}, " "))
 			if !strings.Contains(hay, q) {
 				continue
 			}
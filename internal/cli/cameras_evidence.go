@@ -0,0 +1,674 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	evidenceFormat        = "verkcli-evidence/1"
+	evidenceManifestName  = "manifest.json"
+	evidenceSignatureName = "manifest.sig"
+)
+
+type evidenceFile struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	SHA256 string `json:"sha256"`
+	Bytes  int64  `json:"bytes"`
+}
+
+type evidenceRequest struct {
+	Purpose string `json:"purpose"`
+	Method  string `json:"method"`
+	// URL has secrets (the streaming jwt) replaced with REDACTED.
+	URL string `json:"url"`
+}
+
+type evidenceManifest struct {
+	Format         string            `json:"format"`
+	CreatedAt      string            `json:"created_at"`
+	Operator       string            `json:"operator"`
+	VerkcliVersion string            `json:"verkcli_version"`
+	VerkcliCommit  string            `json:"verkcli_commit"`
+	OrgID          string            `json:"org_id"`
+	CameraID       string            `json:"camera_id"`
+	CameraName     string            `json:"camera_name,omitempty"`
+	Site           string            `json:"site,omitempty"`
+	Start          string            `json:"start"`
+	End            string            `json:"end"`
+	Resolution     string            `json:"resolution"`
+	Codec          string            `json:"codec"`
+	Requests       []evidenceRequest `json:"requests"`
+	Files          []evidenceFile    `json:"files"`
+}
+
+// evidenceSignature is written to manifest.sig; it signs the exact bytes of manifest.json.
+type evidenceSignature struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+func newCamerasEvidenceCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evidence",
+		Short: "Export footage bundles with a chain-of-custody manifest, and verify them",
+	}
+	cmd.AddCommand(newCamerasEvidenceExportCmd(rf))
+	cmd.AddCommand(newCamerasEvidenceVerifyCmd(rf))
+	return cmd
+}
+
+type camerasEvidenceExportFlags struct {
+	CameraID   string
+	Start      string
+	End        string
+	Timezone   string
+	Resolution string
+	Codec      string
+	OutDir     string
+	Thumbnails int
+	Operator   string
+	SignKey    string
+	Force      bool
+	Timeout    time.Duration
+}
+
+func newCamerasEvidenceExportCmd(rf *rootFlags) *cobra.Command {
+	var f camerasEvidenceExportFlags
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Download a clip, reference thumbnails and camera details into a hashed bundle",
+		Long: strings.TrimSpace(`
+Writes an evidence bundle to --out:
+
+  footage.mp4        the clip for --start..--end (same path as "footage download"; needs ffmpeg)
+  thumbnails/        --thumbnails reference thumbnails spread evenly over the window
+  camera.json        the camera's raw API object
+  manifest.json      SHA-256 and size of every file, request URLs (jwt redacted), operator,
+                     verkcli version, camera and window
+  manifest.sig       ed25519 signature of manifest.json (only with --sign-key)
+
+--sign-key takes a PKCS#8 PEM ed25519 private key, e.g. from
+"openssl genpkey -algorithm ed25519 -out evidence.key". Check a bundle with "evidence verify".
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras evidence export --camera CAM123 --start "2026-02-15 06:00" --end "2026-02-15 06:20" --tz camera --out case-1042/
+  verkcli cameras evidence export --camera "Front Door" --start -30m --end now --out case-1043/ --sign-key evidence.key
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.CameraID) == "" {
+				return errors.New("--camera is required")
+			}
+			if strings.TrimSpace(f.OutDir) == "" {
+				return errors.New("--out is required")
+			}
+			if strings.TrimSpace(f.Start) == "" || strings.TrimSpace(f.End) == "" {
+				return errors.New("both --start and --end are required")
+			}
+			if f.Thumbnails < 0 {
+				return errors.New("--thumbnails must be >= 0")
+			}
+			var key ed25519.PrivateKey
+			if f.SignKey != "" {
+				if key, err = loadEd25519PrivateKey(f.SignKey); err != nil {
+					return err
+				}
+			}
+			if err := checkEvidenceDir(f.OutDir, f.Force); err != nil {
+				return err
+			}
+			if _, err := exec.LookPath("ffmpeg"); err != nil {
+				return errors.New("ffmpeg not found in PATH; install ffmpeg to download the clip")
+			}
+			cameraID, err := resolveCameraArg(*rf, cfg, f.CameraID)
+			if err != nil {
+				return err
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
+				return err
+			}
+			if strings.TrimSpace(cfg.OrgID) == "" {
+				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
+			}
+			ff := camerasFootageFlags{CameraID: cameraID, Start: f.Start, End: f.End, Timezone: f.Timezone, Resolution: f.Resolution, Codec: f.Codec}
+			startTime, endTime, err := resolveFootageWindow(cmd.ErrOrStderr(), client, &cfg, rf, ff)
+			if err != nil {
+				return err
+			}
+
+			m := evidenceManifest{
+				Format:         evidenceFormat,
+				Operator:       firstNonEmpty(strings.TrimSpace(f.Operator), defaultOperator()),
+				VerkcliVersion: version,
+				VerkcliCommit:  commit,
+				OrgID:          cfg.OrgID,
+				CameraID:       cameraID,
+				Start:          time.Unix(startTime, 0).UTC().Format(time.RFC3339),
+				End:            time.Unix(endTime, 0).UTC().Format(time.RFC3339),
+				Resolution:     f.Resolution,
+				Codec:          f.Codec,
+			}
+			kinds := map[string]string{}
+
+			// Camera details straight from the API (not the local index) so they reflect the export time.
+			devicesURL, err := buildCamerasDevicesURL(cfg.BaseURL)
+			if err != nil {
+				return err
+			}
+			cams, err := fetchAllCameras(client, &cfg, rf, 200)
+			if err != nil {
+				return err
+			}
+			cam := findCamera(cams, cameraID)
+			if cam == nil {
+				return fmt.Errorf("camera %s not found", cameraID)
+			}
+			info := newCameraInfo(cam, nil)
+			m.CameraName, m.Site = info.Name, info.Site
+			m.Requests = append(m.Requests, evidenceRequest{Purpose: "camera", Method: "GET", URL: devicesURL})
+			camJSON, err := json.MarshalIndent(cam, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			// Build the bundle next to --out and move it into place only once it is complete, so a
+			// failed export never touches an earlier bundle.
+			work, err := newEvidenceWorkDir(f.OutDir)
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(work)
+			if err := os.WriteFile(filepath.Join(work, "camera.json"), append(camJSON, '\n'), 0o644); err != nil {
+				return err
+			}
+			kinds["camera.json"] = "camera"
+
+			thumbRes := "hi-res"
+			if f.Resolution == "low_res" {
+				thumbRes = "low-res"
+			}
+			for _, ts := range evidenceThumbnailTimes(startTime, endTime, f.Thumbnails) {
+				u, err := buildCamerasThumbnailURL(cfg.BaseURL, cameraID, ts, thumbRes)
+				if err != nil {
+					return err
+				}
+				b, _, err := fetchThumbnailJPEG(client, &cfg, rf, cameraID, ts, thumbRes)
+				if err != nil {
+					return fmt.Errorf("thumbnail at %s: %w", time.Unix(ts, 0).UTC().Format(time.RFC3339), err)
+				}
+				rel := filepath.ToSlash(filepath.Join("thumbnails", "thumbnail_"+time.Unix(ts, 0).UTC().Format("20060102T150405Z")+".jpg"))
+				if err := os.MkdirAll(filepath.Join(work, "thumbnails"), 0o755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(filepath.Join(work, filepath.FromSlash(rel)), b, 0o644); err != nil {
+					return err
+				}
+				kinds[rel] = "thumbnail"
+				m.Requests = append(m.Requests, evidenceRequest{Purpose: "thumbnail", Method: "GET", URL: u})
+			}
+
+			jwt, err := fetchStreamingJWT(client, cfg, rf)
+			if err != nil {
+				return err
+			}
+			tokenURL, err := buildFootageTokenURL(cfg.BaseURL)
+			if err != nil {
+				return err
+			}
+			streamURL, err := buildFootageStreamM3U8URL(cfg.BaseURL, cfg.OrgID, cameraID, jwt, startTime, endTime, f.Resolution, f.Codec)
+			if err != nil {
+				return err
+			}
+			m.Requests = append(m.Requests,
+				evidenceRequest{Purpose: "footage_token", Method: "GET", URL: tokenURL},
+				evidenceRequest{Purpose: "footage", Method: "GET", URL: redactURLSecrets(streamURL)},
+			)
+			tmpPath, err := writeFootagePlaylist(client, cfg, rf, streamURL)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(tmpPath)
+			c := exec.Command("ffmpeg", footageDownloadFFmpegArgs(tmpPath, filepath.Join(work, "footage.mp4"), true)...)
+			c.Stdout = cmd.ErrOrStderr()
+			c.Stderr = cmd.ErrOrStderr()
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("ffmpeg failed: %w", err)
+			}
+			kinds["footage.mp4"] = "footage"
+
+			for rel, kind := range kinds {
+				ef, err := hashEvidenceFile(work, rel)
+				if err != nil {
+					return err
+				}
+				ef.Kind = kind
+				m.Files = append(m.Files, ef)
+			}
+			sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+			m.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+
+			blob, err := writeEvidenceManifest(work, m, key)
+			if err != nil {
+				return err
+			}
+			if err := replaceEvidenceDir(work, f.OutDir); err != nil {
+				return err
+			}
+			if rf.Output == "json" {
+				_, _ = cmd.OutOrStdout().Write(blob)
+			}
+			signed := ""
+			if key != nil {
+				signed = ", signed"
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d files%s); check with: verkcli cameras evidence verify %s\n", filepath.Join(f.OutDir, evidenceManifestName), len(m.Files), signed, f.OutDir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (required)")
+	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (alias of --camera)")
+	cmd.Flags().StringVar(&f.Start, "start", "", "Clip start: "+timeExprHelp)
+	cmd.Flags().StringVar(&f.End, "end", "", "Clip end (at most 1 hour after --start)")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --start/--end values (\"camera\" uses the camera's own timezone).")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "high_res", "Resolution: low_res|high_res")
+	cmd.Flags().StringVar(&f.Codec, "codec", "hevc", "Codec: hevc|h264 (depending on camera/availability)")
+	cmd.Flags().StringVar(&f.OutDir, "out", "", "Bundle directory; must be empty or not exist, unless --force replaces a bundle (required)")
+	cmd.Flags().IntVar(&f.Thumbnails, "thumbnails", 3, "Reference thumbnails spread over the window")
+	cmd.Flags().StringVar(&f.Operator, "operator", "", "Operator recorded in the manifest (default: user@host)")
+	cmd.Flags().StringVar(&f.SignKey, "sign-key", "", "Sign manifest.json with this ed25519 private key (PKCS#8 PEM)")
+	cmd.Flags().BoolVar(&f.Force, "force", false, "Replace an existing evidence bundle in --out once the new one is complete")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+type evidenceCheck struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // ok|modified|missing|untracked|invalid
+	Detail string `json:"detail,omitempty"`
+}
+
+type evidenceVerifyReport struct {
+	Dir       string          `json:"dir"`
+	CameraID  string          `json:"camera_id"`
+	Operator  string          `json:"operator"`
+	CreatedAt string          `json:"created_at"`
+	Files     []evidenceCheck `json:"files"`
+	// Signature is "valid", "invalid", "unsigned", or "untrusted" (valid, but not by --pub-key).
+	Signature    string `json:"signature"`
+	SignerKeySHA string `json:"signer_key_sha256,omitempty"`
+	OK           bool   `json:"ok"`
+}
+
+func newCamerasEvidenceVerifyCmd(rf *rootFlags) *cobra.Command {
+	var pubKeyPath string
+
+	cmd := &cobra.Command{
+		Use:   "verify DIR",
+		Short: "Re-check the hashes (and signature) of an evidence bundle",
+		Long: strings.TrimSpace(`
+Recomputes SHA-256 and size of every file listed in manifest.json, reports files that were
+modified, removed or added, and checks manifest.sig when present.
+
+Without --pub-key a signature only proves the manifest was not changed after signing by whoever
+holds that key; pass the expected public key (PKIX PEM, e.g. from
+"openssl pkey -in evidence.key -pubout") to also check who signed it. With --pub-key an unsigned
+bundle fails verification.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras evidence verify case-1042/
+  verkcli cameras evidence verify case-1043/ --pub-key evidence.pub
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var pub ed25519.PublicKey
+			if pubKeyPath != "" {
+				var err error
+				if pub, err = loadEd25519PublicKey(pubKeyPath); err != nil {
+					return err
+				}
+			}
+			rep, err := verifyEvidenceBundle(args[0], pub)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if rf.Output == "json" {
+				b, err := json.MarshalIndent(rep, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, _ = out.Write(append(b, '\n'))
+			} else {
+				fmt.Fprintf(out, "bundle: %s (camera %s, created %s by %s)\n", rep.Dir, rep.CameraID, rep.CreatedAt, rep.Operator)
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				for _, c := range rep.Files {
+					fmt.Fprintf(tw, "%s\t%s\t%s\n", strings.ToUpper(c.Status), c.Path, c.Detail)
+				}
+				_ = tw.Flush()
+				sig := rep.Signature
+				if rep.SignerKeySHA != "" {
+					sig += " (key sha256:" + rep.SignerKeySHA + ")"
+				}
+				fmt.Fprintf(out, "signature: %s\n", sig)
+			}
+			if !rep.OK {
+				return errors.New("evidence bundle failed verification")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pubKeyPath, "pub-key", "", "Require manifest.sig to be signed by this ed25519 public key (PKIX PEM)")
+	return cmd
+}
+
+// verifyEvidenceBundle checks every manifest entry against the files in dir. Only problems with
+// reading the manifest itself are returned as errors; file and signature problems are reported.
+func verifyEvidenceBundle(dir string, pub ed25519.PublicKey) (evidenceVerifyReport, error) {
+	rep := evidenceVerifyReport{Dir: dir}
+	blob, err := os.ReadFile(filepath.Join(dir, evidenceManifestName))
+	if err != nil {
+		return rep, err
+	}
+	var m evidenceManifest
+	if err := json.Unmarshal(blob, &m); err != nil {
+		return rep, fmt.Errorf("parse %s: %w", evidenceManifestName, err)
+	}
+	if m.Format != evidenceFormat {
+		return rep, fmt.Errorf("unsupported manifest format %q (expected %s)", m.Format, evidenceFormat)
+	}
+	rep.CameraID, rep.Operator, rep.CreatedAt = m.CameraID, m.Operator, m.CreatedAt
+	rep.OK = true
+
+	listed := map[string]bool{}
+	for _, want := range m.Files {
+		c := evidenceCheck{Path: want.Path, Status: "ok"}
+		clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(want.Path)))
+		switch {
+		case clean != want.Path || filepath.IsAbs(want.Path) || strings.HasPrefix(clean, "../") || clean == ".." || isEvidenceMetaFile(clean):
+			c.Status, c.Detail = "invalid", "path is not a plain relative path inside the bundle"
+		default:
+			listed[clean] = true
+			got, err := hashEvidenceFile(dir, clean)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				c.Status = "missing"
+			case err != nil:
+				c.Status, c.Detail = "invalid", err.Error()
+			case got.SHA256 != want.SHA256 || got.Bytes != want.Bytes:
+				c.Status = "modified"
+				c.Detail = fmt.Sprintf("sha256 %s (%d bytes), manifest %s (%d bytes)", shortHash(got.SHA256), got.Bytes, shortHash(want.SHA256), want.Bytes)
+			}
+		}
+		if c.Status != "ok" {
+			rep.OK = false
+		}
+		rep.Files = append(rep.Files, c)
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !listed[rel] && !isEvidenceMetaFile(rel) {
+			rep.Files = append(rep.Files, evidenceCheck{Path: rel, Status: "untracked", Detail: "not in manifest"})
+			rep.OK = false
+		}
+		return nil
+	})
+	if err != nil {
+		return rep, err
+	}
+
+	rep.Signature = "unsigned"
+	sigBlob, err := os.ReadFile(filepath.Join(dir, evidenceSignatureName))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if pub != nil {
+			rep.OK = false
+		}
+	case err != nil:
+		return rep, err
+	default:
+		signer, ok := checkEvidenceSignature(blob, sigBlob)
+		if !ok {
+			rep.Signature = "invalid"
+			rep.OK = false
+			break
+		}
+		sum := sha256.Sum256(signer)
+		rep.SignerKeySHA = hex.EncodeToString(sum[:])
+		rep.Signature = "valid"
+		if pub != nil && !pub.Equal(signer) {
+			rep.Signature = "untrusted"
+			rep.OK = false
+		}
+	}
+	return rep, nil
+}
+
+// checkEvidenceSignature verifies manifest.sig against the manifest bytes and returns the
+// embedded public key when the signature is valid.
+func checkEvidenceSignature(manifest, sigBlob []byte) (ed25519.PublicKey, bool) {
+	var sig evidenceSignature
+	if err := json.Unmarshal(sigBlob, &sig); err != nil || sig.Algorithm != "ed25519" {
+		return nil, false
+	}
+	key, err := base64.StdEncoding.DecodeString(sig.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, false
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil || !ed25519.Verify(key, manifest, raw) {
+		return nil, false
+	}
+	return key, true
+}
+
+func isEvidenceMetaFile(rel string) bool {
+	return rel == evidenceManifestName || rel == evidenceSignatureName
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+// writeEvidenceManifest writes manifest.json and, with a key, manifest.sig over its exact bytes.
+func writeEvidenceManifest(dir string, m evidenceManifest, key ed25519.PrivateKey) ([]byte, error) {
+	blob, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	blob = append(blob, '\n')
+	if err := os.WriteFile(filepath.Join(dir, evidenceManifestName), blob, 0o644); err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return blob, nil
+	}
+	sig := evidenceSignature{
+		Algorithm: "ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, blob)),
+	}
+	sigBlob, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, evidenceSignatureName), append(sigBlob, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+func hashEvidenceFile(dir, rel string) (evidenceFile, error) {
+	fh, err := os.Open(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		return evidenceFile{}, err
+	}
+	defer fh.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, fh)
+	if err != nil {
+		return evidenceFile{}, err
+	}
+	return evidenceFile{Path: rel, SHA256: hex.EncodeToString(h.Sum(nil)), Bytes: n}, nil
+}
+
+// checkEvidenceDir refuses to export into a non-empty directory, so a new bundle is never mixed
+// into other files. With force a directory holding an earlier bundle may be replaced; other
+// non-empty directories never are. Nothing is changed on disk.
+func checkEvidenceDir(dir string, force bool) error {
+	entries, err := os.ReadDir(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist), err == nil && len(entries) == 0:
+		return nil
+	case err != nil:
+		return err
+	case !force:
+		return fmt.Errorf("%s is not empty (use --force to replace the evidence bundle in it)", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, evidenceManifestName)); err != nil {
+		return fmt.Errorf("%s is not empty and holds no evidence bundle (%s); refusing to replace it", dir, evidenceManifestName)
+	}
+	return nil
+}
+
+// newEvidenceWorkDir creates the directory a bundle for dir is built in: a hidden sibling of
+// dir, so replaceEvidenceDir can rename it into place on the same filesystem.
+func newEvidenceWorkDir(dir string) (string, error) {
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", err
+	}
+	work, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	return work, os.Chmod(work, 0o755)
+}
+
+// replaceEvidenceDir moves the finished bundle in work to dir. An earlier bundle (or empty
+// directory) at dir is moved aside first and only deleted once the new one is in place; if the
+// swap fails it is moved back.
+func replaceEvidenceDir(work, dir string) error {
+	dir = filepath.Clean(dir)
+	if _, err := os.Lstat(dir); errors.Is(err, fs.ErrNotExist) {
+		return os.Rename(work, dir)
+	} else if err != nil {
+		return err
+	}
+	old := work + ".old"
+	if err := os.Rename(dir, old); err != nil {
+		return err
+	}
+	if err := os.Rename(work, dir); err != nil {
+		_ = os.Rename(old, dir)
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// evidenceThumbnailTimes spreads n times evenly over [start, end], both ends included.
+func evidenceThumbnailTimes(start, end int64, n int) []int64 {
+	switch {
+	case n <= 0:
+		return nil
+	case n == 1:
+		return []int64{start + (end-start)/2}
+	}
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = start + (end-start)*int64(i)/int64(n-1)
+	}
+	return out
+}
+
+func defaultOperator() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		name += "@" + host
+	}
+	return name
+}
+
+func loadEd25519PrivateKey(path string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s: expected a PKCS#8 PEM private key (openssl genpkey -algorithm ed25519)", path)
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	ek, ok := k.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 key", path)
+	}
+	return ek, nil
+}
+
+func loadEd25519PublicKey(path string) (ed25519.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("%s: expected a PKIX PEM public key (openssl pkey -pubout)", path)
+	}
+	k, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	ek, ok := k.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 key", path)
+	}
+	return ek, nil
+}
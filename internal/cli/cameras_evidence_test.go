@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvidenceThumbnailTimes(t *testing.T) {
+	if got := evidenceThumbnailTimes(100, 200, 3); !reflect.DeepEqual(got, []int64{100, 150, 200}) {
+		t.Fatalf("3: %v", got)
+	}
+	if got := evidenceThumbnailTimes(100, 200, 1); !reflect.DeepEqual(got, []int64{150}) {
+		t.Fatalf("1: %v", got)
+	}
+	if got := evidenceThumbnailTimes(100, 200, 0); got != nil {
+		t.Fatalf("0: %v", got)
+	}
+}
+
+func writeTestEvidenceBundle(t *testing.T, key ed25519.PrivateKey) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"footage.mp4": "mp4 bytes",
+		"camera.json": `{"camera_id":"CAM"}`,
+		"thumbnails/thumbnail_20260215T140000Z.jpg": "jpeg bytes",
+	}
+	m := evidenceManifest{Format: evidenceFormat, CameraID: "CAM", Operator: "alex@host", CreatedAt: "2026-02-15T15:00:00Z"}
+	for rel, body := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		ef, err := hashEvidenceFile(dir, rel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Files = append(m.Files, ef)
+	}
+	if _, err := writeEvidenceManifest(dir, m, key); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func statuses(rep evidenceVerifyReport) map[string]string {
+	out := map[string]string{}
+	for _, c := range rep.Files {
+		out[c.Path] = c.Status
+	}
+	return out
+}
+
+func TestVerifyEvidenceBundle(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	dir := writeTestEvidenceBundle(t, key)
+	rep, err := verifyEvidenceBundle(dir, pub)
+	if err != nil || !rep.OK || rep.Signature != "valid" || len(rep.Files) != 3 {
+		t.Fatalf("clean bundle: ok=%v sig=%s files=%v err=%v", rep.OK, rep.Signature, statuses(rep), err)
+	}
+	if rep, _ := verifyEvidenceBundle(dir, otherPub); rep.OK || rep.Signature != "untrusted" {
+		t.Fatalf("other key: ok=%v sig=%s", rep.OK, rep.Signature)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "footage.mp4"), []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "camera.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("added later"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rep, err = verifyEvidenceBundle(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"footage.mp4": "modified",
+		"camera.json": "missing",
+		"notes.txt":   "untracked",
+		"thumbnails/thumbnail_20260215T140000Z.jpg": "ok",
+	}
+	if rep.OK || !reflect.DeepEqual(statuses(rep), want) {
+		t.Fatalf("tampered bundle: ok=%v files=%v", rep.OK, statuses(rep))
+	}
+
+	// Editing the manifest itself breaks the signature.
+	mp := filepath.Join(dir, evidenceManifestName)
+	blob, _ := os.ReadFile(mp)
+	if err := os.WriteFile(mp, []byte(strings.Replace(string(blob), "alex@host", "someone@else", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rep, _ := verifyEvidenceBundle(dir, nil); rep.Signature != "invalid" {
+		t.Fatalf("edited manifest: sig=%s", rep.Signature)
+	}
+}
+
+func TestVerifyEvidenceBundle_UnsignedAndUnsafePaths(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	dir := writeTestEvidenceBundle(t, nil)
+	if rep, _ := verifyEvidenceBundle(dir, nil); !rep.OK || rep.Signature != "unsigned" {
+		t.Fatalf("unsigned without --pub-key: ok=%v sig=%s", rep.OK, rep.Signature)
+	}
+	if rep, _ := verifyEvidenceBundle(dir, pub); rep.OK {
+		t.Fatal("unsigned bundle must fail when a public key is required")
+	}
+
+	m := evidenceManifest{Format: evidenceFormat, Files: []evidenceFile{{Path: "../outside.txt"}, {Path: "manifest.json"}}}
+	dir = t.TempDir()
+	if _, err := writeEvidenceManifest(dir, m, nil); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := verifyEvidenceBundle(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.OK || statuses(rep)["../outside.txt"] != "invalid" || statuses(rep)["manifest.json"] != "invalid" {
+		t.Fatalf("unsafe paths: %v", statuses(rep))
+	}
+}
+
+func TestCheckEvidenceDir(t *testing.T) {
+	if err := checkEvidenceDir(filepath.Join(t.TempDir(), "new"), false); err != nil {
+		t.Fatal(err)
+	}
+	bundle := writeTestEvidenceBundle(t, nil)
+	if err := checkEvidenceDir(bundle, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("non-empty without --force: %v", err)
+	}
+	if err := checkEvidenceDir(bundle, true); err != nil {
+		t.Fatalf("--force over a bundle: %v", err)
+	}
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "notes.txt"), []byte("keep"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkEvidenceDir(other, true); err == nil || !strings.Contains(err.Error(), "no evidence bundle") {
+		t.Fatalf("--force over an unrelated directory: %v", err)
+	}
+}
+
+func TestReplaceEvidenceDir(t *testing.T) {
+	bundle := writeTestEvidenceBundle(t, nil)
+	work, err := newEvidenceWorkDir(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, evidenceManifestName), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := replaceEvidenceDir(work, bundle); err != nil {
+		t.Fatal(err)
+	}
+	// The old bundle's files are gone, not mixed into the new one, and no temp dirs remain.
+	if entries, _ := os.ReadDir(bundle); len(entries) != 1 || entries[0].Name() != evidenceManifestName {
+		t.Fatalf("bundle after replace: %v", entries)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(bundle))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			t.Fatalf("work directory left next to the bundle: %s", e.Name())
+		}
+	}
+}
+
+func TestCamerasEvidenceExport_FailedForceKeepsOldBundle(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	// The camera list works; the thumbnail downloads that follow fail.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cameras/v1/devices" {
+			_, _ = w.Write([]byte(`{"cameras":[{"camera_id":"CAM","name":"Door"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer api.Close()
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{BaseURL: api.URL, OrgID: "ORG1", Auth: AuthConfig{APIKey: "key-1234567890", Token: "tok-abcdefghij", TokenAcquiredAt: time.Now().Unix()}}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	bundle := writeTestEvidenceBundle(t, nil)
+	args := []string{"--config", cfgPath, "cameras", "evidence", "export", "--camera", "CAM", "--start", "2026-02-15T14:00:00Z", "--end", "2026-02-15T14:10:00Z", "--out", bundle, "--force"}
+
+	// Without ffmpeg the export fails before anything else, ...
+	t.Setenv("PATH", t.TempDir())
+	if _, err := runProfilesCLI(t, args...); err == nil || !strings.Contains(err.Error(), "ffmpeg") {
+		t.Fatalf("export without ffmpeg: %v", err)
+	}
+	// ... and with it, the download failure comes after the new bundle was started.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "ffmpeg"), []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	if _, err := runProfilesCLI(t, args...); err == nil || !strings.Contains(err.Error(), "thumbnail at") {
+		t.Fatalf("export with failing downloads: %v", err)
+	}
+
+	rep, err := verifyEvidenceBundle(bundle, nil)
+	if err != nil || !rep.OK {
+		t.Fatalf("old bundle damaged: %+v, %v", rep, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(bundle))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			t.Fatalf("work directory left next to the bundle: %s", e.Name())
+		}
+	}
+}
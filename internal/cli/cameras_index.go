@@ -19,7 +19,7 @@ import (
 )
 
 // camerasIndexSchemaVersion is used to detect incompatible on-disk schema changes.
-const camerasIndexSchemaVersion = 1
+const camerasIndexSchemaVersion = 2
 
 func newCamerasIndexCmd(rf *rootFlags) *cobra.Command {
 	cmd := &cobra.Command{
@@ -246,7 +246,10 @@ func camerasIndexPath(rf rootFlags, cfg Config) (string, error) {
 	host = sanitizePathComponent(host)
 	org := sanitizePathComponent(firstNonEmpty(cfg.OrgID, "no-org"))
 
-	profile := selectedProfileNameFromConfig(rf)
+	profile, err := selectedProfileNameFromConfig(rf)
+	if err != nil {
+		return "", err
+	}
 	profile = sanitizePathComponent(firstNonEmpty(profile, "default"))
 
 	dir := filepath.Join(cacheDir, "verkcli", "index", host, org, profile)
@@ -280,17 +283,17 @@ func sanitizePathComponent(s string) string {
 	return out
 }
 
-func selectedProfileNameFromConfig(rf rootFlags) string {
+func selectedProfileNameFromConfig(rf rootFlags) (string, error) {
 	// Mirror selection semantics used by other commands: flag/env > config current_profile > default.
 	p, err := resolveConfigPath(rf.ConfigPath)
 	if err != nil {
-		return firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), "default")
+		return selectedProfileName(rf, ConfigFile{})
 	}
 	cf, err := loadConfig(p)
 	if err != nil {
-		return firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), "default")
+		return selectedProfileName(rf, ConfigFile{})
 	}
-	return firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
+	return selectedProfileName(rf, cf)
 }
 
 func fetchAllCameras(client *http.Client, cfg *Config, rf *rootFlags, pageSize int) ([]map[string]any, error) {
@@ -334,6 +337,10 @@ func fetchAllCameras(client *http.Client, cfg *Config, rf *rootFlags, pageSize i
 }
 
 func rebuildCamerasIndex(path string, rf rootFlags, cfg Config, cams []map[string]any, labels map[string]string) error {
+	profile, err := selectedProfileNameFromConfig(rf)
+	if err != nil {
+		return err
+	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
@@ -361,6 +368,9 @@ func rebuildCamerasIndex(path string, rf rootFlags, cfg Config, cams []map[strin
 	if _, err := tx.Exec(`DELETE FROM labels`); err != nil {
 		return err
 	}
+	if _, err := tx.Exec(`DELETE FROM camera_meta`); err != nil {
+		return err
+	}
 	if _, err := tx.Exec(`DELETE FROM cameras_fts`); err != nil {
 		return err
 	}
@@ -377,7 +387,7 @@ func rebuildCamerasIndex(path string, rf rootFlags, cfg Config, cams []map[strin
 	if _, err := tx.Exec(`INSERT INTO meta(key,value) VALUES('org_id', ?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, cfg.OrgID); err != nil {
 		return err
 	}
-	if _, err := tx.Exec(`INSERT INTO meta(key,value) VALUES('profile', ?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, selectedProfileNameFromConfig(rf)); err != nil {
+	if _, err := tx.Exec(`INSERT INTO meta(key,value) VALUES('profile', ?) ON CONFLICT(key) DO UPDATE SET value=excluded.value`, profile); err != nil {
 		return err
 	}
 
@@ -399,9 +409,18 @@ func rebuildCamerasIndex(path string, rf rootFlags, cfg Config, cams []map[strin
 	}
 	defer lStmt.Close()
 
+	mStmt, err := tx.Prepare(`
+		INSERT INTO camera_meta(camera_id,tags,notes,updated_at)
+		VALUES(?,?,?,?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer mStmt.Close()
+
 	fStmt, err := tx.Prepare(`
-		INSERT INTO cameras_fts(camera_id,name,site,label,model,serial,status,timezone)
-		VALUES(?,?,?,?,?,?,?,?)
+		INSERT INTO cameras_fts(camera_id,name,site,label,model,serial,status,timezone,tags,notes)
+		VALUES(?,?,?,?,?,?,?,?,?,?)
 	`)
 	if err != nil {
 		return err
@@ -436,7 +455,14 @@ func rebuildCamerasIndex(path string, rf rootFlags, cfg Config, cams []map[strin
 			}
 		}
 
-		if _, err := fStmt.Exec(id, name, site, label, model, serial, status, tz); err != nil {
+		tags, notes := indexCameraMeta(cfg.Labels, id)
+		if tags != "" || notes != "" {
+			if _, err := mStmt.Exec(id, tags, notes, now); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fStmt.Exec(id, name, site, label, model, serial, status, tz, tags, notes); err != nil {
 			return err
 		}
 	}
@@ -479,8 +505,37 @@ func initCamerasIndexSchema(db *sql.DB) error {
 	`); err != nil {
 		return err
 	}
-	// Contentless FTS: we manage inserts/deletes directly.
 	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS camera_meta (
+			camera_id TEXT PRIMARY KEY,
+			tags TEXT,
+			notes TEXT,
+			updated_at INTEGER
+		)
+	`); err != nil {
+		return err
+	}
+	// Schema version 1 had no tags/notes columns. FTS5 tables cannot be altered, but every
+	// column can be recomputed from the other tables, so recreate and refill it in place.
+	if _, err := db.Exec(`SELECT tags, notes FROM cameras_fts LIMIT 0`); err != nil && strings.Contains(err.Error(), "no such column") {
+		if _, err := db.Exec(`DROP TABLE cameras_fts`); err != nil {
+			return err
+		}
+		if err := createCamerasFTS(db); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`INSERT INTO cameras_fts(camera_id,name,site,label,model,serial,status,timezone,tags,notes) ` + camerasFTSSelect); err != nil {
+			return err
+		}
+		_, _ = db.Exec(`UPDATE meta SET value=? WHERE key='schema_version'`, strconv.Itoa(camerasIndexSchemaVersion))
+	}
+	return createCamerasFTS(db)
+}
+
+// createCamerasFTS creates the search table. It is contentless: we manage inserts/deletes
+// directly, from the cameras, labels and camera_meta tables (see camerasFTSSelect).
+func createCamerasFTS(db *sql.DB) error {
+	_, err := db.Exec(`
 		CREATE VIRTUAL TABLE IF NOT EXISTS cameras_fts USING fts5(
 			camera_id UNINDEXED,
 			name,
@@ -490,12 +545,39 @@ func initCamerasIndexSchema(db *sql.DB) error {
 			serial,
 			status,
 			timezone,
+			tags,
+			notes,
 			tokenize = 'unicode61'
 		)
-	`); err != nil {
-		return err
+	`)
+	return err
+}
+
+// camerasFTSSelect selects cameras_fts rows from the other tables.
+const camerasFTSSelect = `
+	SELECT c.camera_id,c.name,c.site,COALESCE(l.label,''),c.model,c.serial,c.status,c.timezone,COALESCE(m.tags,''),COALESCE(m.notes,'')
+	FROM cameras c
+	LEFT JOIN labels l ON l.camera_id = c.camera_id
+	LEFT JOIN camera_meta m ON m.camera_id = c.camera_id
+`
+
+// refreshCameraFTS rewrites the search row of one camera after its label, tags or notes changed.
+func refreshCameraFTS(tx *sql.Tx, cameraID string) {
+	_, _ = tx.Exec(`DELETE FROM cameras_fts WHERE camera_id=?`, cameraID)
+	_, _ = tx.Exec(`INSERT INTO cameras_fts(camera_id,name,site,label,model,serial,status,timezone,tags,notes) `+camerasFTSSelect+` WHERE c.camera_id=?`, cameraID)
+}
+
+// indexCameraMeta renders a camera's tags and notes as the text stored in the index: tags
+// space-separated, notes one per line.
+func indexCameraMeta(l *LocalLabels, cameraID string) (tags, notes string) {
+	if l == nil {
+		return "", ""
 	}
-	return nil
+	texts := make([]string, 0, len(l.Notes[cameraID]))
+	for _, n := range l.Notes[cameraID] {
+		texts = append(texts, n.Text)
+	}
+	return strings.Join(l.Tags[cameraID], " "), strings.Join(texts, "\n")
 }
 
 func readCamerasIndexStatus(path string) (camerasIndexStatus, error) {
@@ -545,9 +627,13 @@ func searchCamerasIndex(path string, query string, limit int) (camerasIndexSearc
 		limit = 500
 	}
 
-	fts, err := buildFTSQuery(query)
-	if err != nil {
-		return out, err
+	tags, rest := splitTagFilters(query)
+	var fts string
+	if len(tags) == 0 || strings.TrimSpace(rest) != "" {
+		var err error
+		if fts, err = buildFTSQuery(rest); err != nil {
+			return out, err
+		}
 	}
 
 	db, err := sql.Open("sqlite", path)
@@ -560,14 +646,27 @@ func searchCamerasIndex(path string, query string, limit int) (camerasIndexSearc
 		return out, err
 	}
 
-	rows, err := db.Query(`
-		SELECT c.raw_json, cameras_fts.camera_id, bm25(cameras_fts) AS rank
-		FROM cameras_fts
-		JOIN cameras c ON c.camera_id = cameras_fts.camera_id
-		WHERE cameras_fts MATCH ?
-		ORDER BY rank ASC
-		LIMIT ?
-	`, fts, limit)
+	// Tags are matched exactly against camera_meta (space-separated), so tag:ptz does not
+	// match non-ptz the way an FTS phrase would.
+	var where []string
+	var args []any
+	stmt := `SELECT c.raw_json, c.camera_id, 0 AS rank FROM cameras c`
+	order := `c.camera_id`
+	if fts != "" {
+		stmt = `SELECT c.raw_json, cameras_fts.camera_id, bm25(cameras_fts) AS rank FROM cameras_fts JOIN cameras c ON c.camera_id = cameras_fts.camera_id`
+		where = append(where, `cameras_fts MATCH ?`)
+		args = append(args, fts)
+		order = `rank ASC`
+	}
+	if len(tags) > 0 {
+		stmt += ` JOIN camera_meta m ON m.camera_id = c.camera_id`
+		for _, t := range tags {
+			where = append(where, `instr(' ' || m.tags || ' ', ?) > 0`)
+			args = append(args, " "+t+" ")
+		}
+	}
+	stmt += ` WHERE ` + strings.Join(where, ` AND `) + ` ORDER BY ` + order + ` LIMIT ?`
+	rows, err := db.Query(stmt, append(args, limit)...)
 	if err != nil {
 		return out, err
 	}
@@ -603,7 +702,52 @@ var camerasSearchStopwords = map[string]struct{}{
 	"camera": {}, "cameras": {},
 }
 
+// camerasSearchFields maps query prefixes like "note:cracked" to the cameras_fts column they
+// restrict the term to. tag: is handled separately (see splitTagFilters).
+var camerasSearchFields = map[string]string{"note": "notes"}
+
+// splitTagFilters pulls the tag:NAME terms out of a search query; the rest is full-text.
+func splitTagFilters(q string) (tags []string, rest string) {
+	var words []string
+	for _, word := range strings.Fields(q) {
+		prefix, value, ok := strings.Cut(word, ":")
+		if !ok || !strings.EqualFold(prefix, "tag") {
+			words = append(words, word)
+			continue
+		}
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			tags = append(tags, value)
+		}
+	}
+	return tags, strings.Join(words, " ")
+}
+
 func buildFTSQuery(q string) (string, error) {
+	var fielded []string
+	var rest []string
+	for _, word := range strings.Fields(q) {
+		prefix, value, ok := strings.Cut(word, ":")
+		col, known := camerasSearchFields[strings.ToLower(prefix)]
+		if !ok || !known {
+			rest = append(rest, word)
+			continue
+		}
+		// A multi-token value such as needs-cleaning must match as a phrase.
+		if toks := tokenizeQuery(value); len(toks) > 0 {
+			fielded = append(fielded, col+" : \""+strings.Join(toks, " ")+"\"")
+		}
+	}
+	if len(fielded) > 0 {
+		if len(rest) == 0 {
+			return strings.Join(fielded, " AND "), nil
+		}
+		more, err := buildFTSQuery(strings.Join(rest, " "))
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(append(fielded, more), " AND "), nil
+	}
+
 	toks := tokenizeQuery(q)
 	keep := toks[:0]
 	for _, t := range toks {
@@ -652,10 +796,52 @@ func tokenizeQuery(q string) []string {
 	return parts
 }
 
+// syncIndexLabels applies the label changes between before and after to the cameras index of
+// profile in one transaction (see tryUpdateIndexLabels), for commands that change many labels.
+func syncIndexLabels(rf *rootFlags, profile string, cfg Config, before, after map[string]string) {
+	dir, err := profileIndexDir(rf, profile, cfg)
+	if err != nil {
+		return
+	}
+	changes := map[string]*string{}
+	for id, label := range after {
+		if before[id] != label {
+			changes[id] = &label
+		}
+	}
+	for id := range before {
+		if _, ok := after[id]; !ok {
+			changes[id] = nil
+		}
+	}
+	tryUpdateIndexLabels(filepath.Join(dir, "cameras.sqlite"), changes)
+}
+
+func profileLabels(p Config) map[string]string {
+	if p.Labels == nil {
+		return nil
+	}
+	return p.Labels.Cameras
+}
+
+// setProfileCameraLabels replaces p's primary labels, keeping its tags and notes.
+func setProfileCameraLabels(p *Config, labels map[string]string) {
+	if p.Labels == nil {
+		p.Labels = &LocalLabels{}
+	}
+	p.Labels.Cameras = labels
+}
+
 // tryUpdateIndexLabel best-effort updates the on-disk index when labels change.
 // It must never break normal label operations.
 func tryUpdateIndexLabel(idxPath string, cameraID string, label *string) {
-	if strings.TrimSpace(cameraID) == "" {
+	tryUpdateIndexLabels(idxPath, map[string]*string{cameraID: label})
+}
+
+// tryUpdateIndexLabels is tryUpdateIndexLabel for a batch of cameras (nil removes a label),
+// applied in one transaction.
+func tryUpdateIndexLabels(idxPath string, changes map[string]*string) {
+	if len(changes) == 0 {
 		return
 	}
 	if _, err := os.Stat(idxPath); err != nil {
@@ -677,24 +863,87 @@ func tryUpdateIndexLabel(idxPath string, cameraID string, label *string) {
 	defer func() { _ = tx.Rollback() }()
 
 	now := time.Now().UTC().Unix()
-	if label == nil || strings.TrimSpace(*label) == "" {
-		_, _ = tx.Exec(`DELETE FROM labels WHERE camera_id=?`, cameraID)
-	} else {
-		_, _ = tx.Exec(`INSERT INTO labels(camera_id,label,updated_at) VALUES(?,?,?) ON CONFLICT(camera_id) DO UPDATE SET label=excluded.label, updated_at=excluded.updated_at`, cameraID, strings.TrimSpace(*label), now)
+	for cameraID, label := range changes {
+		if strings.TrimSpace(cameraID) == "" {
+			continue
+		}
+		if label == nil || strings.TrimSpace(*label) == "" {
+			_, _ = tx.Exec(`DELETE FROM labels WHERE camera_id=?`, cameraID)
+		} else {
+			_, _ = tx.Exec(`INSERT INTO labels(camera_id,label,updated_at) VALUES(?,?,?) ON CONFLICT(camera_id) DO UPDATE SET label=excluded.label, updated_at=excluded.updated_at`, cameraID, strings.TrimSpace(*label), now)
+		}
+
+		refreshCameraFTS(tx, cameraID)
 	}
 
-	// Refresh the FTS row for this camera by deleting/re-inserting from cameras + labels.
-	_, _ = tx.Exec(`DELETE FROM cameras_fts WHERE camera_id=?`, cameraID)
-	var newLabel string
-	if label != nil {
-		newLabel = strings.TrimSpace(*label)
+	_ = tx.Commit()
+}
+
+// tryUpdateIndexMeta best-effort updates the tags and notes of one camera in the on-disk index,
+// like tryUpdateIndexLabel.
+func tryUpdateIndexMeta(idxPath string, cameraID string, l *LocalLabels) {
+	if strings.TrimSpace(cameraID) == "" {
+		return
+	}
+	if _, err := os.Stat(idxPath); err != nil {
+		return
+	}
+	db, err := sql.Open("sqlite", idxPath)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+	if err := initCamerasIndexSchema(db); err != nil {
+		return
 	}
-	_, _ = tx.Exec(`
-		INSERT INTO cameras_fts(camera_id,name,site,label,model,serial,status,timezone)
-		SELECT camera_id,name,site,?,model,serial,status,timezone
-		FROM cameras
-		WHERE camera_id=?
-	`, newLabel, cameraID)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if tags, notes := indexCameraMeta(l, cameraID); tags == "" && notes == "" {
+		_, _ = tx.Exec(`DELETE FROM camera_meta WHERE camera_id=?`, cameraID)
+	} else {
+		_, _ = tx.Exec(`INSERT INTO camera_meta(camera_id,tags,notes,updated_at) VALUES(?,?,?,?) ON CONFLICT(camera_id) DO UPDATE SET tags=excluded.tags, notes=excluded.notes, updated_at=excluded.updated_at`, cameraID, tags, notes, time.Now().UTC().Unix())
+	}
+	refreshCameraFTS(tx, cameraID)
 
 	_ = tx.Commit()
 }
+
+// loadCamerasFromIndex returns every indexed camera (raw API objects) ordered by camera_id.
+func loadCamerasFromIndex(path string) ([]map[string]any, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	if err := initCamerasIndexSchema(db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT raw_json FROM cameras ORDER BY camera_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []map[string]any
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var cam map[string]any
+		if err := json.Unmarshal([]byte(raw), &cam); err != nil {
+			continue
+		}
+		out = append(out, cam)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// labelBatchChange is one label added, changed or removed by a bulk label command.
+type labelBatchChange struct {
+	CameraID string `json:"camera_id"`
+	Action   string `json:"action"` // add, change or remove
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+}
+
+type labelBatchResult struct {
+	Profile string             `json:"profile"`
+	Changes []labelBatchChange `json:"changes"`
+	Skipped []string           `json:"skipped,omitempty"` // cameras left alone, e.g. already labeled
+	// SkippedEmpty lists cameras label auto left alone because a template placeholder was empty.
+	SkippedEmpty []string `json:"skipped_empty,omitempty"`
+	DryRun       bool     `json:"dry_run"`
+}
+
+// applyLabelBatch lets edit change the selected profile's labels in place, then writes the config
+// once and updates the cameras index in one transaction. With dryRun nothing is written; the
+// result lists the changes either way.
+func applyLabelBatch(rf *rootFlags, dryRun bool, edit func(labels map[string]string) error) (labelBatchResult, error) {
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return labelBatchResult{}, err
+	}
+	res := labelBatchResult{Changes: []labelBatchChange{}, DryRun: dryRun}
+	var before, after map[string]string
+	err = updateConfig(p, func(cf *ConfigFile) error {
+		name, err := selectedProfileName(*rf, *cf)
+		if err != nil {
+			return err
+		}
+		res.Profile = name
+		prof, ok := cf.Profiles[res.Profile]
+		if !ok {
+			return fmt.Errorf("profile %q not found in %s", res.Profile, p)
+		}
+		before = copyLabels(profileLabels(prof))
+		after = copyLabels(before)
+		if err := edit(after); err != nil {
+			return err
+		}
+		res.Changes = labelBatchChanges(before, after)
+		if dryRun || len(res.Changes) == 0 {
+			return errLabelsUnchanged
+		}
+		setProfileCameraLabels(&prof, after)
+		cf.Profiles[res.Profile] = prof
+		return nil
+	})
+	if errors.Is(err, errLabelsUnchanged) {
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+	if cfg, err := effectiveConfig(*rf); err == nil {
+		syncIndexLabels(rf, res.Profile, cfg, before, after)
+	}
+	return res, nil
+}
+
+func labelBatchChanges(before, after map[string]string) []labelBatchChange {
+	out := []labelBatchChange{}
+	for id, label := range after {
+		old, ok := before[id]
+		switch {
+		case !ok:
+			out = append(out, labelBatchChange{CameraID: id, Action: "add", New: label})
+		case old != label:
+			out = append(out, labelBatchChange{CameraID: id, Action: "change", Old: old, New: label})
+		}
+	}
+	for id, old := range before {
+		if _, ok := after[id]; !ok {
+			out = append(out, labelBatchChange{CameraID: id, Action: "remove", Old: old})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CameraID < out[j].CameraID })
+	return out
+}
+
+func writeLabelBatchResult(cmd *cobra.Command, rf *rootFlags, what string, res labelBatchResult) error {
+	out := cmd.OutOrStdout()
+	if rf.Output == "json" {
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = out.Write(append(b, '\n'))
+		return nil
+	}
+
+	counts := map[string]int{}
+	if len(res.Changes) > 0 {
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACTION\tCAMERA_ID\tOLD\tNEW")
+		for _, c := range res.Changes {
+			counts[c.Action]++
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Action, c.CameraID, firstNonEmpty(c.Old, "-"), firstNonEmpty(c.New, "-"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	suffix := ""
+	if res.DryRun {
+		suffix = " (dry run, nothing written)"
+	}
+	fmt.Fprintf(out, "%s: %d added, %d changed, %d removed%s\n", what, counts["add"], counts["change"], counts["remove"], suffix)
+	if n := len(res.Skipped); n > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped %d already labeled camera(s); use --overwrite to relabel them\n", n)
+	}
+	if n := len(res.SkippedEmpty); n > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipped %d camera(s) with an empty template placeholder; add a fallback such as {site|name}\n", n)
+	}
+	return nil
+}
+
+func newCamerasLabelImportCmd(rf *rootFlags) *cobra.Command {
+	var format string
+	var replace, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Set many camera labels from a CSV, JSON or YAML file",
+		Long: strings.TrimSpace(`
+Reads labels from FILE ("-" for stdin) and applies them to the selected profile with a single
+config write and index update.
+
+CSV files have a camera_id,label header row (as written by label export or a spreadsheet);
+JSON and YAML files hold {"cameras": {camera_id: label}}. The format follows the extension
+unless --format is given. An empty label removes that camera's label; --replace also removes
+labels of cameras not in the file.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras label import new-site.csv --dry-run
+  verkcli cameras label import labels.yaml --replace
+  cat labels.csv | verkcli cameras label import - --format csv
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			f := strings.ToLower(strings.TrimSpace(format))
+			if f == "" {
+				if f = labelFormatFor(name); f == "" {
+					return fmt.Errorf("cannot tell the format of %q from its extension (use --format csv|json|yaml)", name)
+				}
+			}
+			var b []byte
+			var err error
+			if name == "-" {
+				b, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				b, err = os.ReadFile(name)
+			}
+			if err != nil {
+				return err
+			}
+			raw, err := decodeLabels(b, f, name)
+			if err != nil {
+				return err
+			}
+			// Trim once so --replace compares the same keys that are set.
+			labels := make(map[string]string, len(raw))
+			for id, label := range raw {
+				if id = strings.TrimSpace(id); id != "" {
+					labels[id] = strings.TrimSpace(label)
+				}
+			}
+
+			res, err := applyLabelBatch(rf, dryRun, func(cur map[string]string) error {
+				if replace {
+					for id := range cur {
+						if _, ok := labels[id]; !ok {
+							delete(cur, id)
+						}
+					}
+				}
+				for id, label := range labels {
+					setLabel(cur, id, label)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return writeLabelBatchResult(cmd, rf, "label import", res)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Input format: csv|json|yaml (default: from the file extension)")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Remove labels of cameras not listed in the file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without writing")
+	return cmd
+}
+
+func newCamerasLabelExportCmd(rf *rootFlags) *cobra.Command {
+	var format, outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the profile's camera labels as CSV, JSON or YAML",
+		Long: strings.TrimSpace(`
+Writes the selected profile's labels, sorted by camera_id, to stdout or --out. The output can be
+edited in a spreadsheet and applied again with label import.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras label export > labels.csv
+  verkcli cameras label export --format yaml --out labels.yaml
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f := strings.ToLower(strings.TrimSpace(format))
+			if f == "" {
+				f = firstNonEmpty(labelFormatFor(outPath), labelFormatCSV)
+			}
+			p, err := resolveConfigPath(rf.ConfigPath)
+			if err != nil {
+				return err
+			}
+			cf, err := loadConfig(p)
+			if err != nil {
+				return err
+			}
+			name, err := selectedProfileName(*rf, cf)
+			if err != nil {
+				return err
+			}
+			prof, ok := cf.Profiles[name]
+			if !ok {
+				return fmt.Errorf("profile %q not found in %s", name, p)
+			}
+			labels := profileLabels(prof)
+			b, err := encodeLabels(labels, f)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(outPath) == "" || outPath == "-" {
+				_, err = cmd.OutOrStdout().Write(b)
+				return err
+			}
+			if err := writeFileAtomic(outPath, b, 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "wrote %d label(s) to %s\n", len(labels), outPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Output format: csv|json|yaml (default: from --out, else csv)")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write to this file instead of stdout")
+	return cmd
+}
+
+type labelAutoFlags struct {
+	Set       cameraSetFlags
+	Template  string
+	Overwrite bool
+	DryRun    bool
+	Timeout   time.Duration
+}
+
+func newCamerasLabelAutoCmd(rf *rootFlags) *cobra.Command {
+	var f labelAutoFlags
+
+	cmd := &cobra.Command{
+		Use:   "auto [CAMERA...]",
+		Short: "Derive labels for many cameras from a template",
+		Long: strings.TrimSpace(`
+Renders --template for every selected camera and stores the result as its label, with a single
+config write and index update. Cameras that already have a label are skipped unless --overwrite
+is given. Cameras for which any placeholder is empty (after its fallbacks) are skipped too, so a
+missing site never leaves a label like "-Lobby".
+
+Placeholders: {camera_id}, {name}, {site}, {model}, {serial} and {label} (the current label).
+Use {a|b} to fall back to b when a is empty. Run with --dry-run first to review the changes.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras label auto --site "Warehouse 7" --template "{site}-{name}" --dry-run
+  verkcli cameras label auto --all --template "{site} / {name|serial}" --overwrite
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl := strings.TrimSpace(f.Template)
+			if tmpl == "" {
+				return errors.New("--template is required")
+			}
+			if _, err := renderLabelTemplate(tmpl, nil, ""); err != nil {
+				return err
+			}
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			cams, err := resolveCameraSet(&http.Client{Timeout: f.Timeout}, &cfg, rf, f.Set, args)
+			if err != nil {
+				return err
+			}
+
+			var skipped, empty []string
+			res, err := applyLabelBatch(rf, f.DryRun, func(cur map[string]string) error {
+				for _, c := range cams {
+					id := pickString(c, "camera_id", "cameraId", "cameraID", "id")
+					if strings.TrimSpace(id) == "" {
+						continue
+					}
+					if cur[id] != "" && !f.Overwrite {
+						skipped = append(skipped, id)
+						continue
+					}
+					label, err := renderLabelTemplate(tmpl, c, cur[id])
+					if err != nil {
+						return err
+					}
+					if label == "" {
+						empty = append(empty, id)
+						continue
+					}
+					cur[id] = label
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			sort.Strings(skipped)
+			sort.Strings(empty)
+			res.Skipped, res.SkippedEmpty = skipped, empty
+			if err := writeLabelBatchResult(cmd, rf, "label auto", res); err != nil {
+				return err
+			}
+			rendered := map[string]string{}
+			for _, c := range res.Changes {
+				if c.New != "" {
+					rendered[c.CameraID] = c.New
+				}
+			}
+			if dups := duplicateLabels(&LocalLabels{Cameras: rendered}); len(dups) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: duplicate labels cannot be used as camera references: %s\n", strings.Join(dups, "; "))
+			}
+			return nil
+		},
+	}
+
+	addCameraSetFlags(cmd, &f.Set)
+	cmd.Flags().StringVar(&f.Template, "template", "", "Label template, e.g. \"{site}-{name}\" (required)")
+	cmd.Flags().BoolVar(&f.Overwrite, "overwrite", false, "Relabel cameras that already have a label")
+	cmd.Flags().BoolVar(&f.DryRun, "dry-run", false, "Show the changes without writing")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout")
+	return cmd
+}
+
+// renderLabelTemplate expands a label template for camera c (a raw API or index object) whose
+// current label is label. Runs of whitespace collapse to one space. The result is empty when any
+// placeholder is empty after its fallbacks, since the label would keep a dangling separator.
+func renderLabelTemplate(tmpl string, c map[string]any, label string) (string, error) {
+	values := map[string]string{
+		"camera_id": pickString(c, "camera_id", "cameraId", "cameraID", "id"),
+		"name":      pickString(c, "name", "device_name", "deviceName"),
+		"site":      pickString(c, "site", "site_name", "siteName"),
+		"model":     pickString(c, "model", "device_model", "deviceModel"),
+		"serial":    pickString(c, "serial", "serial_number", "serialNumber"),
+		"label":     label,
+	}
+	missing := false
+	out, err := expandTemplate(tmpl, func(expr string) (string, error) {
+		v, err := templateValue(expr, values)
+		missing = missing || strings.TrimSpace(v) == ""
+		return v, err
+	})
+	if err != nil || missing {
+		return "", err
+	}
+	return strings.Join(strings.Fields(out), " "), nil
+}
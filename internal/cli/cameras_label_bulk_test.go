@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCamerasLabelImportExport(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL: "https://api.verkada.com",
+		OrgID:   "ORG1",
+		Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Front", "CAM9": "Old"}},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door", "site": "HQ"},
+		{"camera_id": "CAM2", "name": "Dock cam", "site": "Warehouse"},
+	})
+
+	csvPath := filepath.Join(t.TempDir(), "labels.csv")
+	if err := os.WriteFile(csvPath, []byte("camera_id,label\nCAM1,Lobby\nCAM2,\"Dock, east\"\nCAM9,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "import", csvPath, "--dry-run")
+	if err != nil || !strings.Contains(out, "1 added, 1 changed, 1 removed (dry run") {
+		t.Fatalf("dry run: %v\n%s", err, out)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); got["CAM1"] != "Front" {
+		t.Fatalf("dry run wrote labels: %v", got)
+	}
+
+	if out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "import", csvPath); err != nil {
+		t.Fatalf("import: %v\n%s", err, out)
+	}
+	want := map[string]string{"CAM1": "Lobby", "CAM2": "Dock, east"}
+	if got := mustLoadLabels(t, cfgPath, "prod"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("labels = %v", got)
+	}
+	res, err := searchCamerasIndex(idxPath, "east", 10)
+	if err != nil || len(res.Results) != 1 || res.Results[0].CameraID != "CAM2" {
+		t.Fatalf("index after import = %+v, %v", res, err)
+	}
+
+	for _, format := range []string{"csv", "json", "yaml"} {
+		out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "export", "--format", format)
+		if err != nil {
+			t.Fatalf("export %s: %v", format, err)
+		}
+		got, err := decodeLabels([]byte(out), format, "stdout")
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Fatalf("export %s = %v, %v\n%s", format, got, err, out)
+		}
+	}
+
+	// --replace keeps cameras listed with padded IDs instead of removing them.
+	jsonPath := filepath.Join(t.TempDir(), "labels.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"cameras": {" CAM1 ": " Lobby ", "CAM2": "Dock, east"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "import", jsonPath, "--replace"); err != nil || !strings.Contains(out, "0 added, 0 changed, 0 removed") {
+		t.Fatalf("import --replace: %v\n%s", err, out)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("labels after --replace = %v", got)
+	}
+}
+
+func TestCamerasLabelAuto(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL: "https://api.verkada.com",
+		OrgID:   "ORG1",
+		Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Mine"}},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door", "site": "HQ"},
+		{"camera_id": "CAM2", "name": "Dock  cam", "site": "W7"},
+		{"camera_id": "CAM3", "site": "W7", "serial": "SN3"},
+		{"camera_id": "CAM4", "name": "Lobby"},
+	})
+
+	args := []string{"--config", cfgPath, "--output", "json", "cameras", "label", "auto", "--all", "--source", "index", "--template", "{site}-{name|serial}"}
+	out, err := runProfilesCLI(t, append(args, "--dry-run")...)
+	if err != nil {
+		t.Fatalf("auto --dry-run: %v", err)
+	}
+	var res labelBatchResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Changes) != 2 || res.Changes[0].New != "W7-Dock cam" || res.Changes[1].New != "W7-SN3" || !reflect.DeepEqual(res.Skipped, []string{"CAM1"}) || !reflect.DeepEqual(res.SkippedEmpty, []string{"CAM4"}) {
+		t.Fatalf("dry run = %+v", res)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); len(got) != 1 {
+		t.Fatalf("dry run wrote labels: %v", got)
+	}
+
+	if _, err := runProfilesCLI(t, append(args, "--overwrite")...); err != nil {
+		t.Fatalf("auto: %v", err)
+	}
+	want := map[string]string{"CAM1": "HQ-Door", "CAM2": "W7-Dock cam", "CAM3": "W7-SN3"}
+	if got := mustLoadLabels(t, cfgPath, "prod"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("labels = %v", got)
+	}
+
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "auto", "--all", "--source", "index", "--template", "{zone}"); err == nil || !strings.Contains(err.Error(), "unknown template placeholder {zone}") {
+		t.Fatalf("bad placeholder: %v", err)
+	}
+}
+
+func mustBuildLabelTestIndex(t *testing.T, cfg Config, cams []map[string]any) string {
+	t.Helper()
+	rf := rootFlags{Profile: "prod"}
+	idxPath, err := camerasIndexPath(rf, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(idxPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := rebuildCamerasIndex(idxPath, rf, cfg, cams, profileLabels(cfg)); err != nil {
+		t.Fatal(err)
+	}
+	return idxPath
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxCameraIDSuggestions caps the "did you mean" list for an unknown camera_id.
+const maxCameraIDSuggestions = 3
+
+// orphanLabel is locally stored camera metadata whose camera_id is not in the org.
+type orphanLabel struct {
+	CameraID    string   `json:"camera_id"`
+	Label       string   `json:"label,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Notes       int      `json:"notes,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+type labelPruneResult struct {
+	Profile string        `json:"profile"`
+	Source  string        `json:"source"` // index or api
+	Cameras int           `json:"cameras"`
+	Orphans []orphanLabel `json:"orphans"`
+	Removed bool          `json:"removed"`
+}
+
+func newCamerasLabelPruneCmd(rf *rootFlags) *cobra.Command {
+	var source string
+	var remove bool
+	var pageSize int
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "List (or remove) labels, tags and notes of cameras that are no longer in the org",
+		Long: strings.TrimSpace(`
+Compares the camera_ids that have a local label, tags or notes with the org's cameras and lists
+the ones that no longer exist, e.g. decommissioned cameras or typos, with the closest existing
+camera_ids as suggestions. Nothing is changed unless --remove is given.
+
+The camera list comes from the API by default, since a stale index would make new cameras look
+orphaned; use --source index to check against the local cameras index instead.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras label prune
+  verkcli cameras label prune --remove
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, cfg, err := effectiveProfileConfig(*rf)
+			if err != nil {
+				return err
+			}
+			idxPath, err := cameraSourceIndex(rf, &cfg, source)
+			if err != nil {
+				return err
+			}
+			res := labelPruneResult{Profile: name, Source: "api", Orphans: []orphanLabel{}}
+			var cams []map[string]any
+			if idxPath != "" {
+				res.Source = "index"
+				cams, err = loadCamerasFromIndex(idxPath)
+			} else {
+				cams, err = fetchAllCameras(&http.Client{Timeout: timeout}, &cfg, rf, pageSize)
+			}
+			if err != nil {
+				return err
+			}
+			res.Cameras = len(cams)
+			known := knownCameraIDs(cams)
+
+			p, err := resolveConfigPath(rf.ConfigPath)
+			if err != nil {
+				return err
+			}
+			var before, after map[string]string
+			err = updateConfig(p, func(cf *ConfigFile) error {
+				name, err := selectedProfileName(*rf, *cf)
+				if err != nil {
+					return err
+				}
+				res.Profile = name
+				prof, ok := cf.Profiles[res.Profile]
+				if !ok {
+					return fmt.Errorf("profile %q not found in %s", res.Profile, p)
+				}
+				res.Orphans = orphanLabels(prof.Labels, known)
+				if !remove || len(res.Orphans) == 0 {
+					return errLabelsUnchanged
+				}
+				// An empty camera list is far more likely a wrong org or key than an empty org.
+				if len(known) == 0 {
+					return errors.New("the org has no cameras; refusing to remove every label (check the profile's org and credentials)")
+				}
+				before = copyLabels(profileLabels(prof))
+				after = copyLabels(before)
+				for _, o := range res.Orphans {
+					delete(after, o.CameraID)
+					delete(prof.Labels.Tags, o.CameraID)
+					delete(prof.Labels.Notes, o.CameraID)
+				}
+				setProfileCameraLabels(&prof, after)
+				cf.Profiles[res.Profile] = prof
+				res.Removed = true
+				return nil
+			})
+			if err != nil && !errors.Is(err, errLabelsUnchanged) {
+				return err
+			}
+			if res.Removed {
+				if cfg, err := effectiveConfig(*rf); err == nil {
+					syncIndexLabels(rf, res.Profile, cfg, before, after)
+					if dir, err := profileIndexDir(rf, res.Profile, cfg); err == nil {
+						for _, o := range res.Orphans {
+							tryUpdateIndexMeta(filepath.Join(dir, "cameras.sqlite"), o.CameraID, cfg.Labels)
+						}
+					}
+				}
+			}
+			return writeLabelPruneResult(cmd, rf, res)
+		},
+	}
+	cmd.Flags().StringVar(&source, "source", "api", "Where to read the org's cameras from: api, index, or auto (index if present)")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the orphaned labels, tags and notes from the profile")
+	cmd.Flags().IntVar(&pageSize, "page-size", 200, "Page size when listing cameras from the API (max 200)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "HTTP timeout")
+	return cmd
+}
+
+// orphanLabels returns the cameras in l that have a label, tags or notes but are not in known,
+// ordered by camera_id.
+func orphanLabels(l *LocalLabels, known map[string]bool) []orphanLabel {
+	out := []orphanLabel{}
+	if l == nil {
+		return out
+	}
+	ids := map[string]bool{}
+	for id := range l.Cameras {
+		ids[id] = true
+	}
+	for id := range l.Tags {
+		ids[id] = true
+	}
+	for id := range l.Notes {
+		ids[id] = true
+	}
+	for _, id := range sortedKeys(ids) {
+		if known[id] {
+			continue
+		}
+		out = append(out, orphanLabel{
+			CameraID:    id,
+			Label:       l.Cameras[id],
+			Tags:        l.Tags[id],
+			Notes:       len(l.Notes[id]),
+			Suggestions: suggestCameraIDs(id, known),
+		})
+	}
+	return out
+}
+
+func writeLabelPruneResult(cmd *cobra.Command, rf *rootFlags, res labelPruneResult) error {
+	out := cmd.OutOrStdout()
+	if rf.Output == "json" {
+		return writeIndentedJSON(out, res)
+	}
+	if len(res.Orphans) > 0 {
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CAMERA_ID\tLABEL\tTAGS\tNOTES\tDID_YOU_MEAN")
+		for _, o := range res.Orphans {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", o.CameraID, firstNonEmpty(o.Label, "-"), firstNonEmpty(strings.Join(o.Tags, ","), "-"), o.Notes, firstNonEmpty(strings.Join(o.Suggestions, ","), "-"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case res.Removed:
+		fmt.Fprintf(out, "removed %d orphaned camera(s) from profile %s\n", len(res.Orphans), res.Profile)
+	case len(res.Orphans) > 0:
+		fmt.Fprintf(out, "%d orphaned camera(s) of %d in the org (%s); nothing written\n", len(res.Orphans), res.Cameras, res.Source)
+		fmt.Fprintln(cmd.ErrOrStderr(), "hint: re-add mistyped labels under the suggested camera_id, then drop the orphans with --remove")
+	default:
+		fmt.Fprintf(out, "no orphaned labels (%d cameras in the org, %s)\n", res.Cameras, res.Source)
+	}
+	return nil
+}
+
+// checkLabelCameraID makes sure cameraID exists before a label is stored for it. With source auto
+// an index miss is re-checked against the API, since the index may predate the camera.
+func checkLabelCameraID(client *http.Client, cfg *Config, rf *rootFlags, source string, pageSize int, cameraID string) error {
+	idxPath, err := cameraSourceIndex(rf, cfg, source)
+	if err != nil {
+		return err
+	}
+	var cams []map[string]any
+	if idxPath != "" {
+		cams, err = loadCamerasFromIndex(idxPath)
+		if err != nil {
+			return err
+		}
+		if findCamera(cams, cameraID) != nil {
+			return nil
+		}
+	}
+	if idxPath == "" || strings.ToLower(strings.TrimSpace(source)) != "index" {
+		cams, err = fetchAllCameras(client, cfg, rf, pageSize)
+		if err != nil {
+			return fmt.Errorf("could not check camera %q: %w (use --force to skip the check)", cameraID, err)
+		}
+		if findCamera(cams, cameraID) != nil {
+			return nil
+		}
+	}
+
+	msg := fmt.Sprintf("camera %q not found in the org", cameraID)
+	if s := suggestCameraIDs(cameraID, knownCameraIDs(cams)); len(s) > 0 {
+		msg += fmt.Sprintf("; did you mean %s?", strings.Join(s, ", "))
+	}
+	return errors.New(msg + " (use --force to set the label anyway)")
+}
+
+func knownCameraIDs(cams []map[string]any) map[string]bool {
+	out := make(map[string]bool, len(cams))
+	for _, c := range cams {
+		if id := pickString(c, "camera_id", "cameraId", "cameraID", "id"); id != "" {
+			out[id] = true
+		}
+	}
+	return out
+}
+
+// suggestCameraIDs returns up to maxCameraIDSuggestions known camera_ids close to id, nearest
+// first. Comparison ignores case; the allowed edit distance grows with the length of id so that
+// long ids (UUIDs) tolerate a few typos while short ones only tolerate one.
+func suggestCameraIDs(id string, known map[string]bool) []string {
+	needle := strings.ToLower(strings.TrimSpace(id))
+	limit := max(1, len([]rune(needle))/4)
+	type candidate struct {
+		id   string
+		dist int
+	}
+	var cands []candidate
+	for k := range known {
+		if k == id {
+			continue
+		}
+		if d := editDistance(needle, strings.ToLower(k)); d <= limit {
+			cands = append(cands, candidate{k, d})
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		if cands[i].dist != cands[j].dist {
+			return cands[i].dist < cands[j].dist
+		}
+		return cands[i].id < cands[j].id
+	})
+	var out []string
+	for i := 0; i < len(cands) && i < maxCameraIDSuggestions; i++ {
+		out = append(out, cands[i].id)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b, counted in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
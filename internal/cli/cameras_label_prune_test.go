@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSuggestCameraIDs(t *testing.T) {
+	known := map[string]bool{
+		"CAM12": true, "CAM13": true, "DOCK1": true,
+		"3f2a9c1e-0b7d-4e5a-9c11-2d6f8a7b4e01": true,
+	}
+	cases := map[string][]string{
+		"cam12":                                {"CAM12", "CAM13"},
+		"CAM21":                                nil, // a swap is two edits, too many for a 5 character id
+		"CAM1":                                 {"CAM12", "CAM13"},
+		"3f2a9c1e-0b7d-4e5a-9c11-2d6f8a7b4e0":  {"3f2a9c1e-0b7d-4e5a-9c11-2d6f8a7b4e01"},
+		"3f2a9c1e-0b7d-4e5a-9c11-2d6f8a7bXXXX": {"3f2a9c1e-0b7d-4e5a-9c11-2d6f8a7b4e01"},
+		"GARAGE":                               nil,
+	}
+	for id, want := range cases {
+		if got := suggestCameraIDs(id, known); !reflect.DeepEqual(got, want) {
+			t.Errorf("suggestCameraIDs(%q) = %v, want %v", id, got, want)
+		}
+	}
+	if d := editDistance("kitten", "sitting"); d != 3 {
+		t.Fatalf("editDistance = %d", d)
+	}
+}
+
+func TestCamerasLabelSetChecksCameraID(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{BaseURL: "https://api.verkada.com", OrgID: "ORG1"}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	mustBuildLabelTestIndex(t, cfg, []map[string]any{{"camera_id": "CAM12", "name": "Door"}})
+
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "set", "CAM12", "Lobby", "--source", "index"); err != nil {
+		t.Fatalf("set known camera: %v", err)
+	}
+	_, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "set", "CAM21", "Dock", "--source", "index")
+	if err == nil || !strings.Contains(err.Error(), `camera "CAM21" not found`) || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("set unknown camera: %v", err)
+	}
+	_, err = runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "set", "CAM13", "Dock", "--source", "index")
+	if err == nil || !strings.Contains(err.Error(), "did you mean CAM12?") {
+		t.Fatalf("set mistyped camera: %v", err)
+	}
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "set", "CAM13", "Dock", "--force"); err != nil {
+		t.Fatalf("set --force: %v", err)
+	}
+	want := map[string]string{"CAM12": "Lobby", "CAM13": "Dock"}
+	if got := mustLoadLabels(t, cfgPath, "prod"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("labels = %v", got)
+	}
+}
+
+func TestCamerasLabelPrune(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL: "https://api.verkada.com",
+		OrgID:   "ORG1",
+		Labels: &LocalLabels{
+			Cameras: map[string]string{"CAM1": "Front", "CAM13": "Dock", "OLD7": "Gone"},
+			Tags:    map[string][]string{"CAM1": {"exterior"}, "OLD8": {"ptz"}},
+			Notes:   map[string][]CameraNote{"OLD7": {{At: "2026-01-02T03:04:05Z", Text: "removed"}}},
+		},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door"},
+		{"camera_id": "CAM12", "name": "Dock cam"},
+	})
+
+	out, err := runProfilesCLI(t, "--config", cfgPath, "--output", "json", "cameras", "label", "prune", "--source", "index")
+	if err != nil {
+		t.Fatalf("prune: %v\n%s", err, out)
+	}
+	var res labelPruneResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Removed || res.Cameras != 2 || len(res.Orphans) != 3 {
+		t.Fatalf("prune result = %+v", res)
+	}
+	if o := res.Orphans[0]; o.CameraID != "CAM13" || !reflect.DeepEqual(o.Suggestions, []string{"CAM1", "CAM12"}) {
+		t.Fatalf("typo orphan = %+v", o)
+	}
+	if o := res.Orphans[1]; o.CameraID != "OLD7" || o.Label != "Gone" || o.Notes != 1 {
+		t.Fatalf("decommissioned orphan = %+v", o)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); len(got) != 3 {
+		t.Fatalf("listing wrote labels: %v", got)
+	}
+
+	out, err = runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "prune", "--source", "index", "--remove")
+	if err != nil || !strings.Contains(out, "removed 3 orphaned camera(s)") {
+		t.Fatalf("prune --remove: %v\n%s", err, out)
+	}
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := cf.Profiles["prod"].Labels
+	if !reflect.DeepEqual(l.Cameras, map[string]string{"CAM1": "Front"}) || len(l.Tags) != 1 || len(l.Notes) != 0 {
+		t.Fatalf("labels after prune = %+v", l)
+	}
+	res2, err := searchCamerasIndex(idxPath, "front", 10)
+	if err != nil || len(res2.Results) != 1 || res2.Results[0].CameraID != "CAM1" {
+		t.Fatalf("index after prune = %+v, %v", res2, err)
+	}
+}
+
+func TestCamerasLabelPruneRemovesIndexedMeta(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			fmt.Fprint(w, `{"token":"tok"}`)
+		case "/cameras/v1/devices":
+			fmt.Fprint(w, `{"cameras":[{"camera_id":"CAM1","name":"Door"}]}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL: srv.URL,
+		OrgID:   "ORG1",
+		Auth:    AuthConfig{APIKey: "key"},
+		Labels: &LocalLabels{
+			Cameras: map[string]string{"CAM1": "Front"},
+			Tags:    map[string][]string{"CAM1": {"exterior"}, "OLD8": {"ptz"}},
+			Notes:   map[string][]CameraNote{"OLD7": {{At: "2026-01-02T03:04:05Z", Text: "removed from the roof"}}},
+		},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	// A stale index still lists the decommissioned cameras.
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door"},
+		{"camera_id": "OLD7", "name": "Roof"},
+		{"camera_id": "OLD8", "name": "Yard"},
+	})
+	search := func(q string) []string {
+		t.Helper()
+		res, err := searchCamerasIndex(idxPath, q, 10)
+		if err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		var ids []string
+		for _, r := range res.Results {
+			ids = append(ids, r.CameraID)
+		}
+		return ids
+	}
+	if got := search("tag:ptz"); !reflect.DeepEqual(got, []string{"OLD8"}) {
+		t.Fatalf("tag:ptz before prune = %v", got)
+	}
+
+	out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "prune", "--remove")
+	if err != nil || !strings.Contains(out, "removed 2 orphaned camera(s)") {
+		t.Fatalf("prune --remove: %v\n%s", err, out)
+	}
+	for _, q := range []string{"tag:ptz", "note:roof"} {
+		if got := search(q); len(got) != 0 {
+			t.Fatalf("%s after prune = %v", q, got)
+		}
+	}
+	if got := search("tag:exterior"); !reflect.DeepEqual(got, []string{"CAM1"}) {
+		t.Fatalf("tag:exterior after prune = %v", got)
+	}
+}
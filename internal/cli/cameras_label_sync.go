@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// errLabelsUnchanged makes runLabelSync's config update skip the write.
+var errLabelsUnchanged = errors.New("labels unchanged")
+
+type labelSyncFlags struct {
+	Store   string
+	Force   bool
+	DryRun  bool
+	Timeout time.Duration
+}
+
+// labelSyncResult is the outcome of one pull or push. Applied changes were copied to the other
+// side; Pending ones were made on the destination side and are left for the opposite command.
+type labelSyncResult struct {
+	Store     string        `json:"store"`
+	Direction string        `json:"direction"` // pull or push
+	Applied   []labelChange `json:"applied"`
+	Pending   []labelChange `json:"pending"`
+	Conflicts []labelChange `json:"conflicts"`
+	DryRun    bool          `json:"dry_run"`
+}
+
+func newCamerasLabelPullCmd(rf *rootFlags) *cobra.Command {
+	return newCamerasLabelSyncCmd(rf, "pull", "Copy label changes from the label store into the profile", `
+Fetches labels from the profile's label_store (or --store) and applies the changes made there
+since the last sync to the profile's labels, then updates the local cameras index.
+
+Labels changed on both sides since the last sync are conflicts: nothing is written until they
+are resolved, or --force takes the store's value. Local changes are left for label push.
+`)
+}
+
+func newCamerasLabelPushCmd(rf *rootFlags) *cobra.Command {
+	return newCamerasLabelSyncCmd(rf, "push", "Copy label changes from the profile into the label store", `
+Applies the changes made to the profile's labels since the last sync to the profile's
+label_store (or --store). The write is conditional: if someone else changed the store in the
+meantime, push fails and asks to pull again.
+
+Labels changed on both sides since the last sync are conflicts: nothing is written until they
+are resolved, or --force takes the profile's value. Store changes are left for label pull.
+`)
+}
+
+func newCamerasLabelSyncCmd(rf *rootFlags, direction, short, long string) *cobra.Command {
+	var f labelSyncFlags
+
+	cmd := &cobra.Command{
+		Use:   direction,
+		Short: short,
+		Long: strings.TrimSpace(long + `
+Stores: a .yaml/.yml/.json/.csv file (e.g. kept in git), an http(s) URL serving
+{"cameras": {...}} with ETag/If-Match (bearer token from ` + labelStoreTokenEnv + `), or
+profile:NAME for another profile's labels.
+`),
+		Example: strings.TrimSpace(`
+  verkcli profiles set label_store ~/src/site-ops/labels.yaml
+  verkcli cameras label ` + direction + ` --dry-run
+  verkcli cameras label ` + direction + ` --store https://labels.example.com/v1/acme
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			res, err := runLabelSync(rf, direction, f)
+			if err != nil {
+				return err
+			}
+			if err := writeLabelSyncResult(cmd, rf, res); err != nil {
+				return err
+			}
+			if n := len(res.Conflicts); n > 0 && !f.Force {
+				return fmt.Errorf("%d label conflict(s); nothing was written (rerun with --force to keep the %s value)", n, map[string]string{"pull": "store's", "push": "profile's"}[direction])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.Store, "store", "", "Label store to sync with (default: the profile's label_store)")
+	cmd.Flags().BoolVar(&f.Force, "force", false, "Resolve conflicts in favor of the side being copied from")
+	cmd.Flags().BoolVar(&f.DryRun, "dry-run", false, "Show what would change without writing")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout for URL stores")
+	return cmd
+}
+
+// runLabelSync compares the profile's stored labels with the label store against the last synced
+// state and copies one side's changes to the other (see diffLabels). Labels from a project
+// config are not part of the profile and are never pushed.
+func runLabelSync(rf *rootFlags, direction string, f labelSyncFlags) (labelSyncResult, error) {
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return labelSyncResult{}, err
+	}
+	name, cfg, err := effectiveProfileConfig(*rf)
+	if err != nil {
+		return labelSyncResult{}, err
+	}
+	spec := strings.TrimSpace(firstNonEmpty(f.Store, cfg.LabelStore))
+	if spec == "profile:"+name {
+		return labelSyncResult{}, fmt.Errorf("profile %q cannot sync labels with itself", name)
+	}
+	store, err := parseLabelStore(spec, p, &http.Client{Timeout: f.Timeout})
+	if err != nil {
+		return labelSyncResult{}, err
+	}
+	remote, version, err := store.Load()
+	if err != nil {
+		return labelSyncResult{}, err
+	}
+	statePath, err := labelSyncStatePath(rf, name, cfg)
+	if err != nil {
+		return labelSyncResult{}, err
+	}
+	base := loadLabelSyncBase(statePath, spec)
+
+	res := labelSyncResult{Store: spec, Direction: direction, Applied: []labelChange{}, Pending: []labelChange{}, Conflicts: []labelChange{}, DryRun: f.DryRun}
+	plan := func(local map[string]string) {
+		for _, c := range diffLabels(base, local, remote) {
+			switch {
+			case c.Side == "both" && !f.Force:
+				res.Conflicts = append(res.Conflicts, c)
+			case c.Side == "both", c.Side == "remote" && direction == "pull", c.Side == "local" && direction == "push":
+				res.Applied = append(res.Applied, c)
+			default:
+				res.Pending = append(res.Pending, c)
+			}
+		}
+	}
+
+	if direction == "push" {
+		cf, err := loadConfig(p)
+		if err != nil {
+			return res, err
+		}
+		prof, ok := cf.Profiles[name]
+		if !ok {
+			return res, fmt.Errorf("profile %q not found in %s", name, p)
+		}
+		plan(profileLabels(prof))
+		if f.DryRun || len(res.Conflicts) > 0 {
+			return res, nil
+		}
+		next := copyLabels(remote)
+		for _, c := range res.Applied {
+			setLabel(next, c.CameraID, c.Local)
+		}
+		if len(res.Applied) > 0 {
+			if err := store.Save(next, version); err != nil {
+				return res, err
+			}
+			// A profile store is another profile's labels; keep its cameras index in step.
+			if ps, ok := store.(*configLabelStore); ok {
+				syncIndexLabels(rf, ps.profile, resolvedProfileOrRaw(cf, ps.profile), remote, next)
+			}
+		}
+		return res, saveLabelSyncBase(statePath, spec, pushedBase(base, next, res.Pending))
+	}
+
+	var before, after map[string]string
+	err = updateConfig(p, func(cf *ConfigFile) error {
+		prof, ok := cf.Profiles[name]
+		if !ok {
+			return fmt.Errorf("profile %q not found in %s", name, p)
+		}
+		before = copyLabels(profileLabels(prof))
+		plan(before)
+		if f.DryRun || len(res.Conflicts) > 0 || len(res.Applied) == 0 {
+			return errLabelsUnchanged
+		}
+		after = copyLabels(before)
+		for _, c := range res.Applied {
+			setLabel(after, c.CameraID, c.Remote)
+		}
+		setProfileCameraLabels(&prof, after)
+		cf.Profiles[name] = prof
+		return nil
+	})
+	if errors.Is(err, errLabelsUnchanged) {
+		if f.DryRun || len(res.Conflicts) > 0 {
+			return res, nil
+		}
+		// Already in sync: record it so later edits on either side are not conflicts.
+		return res, saveLabelSyncBase(statePath, spec, remote)
+	}
+	if err != nil {
+		return res, err
+	}
+	syncIndexLabels(rf, name, cfg, before, after)
+	return res, saveLabelSyncBase(statePath, spec, remote)
+}
+
+// pushedBase is the sync base after a push: the new store contents, except for store-side
+// changes that were not pulled yet, which keep their old base value so they stay pending.
+func pushedBase(base, stored map[string]string, pending []labelChange) map[string]string {
+	out := copyLabels(stored)
+	for _, c := range pending {
+		if b, ok := base[c.CameraID]; ok {
+			out[c.CameraID] = b
+		} else {
+			delete(out, c.CameraID)
+		}
+	}
+	return out
+}
+
+func copyLabels(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// setLabel sets or, for an empty label, removes a camera's label.
+func setLabel(m map[string]string, id, label string) {
+	if label == "" {
+		delete(m, id)
+		return
+	}
+	m[id] = label
+}
+
+func writeLabelSyncResult(cmd *cobra.Command, rf *rootFlags, res labelSyncResult) error {
+	out := cmd.OutOrStdout()
+	if rf.Output == "json" {
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = out.Write(append(b, '\n'))
+		return nil
+	}
+
+	if n := len(res.Applied) + len(res.Pending) + len(res.Conflicts); n > 0 {
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ACTION\tCAMERA_ID\tLOCAL\tSTORE")
+		row := func(action string, c labelChange) {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", action, c.CameraID, firstNonEmpty(c.Local, "-"), firstNonEmpty(c.Remote, "-"))
+		}
+		for _, c := range res.Applied {
+			row(res.Direction, c)
+		}
+		for _, c := range res.Conflicts {
+			row("conflict", c)
+		}
+		for _, c := range res.Pending {
+			row("pending", c)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	verb := map[string]string{"pull": "pulled", "push": "pushed"}[res.Direction]
+	if res.DryRun || len(res.Conflicts) > 0 {
+		verb = "would " + res.Direction
+	}
+	fmt.Fprintf(out, "%s %d label change(s) %s %s\n", verb, len(res.Applied), map[string]string{"pull": "from", "push": "to"}[res.Direction], res.Store)
+	if len(res.Pending) > 0 {
+		other := map[string]string{"pull": "push", "push": "pull"}[res.Direction]
+		fmt.Fprintf(cmd.ErrOrStderr(), "hint: %d change(s) marked pending need: verkcli cameras label %s\n", len(res.Pending), other)
+	}
+	return nil
+}
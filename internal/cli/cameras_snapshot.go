@@ -0,0 +1,420 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultSnapshotTemplate = "{site}/{label|name}_{timestamp}.jpg"
+
+type camerasSnapshotFlags struct {
+	Set        cameraSetFlags
+	OutDir     string
+	Template   string
+	Timestamp  string
+	Timezone   string
+	Resolution string
+	Workers    int
+	Rate       float64
+	Timeout    time.Duration
+}
+
+type snapshotResult struct {
+	CameraID string `json:"camera_id"`
+	Name     string `json:"name,omitempty"`
+	Site     string `json:"site,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Path     string `json:"path,omitempty"` // relative to the manifest's directory
+	Status   string `json:"status"`         // ok|error
+	Bytes    int    `json:"bytes"`
+	Error    string `json:"error,omitempty"`
+}
+
+type snapshotManifest struct {
+	GeneratedAt string           `json:"generated_at"`
+	Timestamp   string           `json:"timestamp"`
+	Resolution  string           `json:"resolution"`
+	Template    string           `json:"template"`
+	OK          int              `json:"ok"`
+	Failed      int              `json:"failed"`
+	Cameras     []snapshotResult `json:"cameras"`
+}
+
+func newCamerasSnapshotCmd(rf *rootFlags) *cobra.Command {
+	var f camerasSnapshotFlags
+
+	cmd := &cobra.Command{
+		Use:   "snapshot [CAMERA...]",
+		Short: "Save a thumbnail from many cameras at once",
+		Long: strings.TrimSpace(`
+Fetches a thumbnail from every selected camera concurrently and writes them under --out-dir,
+together with an index.json manifest recording each camera's status, byte size and any API error.
+
+File names come from --template. Placeholders: {camera_id}, {name}, {label}, {site},
+{timestamp} (UTC, 20060102T150405Z) and {date} (2006-01-02). Use {a|b} to fall back to b when a
+is empty, e.g. {label|name}. A "/" in the template creates subdirectories.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras snapshot --site HQ --out-dir snaps/
+  verkcli cameras snapshot --all --out-dir snaps/ --workers 8 --rate 10
+  verkcli cameras snapshot --query lobby --out-dir snaps/ --template "{date}/{camera_id}.jpg"
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.OutDir) == "" {
+				return errors.New("--out-dir is required")
+			}
+			// index.json is written even when every camera fails, so the directory can't wait for
+			// the first thumbnail.
+			if err := os.MkdirAll(f.OutDir, 0o755); err != nil {
+				return err
+			}
+			if f.Resolution != "low-res" && f.Resolution != "hi-res" {
+				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
+			}
+			if f.Workers < 1 {
+				return errors.New("--workers must be at least 1")
+			}
+			if f.Rate < 0 {
+				return errors.New("--rate must not be negative")
+			}
+			tmpl := firstNonEmpty(strings.TrimSpace(f.Template), defaultSnapshotTemplate)
+			if err := validateSnapshotTemplate(tmpl); err != nil {
+				return err
+			}
+
+			var ts int64
+			if strings.TrimSpace(f.Timestamp) != "" {
+				if ts, err = parseThumbnailTimestamp(f.Timestamp, f.Timezone); err != nil {
+					return fmt.Errorf("invalid --timestamp: %w", err)
+				}
+			} else {
+				ts = time.Now().Unix()
+			}
+			at := time.Unix(ts, 0).UTC()
+
+			client := &http.Client{Timeout: f.Timeout}
+			cams, err := resolveCameraSet(client, &cfg, rf, f.Set, args)
+			if err != nil {
+				return err
+			}
+			infos := make([]cameraInfo, 0, len(cams))
+			for _, c := range cams {
+				infos = append(infos, newCameraInfo(c, cfg.Labels))
+			}
+			paths, err := snapshotPaths(f.OutDir, tmpl, infos, at)
+			if err != nil {
+				return err
+			}
+
+			fetchThumb := newSharedThumbnailFetcher(client, cfg, rf, f.Resolution)
+			fetch := func(c cameraInfo) ([]byte, error) {
+				b, _, err := fetchThumb(c.CameraID, ts)
+				return b, err
+			}
+
+			results := runSnapshots(cmd.Context(), infos, paths, f.Workers, newRateLimiter(f.Rate), fetch)
+			relativeSnapshotPaths(f.OutDir, results)
+			m := snapshotManifest{
+				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+				Timestamp:   at.Format(time.RFC3339),
+				Resolution:  f.Resolution,
+				Template:    tmpl,
+				Cameras:     results,
+			}
+			for _, r := range results {
+				if r.Status == "ok" {
+					m.OK++
+				} else {
+					m.Failed++
+				}
+			}
+			blob, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return err
+			}
+			blob = append(blob, '\n')
+			manifestPath := filepath.Join(f.OutDir, "index.json")
+			if err := os.WriteFile(manifestPath, blob, 0o644); err != nil {
+				return err
+			}
+
+			if rf.Output == "json" {
+				_, _ = cmd.OutOrStdout().Write(blob)
+			} else {
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "STATUS\tCAMERA_ID\tNAME\tBYTES\tPATH/ERROR")
+				for _, r := range results {
+					detail := filepath.Join(f.OutDir, filepath.FromSlash(r.Path))
+					if r.Status != "ok" {
+						detail = r.Error
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", r.Status, r.CameraID, trunc(firstNonEmpty(r.Label, r.Name), 32), r.Bytes, detail)
+				}
+				_ = tw.Flush()
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%d ok, %d failed; manifest: %s\n", m.OK, m.Failed, manifestPath)
+			if m.Failed > 0 {
+				return fmt.Errorf("%d of %d snapshots failed", m.Failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	addCameraSetFlags(cmd, &f.Set)
+	cmd.Flags().StringVar(&f.OutDir, "out-dir", "", "Directory for snapshots and index.json (required)")
+	cmd.Flags().StringVar(&f.Template, "template", defaultSnapshotTemplate, "File name template relative to --out-dir")
+	cmd.Flags().StringVar(&f.Timestamp, "timestamp", "", "Thumbnail time (same formats as `cameras thumbnail --timestamp`). Omit to use now.")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for a naive --timestamp.")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "hi-res", "Thumbnail resolution: low-res|hi-res")
+	cmd.Flags().IntVar(&f.Workers, "workers", 4, "Concurrent thumbnail requests")
+	cmd.Flags().Float64Var(&f.Rate, "rate", 5, "Maximum thumbnail requests per second across all workers (0 = unlimited)")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+// validateSnapshotTemplate rejects templates that could escape --out-dir or name no file.
+func validateSnapshotTemplate(tmpl string) error {
+	if filepath.IsAbs(tmpl) || strings.HasPrefix(tmpl, "/") {
+		return errors.New("--template must be relative to --out-dir")
+	}
+	for _, part := range strings.Split(tmpl, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid --template %q (empty, '.' or '..' path segment)", tmpl)
+		}
+	}
+	if strings.Count(tmpl, "{") != strings.Count(tmpl, "}") {
+		return fmt.Errorf("invalid --template %q (unbalanced braces)", tmpl)
+	}
+	_, err := renderSnapshotTemplate(tmpl, cameraInfo{}, time.Time{})
+	return err
+}
+
+// renderSnapshotTemplate expands placeholders. Each value is sanitized into a single path
+// component, so camera names cannot introduce directories or traversal.
+func renderSnapshotTemplate(tmpl string, c cameraInfo, at time.Time) (string, error) {
+	values := map[string]string{
+		"camera_id": c.CameraID,
+		"name":      c.Name,
+		"label":     c.Label,
+		"site":      c.Site,
+	}
+	return expandTemplate(tmpl, func(expr string) (string, error) {
+		switch expr {
+		case "timestamp":
+			return at.UTC().Format("20060102T150405Z"), nil
+		case "date":
+			return at.UTC().Format("2006-01-02"), nil
+		}
+		v, err := templateValue(expr, values)
+		if err != nil {
+			return "", err
+		}
+		v = sanitizePathComponent(v)
+		if v == "." || v == ".." {
+			v = "unknown"
+		}
+		return v, nil
+	})
+}
+
+// expandTemplate replaces each {expr} in tmpl with value(expr).
+func expandTemplate(tmpl string, value func(expr string) (string, error)) (string, error) {
+	var b strings.Builder
+	rest := tmpl
+	for {
+		i := strings.Index(rest, "{")
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		j := strings.Index(rest[i:], "}")
+		if j < 0 {
+			return "", fmt.Errorf("invalid --template %q (unbalanced braces)", tmpl)
+		}
+		b.WriteString(rest[:i])
+		v, err := value(rest[i+1 : i+j])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+		rest = rest[i+j+1:]
+	}
+	return b.String(), nil
+}
+
+// templateValue resolves a placeholder expression like "label|name": the first non-empty value.
+func templateValue(expr string, values map[string]string) (string, error) {
+	for _, alt := range strings.Split(expr, "|") {
+		alt = strings.TrimSpace(alt)
+		val, ok := values[alt]
+		if !ok {
+			return "", fmt.Errorf("unknown template placeholder {%s}", alt)
+		}
+		if strings.TrimSpace(val) != "" {
+			return val, nil
+		}
+	}
+	return "", nil
+}
+
+// snapshotPaths renders the output path for every camera. Cameras that render to the same
+// path (e.g. two cameras named "Lobby") get their camera_id appended.
+func snapshotPaths(outDir, tmpl string, cams []cameraInfo, at time.Time) ([]string, error) {
+	out := make([]string, len(cams))
+	seen := map[string]int{}
+	for i, c := range cams {
+		rel, err := renderSnapshotTemplate(tmpl, c, at)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = filepath.Join(outDir, filepath.FromSlash(rel))
+		seen[out[i]]++
+	}
+	for i, c := range cams {
+		if seen[out[i]] > 1 {
+			ext := filepath.Ext(out[i])
+			out[i] = strings.TrimSuffix(out[i], ext) + "-" + sanitizePathComponent(c.CameraID) + ext
+		}
+	}
+	return out, nil
+}
+
+// runSnapshots fetches thumbnails with a bounded worker pool and writes them to paths.
+// Results keep the order of cams regardless of completion order.
+func runSnapshots(ctx context.Context, cams []cameraInfo, paths []string, workers int, limiter *rateLimiter, fetch func(cameraInfo) ([]byte, error)) []snapshotResult {
+	results := make([]snapshotResult, len(cams))
+	forEachConcurrent(len(cams), workers, func(i int) {
+		results[i] = snapshotOne(ctx, cams[i], paths[i], limiter, fetch)
+	})
+	return results
+}
+
+// forEachConcurrent calls fn(0..n-1) from at most workers goroutines and waits for all calls.
+func forEachConcurrent(n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(workers, 1), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// newSharedThumbnailFetcher returns a fetchThumbnailJPEG wrapper that is safe for concurrent
+// use. Callers share one config so a refreshed API token is reused instead of being fetched
+// again by every worker.
+func newSharedThumbnailFetcher(client *http.Client, cfg Config, rf *rootFlags, resolution string) func(cameraID string, ts int64) ([]byte, http.Header, error) {
+	var mu sync.Mutex
+	return func(cameraID string, ts int64) ([]byte, http.Header, error) {
+		mu.Lock()
+		local := cfg
+		mu.Unlock()
+		b, hdr, err := fetchThumbnailJPEG(client, &local, rf, cameraID, ts, resolution)
+		mu.Lock()
+		if local.Auth.Token != cfg.Auth.Token {
+			cfg.Auth = local.Auth
+		}
+		mu.Unlock()
+		return b, hdr, err
+	}
+}
+
+func snapshotOne(ctx context.Context, c cameraInfo, path string, limiter *rateLimiter, fetch func(cameraInfo) ([]byte, error)) snapshotResult {
+	r := snapshotResult{CameraID: c.CameraID, Name: c.Name, Site: c.Site, Label: c.Label, Status: "error"}
+	if err := limiter.Wait(ctx); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	b, err := fetch(c)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Status, r.Path, r.Bytes = "ok", path, len(b)
+	return r
+}
+
+// relativeSnapshotPaths makes result paths relative to outDir, where the manifest goes, so the
+// directory can be moved or archived and still be compared.
+func relativeSnapshotPaths(outDir string, results []snapshotResult) {
+	for i, r := range results {
+		if r.Path == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(outDir, r.Path); err == nil {
+			results[i].Path = filepath.ToSlash(rel)
+		}
+	}
+}
+
+// rateLimiter spaces calls evenly across all goroutines sharing it. A nil limiter never waits.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
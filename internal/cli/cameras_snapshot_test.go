@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRenderSnapshotTemplate(t *testing.T) {
+	at := time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC)
+	c := cameraInfo{CameraID: "CAM-1", Name: "Lobby / East", Site: "HQ"}
+
+	got, err := renderSnapshotTemplate(defaultSnapshotTemplate, c, at)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if got != "hq/lobby___east_20260215T143000Z.jpg" {
+		t.Fatalf("got %q", got)
+	}
+
+	c.Label = "Front Door"
+	got, _ = renderSnapshotTemplate("{date}/{label|name}-{camera_id}.jpg", c, at)
+	if got != "2026-02-15/front_door-cam-1.jpg" {
+		t.Fatalf("got %q", got)
+	}
+
+	got, _ = renderSnapshotTemplate("{site}/x.jpg", cameraInfo{CameraID: "C"}, at)
+	if got != "unknown/x.jpg" {
+		t.Fatalf("empty site: got %q", got)
+	}
+	got, _ = renderSnapshotTemplate("{name}.jpg", cameraInfo{Name: ".."}, at)
+	if got != "unknown.jpg" {
+		t.Fatalf("traversal name: got %q", got)
+	}
+}
+
+func TestValidateSnapshotTemplate(t *testing.T) {
+	for _, tmpl := range []string{"/abs/{name}.jpg", "../{name}.jpg", "a//b.jpg", "{name.jpg", "{nope}.jpg"} {
+		if err := validateSnapshotTemplate(tmpl); err == nil {
+			t.Fatalf("expected %q to be rejected", tmpl)
+		}
+	}
+	if err := validateSnapshotTemplate(defaultSnapshotTemplate); err != nil {
+		t.Fatalf("default template: %v", err)
+	}
+}
+
+func TestSnapshotPaths_DisambiguatesCollisions(t *testing.T) {
+	at := time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC)
+	cams := []cameraInfo{
+		{CameraID: "A", Name: "Lobby", Site: "HQ"},
+		{CameraID: "B", Name: "Lobby", Site: "HQ"},
+		{CameraID: "C", Name: "Dock", Site: "HQ"},
+	}
+	paths, err := snapshotPaths("out", "{site}/{name}.jpg", cams, at)
+	if err != nil {
+		t.Fatalf("paths: %v", err)
+	}
+	want := []string{
+		filepath.Join("out", "hq", "lobby-a.jpg"),
+		filepath.Join("out", "hq", "lobby-b.jpg"),
+		filepath.Join("out", "hq", "dock.jpg"),
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("paths[%d]=%q want %q", i, paths[i], want[i])
+		}
+	}
+}
+
+func TestRunSnapshots_BoundedAndOrdered(t *testing.T) {
+	dir := t.TempDir()
+	cams := []cameraInfo{{CameraID: "A"}, {CameraID: "B"}, {CameraID: "C"}, {CameraID: "D"}}
+	paths := make([]string, len(cams))
+	for i, c := range cams {
+		paths[i] = filepath.Join(dir, "site", c.CameraID+".jpg")
+	}
+
+	var inflight, peak atomic.Int32
+	fetch := func(c cameraInfo) ([]byte, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if c.CameraID == "C" {
+			return nil, errors.New("thumbnail request failed with status 404: camera not found")
+		}
+		return []byte("jpeg-" + c.CameraID), nil
+	}
+
+	results := runSnapshots(context.Background(), cams, paths, 2, newRateLimiter(0), fetch)
+	if peak.Load() > 2 {
+		t.Fatalf("worker pool exceeded: peak=%d", peak.Load())
+	}
+	for i, r := range results {
+		if r.CameraID != cams[i].CameraID {
+			t.Fatalf("results out of order: %+v", results)
+		}
+	}
+	if results[2].Status != "error" || !strings.Contains(results[2].Error, "camera not found") || results[2].Path != "" {
+		t.Fatalf("unexpected failure result: %+v", results[2])
+	}
+	if results[0].Status != "ok" || results[0].Bytes != len("jpeg-A") {
+		t.Fatalf("unexpected ok result: %+v", results[0])
+	}
+	b, err := os.ReadFile(paths[3])
+	if err != nil || string(b) != "jpeg-D" {
+		t.Fatalf("file D: %q %v", b, err)
+	}
+}
+
+func TestRateLimiter_SpacesCalls(t *testing.T) {
+	l := newRateLimiter(50) // 20ms apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if el := time.Since(start); el < 55*time.Millisecond {
+		t.Fatalf("expected >=60ms for 4 calls at 50/s, took %s", el)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newRateLimiter(0).Wait(ctx); err == nil {
+		t.Fatalf("expected canceled context error")
+	}
+}
+
+func TestCamerasSnapshot_AllFailedStillWritesIndex(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cameras/v1/devices" {
+			_, _ = w.Write([]byte(`{"cameras":[{"camera_id":"CAM1","name":"Door"},{"camera_id":"CAM2","name":"Dock"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"rate limited"}`))
+	}))
+	defer api.Close()
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{BaseURL: api.URL, OrgID: "ORG1", Auth: AuthConfig{APIKey: "key-1234567890", Token: "tok-abcdefghij", TokenAcquiredAt: time.Now().Unix()}}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	outDir := filepath.Join(t.TempDir(), "snaps")
+
+	_, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "snapshot", "--all", "--source", "api", "--out-dir", outDir)
+	if err == nil {
+		t.Fatal("snapshot with every camera failing should fail")
+	}
+	var m snapshotManifest
+	if err := json.Unmarshal(mustReadFile(t, filepath.Join(outDir, "index.json")), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Failed != 2 || len(m.Cameras) != 2 {
+		t.Fatalf("index = %+v", m)
+	}
+	for _, c := range m.Cameras {
+		if c.Status != "error" || !strings.Contains(c.Error, "rate limited") {
+			t.Fatalf("camera %s: %+v", c.CameraID, c)
+		}
+	}
+}
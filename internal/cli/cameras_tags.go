@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newCamerasTagCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manage local camera tags (stored in the config profile)",
+		Long: strings.TrimSpace(`
+Tags are short lower-case words such as exterior, ptz or needs-cleaning. A camera can have any
+number of them; they are searchable with: verkcli cameras search tag:exterior
+`),
+	}
+	cmd.AddCommand(newCamerasTagAddCmd(rf))
+	cmd.AddCommand(newCamerasTagRmCmd(rf))
+	cmd.AddCommand(newCamerasTagListCmd(rf))
+	return cmd
+}
+
+func newCamerasTagAddCmd(rf *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add CAMERA_ID TAG...",
+		Short: "Add tags to a camera",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := normalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			cur, err := updateCameraMeta(rf, args[0], func(l *LocalLabels, id string) {
+				l.Tags[id] = addTags(l.Tags[id], tags)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "tags[%s]=%s\n", strings.TrimSpace(args[0]), strings.Join(cur.Tags[strings.TrimSpace(args[0])], ","))
+			return nil
+		},
+	}
+}
+
+func newCamerasTagRmCmd(rf *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm CAMERA_ID TAG...",
+		Short: "Remove tags from a camera",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := normalizeTags(args[1:])
+			if err != nil {
+				return err
+			}
+			if err := checkOwnTags(rf, strings.TrimSpace(args[0]), tags); err != nil {
+				return err
+			}
+			cur, err := updateCameraMeta(rf, args[0], func(l *LocalLabels, id string) {
+				drop := map[string]bool{}
+				for _, t := range tags {
+					drop[t] = true
+				}
+				kept := l.Tags[id][:0]
+				for _, t := range l.Tags[id] {
+					if !drop[t] {
+						kept = append(kept, t)
+					}
+				}
+				if len(kept) == 0 {
+					delete(l.Tags, id)
+				} else {
+					l.Tags[id] = kept
+				}
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "tags[%s]=%s\n", strings.TrimSpace(args[0]), strings.Join(cur.Tags[strings.TrimSpace(args[0])], ","))
+			return nil
+		},
+	}
+}
+
+func newCamerasTagListCmd(rf *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [CAMERA_ID]",
+		Short: "List a camera's tags, or every tag with the cameras that have it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			var all map[string][]string
+			if cfg.Labels != nil {
+				all = cfg.Labels.Tags
+			}
+			out := cmd.OutOrStdout()
+
+			if len(args) == 1 {
+				tags := all[strings.TrimSpace(args[0])]
+				if rf.Output == "json" {
+					return writeIndentedJSON(out, map[string]any{"camera_id": strings.TrimSpace(args[0]), "tags": append([]string{}, tags...)})
+				}
+				for _, t := range tags {
+					fmt.Fprintln(out, t)
+				}
+				return nil
+			}
+
+			byTag := map[string][]string{}
+			for id, tags := range all {
+				for _, t := range tags {
+					byTag[t] = append(byTag[t], id)
+				}
+			}
+			for _, ids := range byTag {
+				sort.Strings(ids)
+			}
+			if rf.Output == "json" {
+				return writeIndentedJSON(out, map[string]any{"tags": byTag})
+			}
+			names := make([]string, 0, len(byTag))
+			for t := range byTag {
+				names = append(names, t)
+			}
+			sort.Strings(names)
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "TAG\tCAMERAS\tCAMERA_IDS")
+			for _, t := range names {
+				fmt.Fprintf(tw, "%s\t%d\t%s\n", t, len(byTag[t]), strings.Join(byTag[t], ","))
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newCamerasNoteCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "note",
+		Short: "Keep timestamped local notes about cameras (stored in the config profile)",
+	}
+	cmd.AddCommand(newCamerasNoteAddCmd(rf))
+	cmd.AddCommand(newCamerasNoteListCmd(rf))
+	return cmd
+}
+
+func newCamerasNoteAddCmd(rf *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:     "add CAMERA_ID TEXT...",
+		Short:   "Add a note to a camera",
+		Example: `  verkcli cameras note add CAM123 "lens cracked, ticket 4411"`,
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := strings.TrimSpace(strings.Join(args[1:], " "))
+			if text == "" {
+				return errors.New("note is empty")
+			}
+			note := CameraNote{At: time.Now().UTC().Format(time.RFC3339), Text: text}
+			_, err := updateCameraMeta(rf, args[0], func(l *LocalLabels, id string) {
+				l.Notes[id] = append(l.Notes[id], note)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "note[%s]@%s=%s\n", strings.TrimSpace(args[0]), note.At, note.Text)
+			return nil
+		},
+	}
+}
+
+type cameraNoteRow struct {
+	CameraID string `json:"camera_id"`
+	CameraNote
+}
+
+func newCamerasNoteListCmd(rf *rootFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list [CAMERA_ID]",
+		Short: "List notes, oldest first, for one camera or all of them",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			rows := []cameraNoteRow{}
+			if cfg.Labels != nil {
+				for id, notes := range cfg.Labels.Notes {
+					if len(args) == 1 && id != strings.TrimSpace(args[0]) {
+						continue
+					}
+					for _, n := range notes {
+						rows = append(rows, cameraNoteRow{CameraID: id, CameraNote: n})
+					}
+				}
+			}
+			sort.SliceStable(rows, func(i, j int) bool {
+				if rows[i].At != rows[j].At {
+					return rows[i].At < rows[j].At
+				}
+				return rows[i].CameraID < rows[j].CameraID
+			})
+
+			out := cmd.OutOrStdout()
+			if rf.Output == "json" {
+				return writeIndentedJSON(out, map[string]any{"notes": rows})
+			}
+			tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "AT\tCAMERA_ID\tNOTE")
+			for _, r := range rows {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", r.At, r.CameraID, r.Text)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+// updateCameraMeta runs fn on the selected profile's stored metadata for cameraID (with Tags and
+// Notes non-nil), writes the config and refreshes the camera's row in the cameras index. It
+// returns the effective metadata afterwards, which includes values inherited from other layers.
+func updateCameraMeta(rf *rootFlags, cameraID string, fn func(l *LocalLabels, id string)) (LocalLabels, error) {
+	cameraID = strings.TrimSpace(cameraID)
+	if cameraID == "" {
+		return LocalLabels{}, errors.New("camera_id is empty")
+	}
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return LocalLabels{}, err
+	}
+	err = updateConfig(p, func(cf *ConfigFile) error {
+		name, err := selectedProfileName(*rf, *cf)
+		if err != nil {
+			return err
+		}
+		prof, ok := cf.Profiles[name]
+		if !ok {
+			return fmt.Errorf("profile %q not found in %s", name, p)
+		}
+		if prof.Labels == nil {
+			prof.Labels = &LocalLabels{}
+		}
+		if prof.Labels.Tags == nil {
+			prof.Labels.Tags = map[string][]string{}
+		}
+		if prof.Labels.Notes == nil {
+			prof.Labels.Notes = map[string][]CameraNote{}
+		}
+		fn(prof.Labels, cameraID)
+		cf.Profiles[name] = prof
+		return nil
+	})
+	if err != nil {
+		return LocalLabels{}, err
+	}
+
+	cfg, err := effectiveConfig(*rf)
+	if err != nil || cfg.Labels == nil {
+		return LocalLabels{}, nil
+	}
+	// Best-effort: keep the local search index in sync if it exists.
+	if idxPath, err := camerasIndexPath(*rf, cfg); err == nil {
+		tryUpdateIndexMeta(idxPath, cameraID, cfg.Labels)
+	}
+	return *cfg.Labels, nil
+}
+
+// checkOwnTags fails when a tag to remove from cameraID would stay because another layer
+// (defaults, a parent profile or the project config) also sets it.
+func checkOwnTags(rf *rootFlags, cameraID string, tags []string) error {
+	inherited, src, err := resolveInherited(*rf, func(l *LocalLabels) { delete(l.Tags, cameraID) })
+	if err != nil {
+		return err
+	}
+	if inherited.Labels == nil {
+		return nil
+	}
+	for _, t := range tags {
+		for _, have := range inherited.Labels.Tags[cameraID] {
+			if have == t {
+				return fmt.Errorf("tag %q on %s comes from %s; remove it there", t, cameraID, src["labels.tags."+cameraID+"."+t])
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeTags lower-cases tags and rejects ones that could not be typed as a single word.
+func normalizeTags(in []string) ([]string, error) {
+	out := make([]string, 0, len(in))
+	for _, t := range in {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			return nil, errors.New("tag is empty")
+		}
+		if strings.ContainsAny(t, " \t\n,:") {
+			return nil, fmt.Errorf("invalid tag %q (no spaces, commas or colons)", t)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// addTags returns the sorted union of tags and more.
+func addTags(tags, more []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags)+len(more))
+	for _, t := range append(append([]string{}, tags...), more...) {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// mergeCameraNotes returns the notes of a and b without duplicates, oldest first.
+func mergeCameraNotes(a, b []CameraNote) []CameraNote {
+	seen := map[CameraNote]bool{}
+	out := make([]CameraNote, 0, len(a)+len(b))
+	for _, n := range append(append([]CameraNote{}, a...), b...) {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At < out[j].At })
+	return out
+}
+
+// cameraMetaSummary is the tags and note count shown next to a camera in list output.
+func cameraMetaSummary(l *LocalLabels, cameraID string) (tags string, notes int) {
+	if l == nil {
+		return "", 0
+	}
+	return strings.Join(l.Tags[cameraID], ","), len(l.Notes[cameraID])
+}
+
+// withCameraMeta returns c with the camera's local tags and notes added under "tags" and
+// "notes" for JSON output; c itself is left alone.
+func withCameraMeta(c map[string]any, l *LocalLabels) map[string]any {
+	if l == nil {
+		return c
+	}
+	id := pickString(c, "camera_id", "cameraId", "cameraID", "id")
+	tags, notes := l.Tags[id], l.Notes[id]
+	if len(tags) == 0 && len(notes) == 0 {
+		return c
+	}
+	out := make(map[string]any, len(c)+2)
+	for k, v := range c {
+		out[k] = v
+	}
+	if len(tags) > 0 {
+		out["tags"] = tags
+	}
+	if len(notes) > 0 {
+		out["notes"] = notes
+	}
+	return out
+}
+
+// withCamerasMeta applies withCameraMeta to every camera of a {"cameras": [...]} list response,
+// keeping its other fields. ok is false when b is not such a response.
+func withCamerasMeta(b []byte, l *LocalLabels) (out []byte, ok bool) {
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, false
+	}
+	cams, isList := doc["cameras"].([]any)
+	if !isList {
+		return nil, false
+	}
+	for i, c := range cams {
+		if m, isObj := c.(map[string]any); isObj {
+			cams[i] = withCameraMeta(m, l)
+		}
+	}
+	blob, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, false
+	}
+	return append(blob, '\n'), true
+}
+
+func writeIndentedJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
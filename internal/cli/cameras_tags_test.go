@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCamerasTagsAndNotes(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL: "https://api.verkada.com",
+		OrgID:   "ORG1",
+		Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Front"}},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door", "site": "HQ"},
+		{"camera_id": "CAM2", "name": "Dock", "site": "HQ"},
+	})
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := runProfilesCLI(t, append([]string{"--config", cfgPath}, args...)...)
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", args, err, out)
+		}
+		return out
+	}
+	run("cameras", "tag", "add", "CAM1", "Exterior", "ptz", "needs-cleaning")
+	run("cameras", "tag", "add", "CAM2", "exterior")
+	if out := run("cameras", "tag", "rm", "CAM1", "ptz"); out != "tags[CAM1]=exterior,needs-cleaning\n" {
+		t.Fatalf("tag rm output = %q", out)
+	}
+	run("cameras", "note", "add", "CAM2", "lens cracked,", "ticket 4411")
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "tag", "add", "CAM1", "two words"); err == nil {
+		t.Fatal("tag with a space was accepted")
+	}
+
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := cf.Profiles["prod"].Labels
+	if l.Cameras["CAM1"] != "Front" || !reflect.DeepEqual(l.Tags["CAM2"], []string{"exterior"}) || len(l.Notes["CAM2"]) != 1 || l.Notes["CAM2"][0].Text != "lens cracked, ticket 4411" {
+		t.Fatalf("stored labels = %+v", l)
+	}
+
+	var tags struct {
+		Tags map[string][]string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(run("--output", "json", "cameras", "tag", "list")), &tags); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags.Tags["exterior"], []string{"CAM1", "CAM2"}) {
+		t.Fatalf("tag list = %v", tags.Tags)
+	}
+	if out := run("cameras", "note", "list", "CAM2"); !strings.Contains(out, "CAM2") || !strings.Contains(out, "ticket 4411") {
+		t.Fatalf("note list:\n%s", out)
+	}
+
+	for q, want := range map[string][]string{
+		"tag:exterior":          {"CAM1", "CAM2"},
+		"tag:needs-cleaning":    {"CAM1"},
+		"note:cracked":          {"CAM2"},
+		"tag:exterior dock":     {"CAM2"},
+		"tag:ptz":               nil,
+		"4411":                  {"CAM2"},
+		"front tag:exterior hq": {"CAM1"},
+	} {
+		res, err := searchCamerasIndex(idxPath, q, 10)
+		if err != nil {
+			t.Fatalf("search %q: %v", q, err)
+		}
+		var got []string
+		for _, r := range res.Results {
+			got = append(got, r.CameraID)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("search %q = %v, want %v", q, got, want)
+		}
+	}
+
+	body := []byte(`{"cameras":[{"camera_id":"CAM1","name":"Door"}]}`)
+	s, err := formatCameraListText(body, false, l)
+	if err != nil || !strings.Contains(s, "exterior,needs-cl...") {
+		t.Fatalf("list text:\n%s", s)
+	}
+}
+
+func TestCamerasIndexMigratesFTSWithoutTags(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cameras.sqlite")
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`CREATE TABLE cameras (camera_id TEXT PRIMARY KEY, name TEXT, site TEXT, model TEXT, serial TEXT, status TEXT, timezone TEXT, updated_at INTEGER, raw_json TEXT)`,
+		`CREATE TABLE labels (camera_id TEXT PRIMARY KEY, label TEXT, updated_at INTEGER)`,
+		`CREATE VIRTUAL TABLE cameras_fts USING fts5(camera_id UNINDEXED, name, site, label, model, serial, status, timezone, tokenize = 'unicode61')`,
+		`INSERT INTO cameras(camera_id,name,site,raw_json) VALUES('CAM1','Door','HQ','{"camera_id":"CAM1"}')`,
+		`INSERT INTO labels(camera_id,label) VALUES('CAM1','Lobby')`,
+		`INSERT INTO cameras_fts(camera_id,name,site,label) VALUES('CAM1','Door','HQ','Lobby')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	tryUpdateIndexMeta(dbPath, "CAM1", &LocalLabels{Tags: map[string][]string{"CAM1": {"exterior"}}})
+	for _, q := range []string{"tag:exterior", "lobby"} {
+		res, err := searchCamerasIndex(dbPath, q, 10)
+		if err != nil || len(res.Results) != 1 {
+			t.Fatalf("search %q after migration = %+v, %v", q, res, err)
+		}
+	}
+}
+
+func TestCamerasTagsInherited(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	base := Config{
+		BaseURL: "https://api.verkada.com",
+		OrgID:   "ORG1",
+		Labels: &LocalLabels{
+			Tags:  map[string][]string{"CAM1": {"exterior"}, "CAM2": {"non-ptz"}},
+			Notes: map[string][]CameraNote{"CAM1": {{At: "2026-01-02T03:04:05Z", Text: "installed"}}},
+		},
+	}
+	cf := ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"base": base, "prod": {Extends: "base"}}}
+	if err := writeConfig(cfgPath, cf); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		return runProfilesCLI(t, append([]string{"--config", cfgPath}, args...)...)
+	}
+	if out, err := run("cameras", "tag", "add", "CAM1", "ptz"); err != nil || out != "tags[CAM1]=exterior,ptz\n" {
+		t.Fatalf("tag add = %q, %v", out, err)
+	}
+	if _, err := run("cameras", "note", "add", "CAM1", "lens cleaned"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := run("cameras", "tag", "rm", "CAM1", "exterior")
+	if err == nil || !strings.Contains(err.Error(), "profile:base") {
+		t.Fatalf("tag rm of an inherited tag: %v", err)
+	}
+	if _, err := run("cameras", "tag", "rm", "CAM1", "ptz"); err != nil {
+		t.Fatalf("tag rm of an own tag: %v", err)
+	}
+
+	_, cfg, err := effectiveProfileConfig(rootFlags{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Labels.Tags["CAM1"], []string{"exterior"}) || len(cfg.Labels.Notes["CAM1"]) != 2 {
+		t.Fatalf("resolved labels = %+v", cfg.Labels)
+	}
+
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door"},
+		{"camera_id": "CAM2", "name": "Dock"},
+	})
+	tryUpdateIndexMeta(idxPath, "CAM1", &LocalLabels{Tags: map[string][]string{"CAM1": {"ptz"}}})
+	tryUpdateIndexMeta(idxPath, "CAM2", cfg.Labels)
+	res, err := searchCamerasIndex(idxPath, "tag:ptz", 10)
+	if err != nil || len(res.Results) != 1 || res.Results[0].CameraID != "CAM1" {
+		t.Fatalf("tag:ptz = %+v, %v", res, err)
+	}
+
+	got := withCameraMeta(map[string]any{"camera_id": "CAM1", "name": "Door"}, cfg.Labels)
+	if !reflect.DeepEqual(got["tags"], []string{"exterior"}) || got["notes"] == nil || got["name"] != "Door" {
+		t.Fatalf("camera json = %v", got)
+	}
+	blob, ok := withCamerasMeta([]byte(`{"cameras":[{"camera_id":"CAM2"},{"camera_id":"CAM3"}],"next_page_token":"x"}`), cfg.Labels)
+	if !ok {
+		t.Fatal("list json not annotated")
+	}
+	var list struct {
+		Cameras []map[string]any `json:"cameras"`
+		Next    string           `json:"next_page_token"`
+	}
+	if err := json.Unmarshal(blob, &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Next != "x" || !reflect.DeepEqual(list.Cameras[0]["tags"], []any{"non-ptz"}) || list.Cameras[1]["tags"] != nil {
+		t.Fatalf("list json = %s", blob)
+	}
+}
@@ -0,0 +1,499 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"math/bits"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/image/draw"
+)
+
+type camerasThumbnailCompareFlags struct {
+	Set        cameraSetFlags
+	CameraID   string
+	A          string
+	B          string
+	ADir       string
+	BDir       string
+	Timezone   string
+	Resolution string
+	Grid       int
+	Threshold  float64
+	DiffOut    string
+	DiffDir    string
+	Workers    int
+	Rate       float64
+	Timeout    time.Duration
+}
+
+// compareWidth x compareHeight is the grayscale size both images are reduced to before diffing,
+// which also makes low-res and hi-res thumbnails comparable.
+const compareWidth, compareHeight = 256, 144
+
+type compareResult struct {
+	CameraID     string      `json:"camera_id"`
+	Name         string      `json:"name,omitempty"`
+	Status       string      `json:"status"` // ok|changed|error
+	Score        float64     `json:"score"`
+	HashDistance int         `json:"hash_distance"`
+	MaxRegion    float64     `json:"max_region_diff"`
+	MeanDiff     float64     `json:"mean_diff"`
+	Regions      [][]float64 `json:"regions,omitempty"`
+	DiffPath     string      `json:"diff_path,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+func newCamerasThumbnailCompareCmd(rf *rootFlags) *cobra.Command {
+	var f camerasThumbnailCompareFlags
+
+	cmd := &cobra.Command{
+		Use:   "compare [CAMERA...]",
+		Short: "Detect cameras that were bumped, blocked or repositioned between two times",
+		Long: strings.TrimSpace(`
+Fetches a thumbnail at --a and at --b for each camera and compares them:
+
+- hash_distance: Hamming distance (0-64) between 64-bit difference hashes. Large values mean the
+  scene's structure changed (camera moved or repositioned).
+- regions: mean absolute grayscale difference (0-1) per cell of a --grid x --grid grid; a high
+  max_region_diff with a low mean points at a partial blockage or new object.
+- score: 100 * max(hash_distance/64, max_region_diff).
+
+Cameras with score above --threshold are reported as "changed" and make the command exit
+non-zero, so it can run from cron. Instead of fetching, --a-dir/--b-dir compare two
+"cameras snapshot" output directories (matched by camera_id via index.json); a camera without a
+good snapshot on one side is reported as an error.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras thumbnail compare --camera CAM123 --a "2026-02-15 09:00:00" --b "2026-02-16 09:00:00" --diff-out diff.png
+  verkcli cameras thumbnail compare --site HQ --a 2026-02-15T09:00:00Z --threshold 30 --diff-dir diffs/
+  verkcli cameras thumbnail compare --a-dir snaps/monday --b-dir snaps/tuesday
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if f.Grid < 1 || f.Grid > 16 {
+				return errors.New("--grid must be between 1 and 16")
+			}
+			if f.Threshold < 0 || f.Threshold > 100 {
+				return errors.New("--threshold must be between 0 and 100")
+			}
+			if f.Resolution != "low-res" && f.Resolution != "hi-res" {
+				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
+			}
+			if f.Workers < 1 {
+				return errors.New("--workers must be at least 1")
+			}
+			if (f.ADir == "") != (f.BDir == "") {
+				return errors.New("--a-dir and --b-dir must be used together")
+			}
+			dirMode := f.ADir != ""
+			if !dirMode && strings.TrimSpace(f.A) == "" {
+				return errors.New("--a is required (or compare snapshot directories with --a-dir/--b-dir)")
+			}
+			if f.DiffOut != "" && f.DiffDir != "" {
+				return errors.New("use either --diff-out or --diff-dir")
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			var cams []cameraInfo
+			var sourceA, sourceB func(cameraID string) ([]byte, error)
+			if dirMode {
+				ma, err := loadSnapshotDir(f.ADir)
+				if err != nil {
+					return err
+				}
+				mb, err := loadSnapshotDir(f.BDir)
+				if err != nil {
+					return err
+				}
+				if cams, err = snapshotDirCameras(ma, mb, f.CameraID, args); err != nil {
+					return err
+				}
+				if len(cams) == 0 {
+					return errors.New("no cameras in --a-dir or --b-dir")
+				}
+				sourceA, sourceB = ma.read, mb.read
+			} else {
+				ta, err := parseThumbnailTimestamp(f.A, f.Timezone)
+				if err != nil {
+					return fmt.Errorf("invalid --a: %w", err)
+				}
+				tb, err := parseThumbnailTimestamp(f.B, f.Timezone)
+				if err != nil {
+					return fmt.Errorf("invalid --b: %w", err)
+				}
+				if strings.TrimSpace(f.CameraID) != "" {
+					id, err := resolveCameraArg(*rf, cfg, f.CameraID)
+					if err != nil {
+						return err
+					}
+					info := cameraInfo{CameraID: id}
+					if cfg.Labels != nil {
+						info.Label = cfg.Labels.Cameras[id]
+					}
+					cams = []cameraInfo{info}
+				} else {
+					raw, err := resolveCameraSet(client, &cfg, rf, f.Set, args)
+					if err != nil {
+						return err
+					}
+					for _, c := range raw {
+						cams = append(cams, newCameraInfo(c, cfg.Labels))
+					}
+				}
+				fetch := newSharedThumbnailFetcher(client, cfg, rf, f.Resolution)
+				limiter := newRateLimiter(f.Rate)
+				at := func(ts int64) func(string) ([]byte, error) {
+					return func(id string) ([]byte, error) {
+						if err := limiter.Wait(cmd.Context()); err != nil {
+							return nil, err
+						}
+						b, _, err := fetch(id, ts)
+						return b, err
+					}
+				}
+				sourceA, sourceB = at(ta), at(tb)
+			}
+
+			diffPath := func(c cameraInfo) string {
+				if f.DiffOut != "" && len(cams) == 1 {
+					return f.DiffOut
+				}
+				if f.DiffDir != "" {
+					return filepath.Join(f.DiffDir, sanitizePathComponent(c.CameraID)+"_diff.png")
+				}
+				return ""
+			}
+			if f.DiffOut != "" && len(cams) > 1 {
+				return errors.New("--diff-out writes one image; use --diff-dir when comparing several cameras")
+			}
+
+			results := make([]compareResult, len(cams))
+			forEachConcurrent(len(cams), f.Workers, func(i int) {
+				results[i] = compareCamera(cams[i], sourceA, sourceB, f.Grid, f.Threshold, diffPath(cams[i]))
+			})
+
+			changed, failed := 0, 0
+			for _, r := range results {
+				switch r.Status {
+				case "changed":
+					changed++
+				case "error":
+					failed++
+				}
+			}
+
+			if rf.Output == "json" {
+				blob, err := json.MarshalIndent(map[string]any{
+					"threshold": f.Threshold,
+					"grid":      f.Grid,
+					"changed":   changed,
+					"failed":    failed,
+					"results":   results,
+				}, "", "  ")
+				if err != nil {
+					return err
+				}
+				blob = append(blob, '\n')
+				_, _ = cmd.OutOrStdout().Write(blob)
+			} else {
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "STATUS\tCAMERA_ID\tNAME\tSCORE\tHASH\tMAX_REGION\tMEAN\tDETAIL")
+				for _, r := range results {
+					detail := r.DiffPath
+					if r.Error != "" {
+						detail = r.Error
+					}
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f\t%d\t%.3f\t%.3f\t%s\n", r.Status, r.CameraID, trunc(r.Name, 28), r.Score, r.HashDistance, r.MaxRegion, r.MeanDiff, detail)
+				}
+				_ = tw.Flush()
+			}
+
+			if failed > 0 || changed > 0 {
+				return fmt.Errorf("%d changed above threshold %.1f, %d failed (of %d)", changed, f.Threshold, failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	addCameraSetFlags(cmd, &f.Set)
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Compare a single camera: camera_id or local label")
+	cmd.Flags().StringVar(&f.A, "a", "", "First (baseline) time (same formats as `cameras thumbnail --timestamp`)")
+	cmd.Flags().StringVar(&f.B, "b", "", "Second time (default: now)")
+	cmd.Flags().StringVar(&f.ADir, "a-dir", "", "Baseline `cameras snapshot` directory instead of --a")
+	cmd.Flags().StringVar(&f.BDir, "b-dir", "", "Second `cameras snapshot` directory instead of --b")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --a/--b values.")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low-res", "Thumbnail resolution: low-res|hi-res")
+	cmd.Flags().IntVar(&f.Grid, "grid", 4, "Split the image into NxN regions for the pixel difference")
+	cmd.Flags().Float64Var(&f.Threshold, "threshold", 25, "Score (0-100) above which a camera counts as changed")
+	cmd.Flags().StringVar(&f.DiffOut, "diff-out", "", "Write a diff visualization PNG (single camera)")
+	cmd.Flags().StringVar(&f.DiffDir, "diff-dir", "", "Write <camera>_diff.png per camera into this directory")
+	cmd.Flags().IntVar(&f.Workers, "workers", 4, "Concurrent cameras")
+	cmd.Flags().Float64Var(&f.Rate, "rate", 5, "Maximum thumbnail requests per second (0 = unlimited)")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+func compareCamera(c cameraInfo, sourceA, sourceB func(string) ([]byte, error), grid int, threshold float64, diffPath string) compareResult {
+	r := compareResult{CameraID: c.CameraID, Name: c.DisplayName(), Status: "error"}
+	load := func(which string, src func(string) ([]byte, error)) (image.Image, error) {
+		b, err := src(c.CameraID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", which, err)
+		}
+		img, err := jpeg.Decode(bytes.NewReader(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: decode: %w", which, err)
+		}
+		return img, nil
+	}
+	a, err := load("a", sourceA)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	b, err := load("b", sourceB)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	m := compareImages(a, b, grid)
+	r.HashDistance, r.MaxRegion, r.MeanDiff, r.Regions, r.Score = m.HashDistance, m.MaxRegion, m.MeanDiff, m.Regions, m.Score
+	r.Status = "ok"
+	if r.Score > threshold {
+		r.Status = "changed"
+	}
+
+	if diffPath != "" {
+		if err := writeDiffImage(diffPath, a, b, m, threshold); err != nil {
+			r.Status, r.Error = "error", "diff image: "+err.Error()
+			return r
+		}
+		r.DiffPath = diffPath
+	}
+	return r
+}
+
+type compareMetrics struct {
+	HashDistance int
+	MaxRegion    float64
+	MeanDiff     float64
+	Regions      [][]float64
+	Score        float64
+
+	grayA, grayB *image.Gray
+}
+
+func compareImages(a, b image.Image, grid int) compareMetrics {
+	ga := grayResize(a, compareWidth, compareHeight)
+	gb := grayResize(b, compareWidth, compareHeight)
+	m := compareMetrics{
+		HashDistance: bits.OnesCount64(dHash(a) ^ dHash(b)),
+		Regions:      make([][]float64, grid),
+		grayA:        ga,
+		grayB:        gb,
+	}
+
+	var total float64
+	for gy := 0; gy < grid; gy++ {
+		m.Regions[gy] = make([]float64, grid)
+		y0, y1 := gy*compareHeight/grid, (gy+1)*compareHeight/grid
+		for gx := 0; gx < grid; gx++ {
+			x0, x1 := gx*compareWidth/grid, (gx+1)*compareWidth/grid
+			var sum float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					d := float64(ga.GrayAt(x, y).Y) - float64(gb.GrayAt(x, y).Y)
+					sum += math.Abs(d)
+				}
+			}
+			v := sum / float64((y1-y0)*(x1-x0)) / 255
+			m.Regions[gy][gx] = math.Round(v*1000) / 1000
+			m.MaxRegion = max(m.MaxRegion, m.Regions[gy][gx])
+			total += sum
+		}
+	}
+	m.MeanDiff = math.Round(total/float64(compareWidth*compareHeight)/255*1000) / 1000
+	m.Score = math.Round(100*max(float64(m.HashDistance)/64, m.MaxRegion)*10) / 10
+	return m
+}
+
+func grayResize(src image.Image, w, h int) *image.Gray {
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(rgba, rgba.Bounds(), src, src.Bounds(), draw.Src, nil)
+	g := image.NewGray(rgba.Bounds())
+	draw.Draw(g, g.Bounds(), rgba, image.Point{}, draw.Src)
+	return g
+}
+
+// dHash is a 64-bit difference hash: the image is reduced to 9x8 grayscale and each bit records
+// whether a pixel is brighter than its right neighbor. It ignores small brightness and
+// compression changes but flips many bits when the framing moves.
+func dHash(img image.Image) uint64 {
+	g := grayResize(img, 9, 8)
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			h <<= 1
+			if g.GrayAt(x, y).Y > g.GrayAt(x+1, y).Y {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+// writeDiffImage writes three panels side by side: A, B, and a heatmap of the per-pixel
+// difference over a dimmed B with regions above the threshold outlined.
+func writeDiffImage(path string, a, b image.Image, m compareMetrics, threshold float64) error {
+	w, h := compareWidth*2, compareHeight*2
+	out := image.NewRGBA(image.Rect(0, 0, 3*w+2*mosaicGap, h))
+	draw.Draw(out, out.Bounds(), image.NewUniform(mosaicBackground), image.Point{}, draw.Src)
+	draw.ApproxBiLinear.Scale(out, image.Rect(0, 0, w, h), a, a.Bounds(), draw.Src, nil)
+	draw.ApproxBiLinear.Scale(out, image.Rect(w+mosaicGap, 0, 2*w+mosaicGap, h), b, b.Bounds(), draw.Src, nil)
+
+	x0 := 2 * (w + mosaicGap)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			ga := m.grayA.GrayAt(x/2, y/2).Y
+			gb := m.grayB.GrayAt(x/2, y/2).Y
+			d := uint8(math.Abs(float64(ga) - float64(gb)))
+			base := gb / 3
+			out.SetRGBA(x0+x, y, color.RGBA{R: max(base, d), G: base, B: base, A: 0xff})
+		}
+	}
+
+	grid := len(m.Regions)
+	outline := color.RGBA{0xff, 0xd0, 0x00, 0xff}
+	for gy, row := range m.Regions {
+		for gx, v := range row {
+			if 100*v <= threshold {
+				continue
+			}
+			r := image.Rect(x0+gx*w/grid, gy*h/grid, x0+(gx+1)*w/grid-1, (gy+1)*h/grid-1)
+			for x := r.Min.X; x <= r.Max.X; x++ {
+				out.SetRGBA(x, r.Min.Y, outline)
+				out.SetRGBA(x, r.Max.Y, outline)
+			}
+			for y := r.Min.Y; y <= r.Max.Y; y++ {
+				out.SetRGBA(r.Min.X, y, outline)
+				out.SetRGBA(r.Max.X, y, outline)
+			}
+		}
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// snapshotDir is a loaded `cameras snapshot` output directory.
+type snapshotDir struct {
+	dir      string
+	manifest snapshotManifest
+	byCamera map[string]snapshotResult
+}
+
+func loadSnapshotDir(dir string) (*snapshotDir, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot manifest: %w (expected a `cameras snapshot` --out-dir)", err)
+	}
+	var m snapshotManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, "index.json"), err)
+	}
+	sd := &snapshotDir{dir: dir, manifest: m, byCamera: map[string]snapshotResult{}}
+	for _, c := range m.Cameras {
+		sd.byCamera[c.CameraID] = c
+	}
+	return sd, nil
+}
+
+func (sd *snapshotDir) read(cameraID string) ([]byte, error) {
+	c, ok := sd.byCamera[cameraID]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("no snapshot in %s", sd.dir)
+	case c.Status != "ok" || c.Path == "":
+		return nil, fmt.Errorf("snapshot failed in %s: %s", sd.dir, firstNonEmpty(c.Error, c.Status))
+	}
+	return os.ReadFile(sd.path(c))
+}
+
+// path resolves a manifest entry against the directory being loaded, wherever it was written.
+// Manifests from before paths were stored relative hold absolute paths, used as they are.
+func (sd *snapshotDir) path(c snapshotResult) string {
+	p := filepath.FromSlash(c.Path)
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(sd.dir, p)
+}
+
+// snapshotDirCameras lists the cameras of either snapshot directory, optionally narrowed to the
+// given references (camera_id, label or name as recorded in the manifests). A camera missing or
+// failed on one side is still listed, so the comparison reports it instead of dropping it; a
+// reference that matches no camera is an error.
+func snapshotDirCameras(a, b *snapshotDir, camera string, refs []string) ([]cameraInfo, error) {
+	want := map[string]bool{}
+	for _, r := range splitCameraRefs(append(refs, camera)) {
+		want[strings.ToLower(r)] = false
+	}
+	var out []cameraInfo
+	seen := map[string]bool{}
+	for _, c := range append(append([]snapshotResult{}, a.manifest.Cameras...), b.manifest.Cameras...) {
+		if c.CameraID == "" || seen[c.CameraID] {
+			continue
+		}
+		seen[c.CameraID] = true
+		if len(want) > 0 {
+			matched := false
+			for _, key := range []string{c.CameraID, c.Label, c.Name} {
+				if _, ok := want[strings.ToLower(key)]; ok {
+					want[strings.ToLower(key)], matched = true, true
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		out = append(out, cameraInfo{CameraID: c.CameraID, Name: c.Name, Site: c.Site, Label: c.Label})
+	}
+	var unmatched []string
+	for ref, ok := range want {
+		if !ok {
+			unmatched = append(unmatched, ref)
+		}
+	}
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		return nil, fmt.Errorf("no camera in %s or %s matches %s", a.dir, b.dir, strings.Join(unmatched, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sceneImage draws a gradient with a few shapes so hashes have structure to work with.
+func sceneImage(w, h, shift int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx := x + shift
+			v := uint8((sx * 255 / w) % 256)
+			if (sx/40+y/40)%2 == 0 {
+				v /= 2
+			}
+			img.Set(x, y, color.RGBA{v, v, v, 0xff})
+		}
+	}
+	return img
+}
+
+func jpegOf(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCompareImages_IdenticalAndChanged(t *testing.T) {
+	base := sceneImage(320, 180, 0)
+
+	same := compareImages(base, base, 4)
+	if same.HashDistance != 0 || same.MaxRegion != 0 || same.Score != 0 {
+		t.Fatalf("identical images: %+v", same)
+	}
+
+	// Blocked: one corner covered by a black box.
+	blocked := sceneImage(320, 180, 0)
+	for y := 0; y < 45; y++ {
+		for x := 0; x < 80; x++ {
+			blocked.Set(x, y, color.Black)
+		}
+	}
+	m := compareImages(base, blocked, 4)
+	if m.Regions[0][0] < 0.1 || m.Regions[3][3] != 0 {
+		t.Fatalf("blocked corner not localized: %v", m.Regions)
+	}
+	if m.MaxRegion != m.Regions[0][0] || m.MeanDiff >= m.MaxRegion {
+		t.Fatalf("unexpected aggregates: %+v", m)
+	}
+
+	// Repositioned: the scene shifts sideways.
+	moved := compareImages(base, sceneImage(320, 180, 60), 4)
+	if moved.HashDistance < 10 || moved.Score <= m.Score/2 {
+		t.Fatalf("shifted scene scored too low: hash=%d score=%.1f", moved.HashDistance, moved.Score)
+	}
+}
+
+func TestCompareCamera_ThresholdAndDiffImage(t *testing.T) {
+	a := jpegOf(t, sceneImage(320, 180, 0))
+	b := jpegOf(t, sceneImage(320, 180, 60))
+	src := func(data []byte) func(string) ([]byte, error) {
+		return func(string) ([]byte, error) { return data, nil }
+	}
+	diff := filepath.Join(t.TempDir(), "sub", "diff.png")
+
+	r := compareCamera(cameraInfo{CameraID: "CAM"}, src(a), src(b), 4, 5, diff)
+	if r.Status != "changed" || r.DiffPath != diff {
+		t.Fatalf("expected changed with diff image: %+v", r)
+	}
+	fh, err := os.Open(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	img, err := png.Decode(fh)
+	if err != nil {
+		t.Fatalf("diff png: %v", err)
+	}
+	if img.Bounds().Dx() != 3*2*compareWidth+2*mosaicGap {
+		t.Fatalf("diff image width %d", img.Bounds().Dx())
+	}
+
+	r = compareCamera(cameraInfo{CameraID: "CAM"}, src(a), src(a), 4, 5, "")
+	if r.Status != "ok" || r.Score != 0 {
+		t.Fatalf("expected ok: %+v", r)
+	}
+
+	failing := func(string) ([]byte, error) { return nil, errors.New("thumbnail request failed with status 404") }
+	r = compareCamera(cameraInfo{CameraID: "CAM"}, src(a), failing, 4, 5, "")
+	if r.Status != "error" || r.Error != "b: thumbnail request failed with status 404" {
+		t.Fatalf("expected error: %+v", r)
+	}
+}
+
+func TestSnapshotDirCameras(t *testing.T) {
+	write := func(dir string, m snapshotManifest) *snapshotDir {
+		b, _ := json.Marshal(m)
+		if err := os.WriteFile(filepath.Join(dir, "index.json"), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		sd, err := loadSnapshotDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sd
+	}
+	da, db := t.TempDir(), t.TempDir()
+	a := write(da, snapshotManifest{Cameras: []snapshotResult{
+		{CameraID: "A", Name: "Lobby", Status: "ok", Path: "a.jpg"},
+		{CameraID: "B", Name: "Dock", Status: "ok", Path: "b.jpg"},
+		{CameraID: "C", Status: "error", Error: "status 429"},
+	}})
+	b := write(db, snapshotManifest{Cameras: []snapshotResult{
+		{CameraID: "B", Name: "Dock", Status: "ok", Path: "b.jpg"},
+		{CameraID: "A", Name: "Lobby", Status: "ok", Path: "a.jpg"},
+		{CameraID: "C", Status: "ok", Path: "c.jpg"},
+		{CameraID: "D", Name: "Yard", Status: "ok", Path: "d.jpg"},
+	}})
+
+	// Cameras failed or missing on one side are listed too, so the comparison reports them.
+	all, err := snapshotDirCameras(a, b, "", nil)
+	if err != nil || cameraInfoIDs(all) != "A,B,C,D" {
+		t.Fatalf("cameras: %+v, %v", all, err)
+	}
+	if r := compareCamera(all[2], a.read, b.read, 4, 25, ""); r.Status != "error" || r.Error != "a: snapshot failed in "+da+": status 429" {
+		t.Fatalf("failed on one side: %+v", r)
+	}
+	if r := compareCamera(all[3], a.read, b.read, 4, 25, ""); r.Status != "error" || r.Error != "a: no snapshot in "+da {
+		t.Fatalf("missing on one side: %+v", r)
+	}
+	one, err := snapshotDirCameras(a, b, "dock", nil)
+	if err != nil || cameraInfoIDs(one) != "B" {
+		t.Fatalf("filtered: %+v, %v", one, err)
+	}
+	if _, err := snapshotDirCameras(a, b, "dock", []string{"Garage"}); err == nil || !strings.Contains(err.Error(), "matches garage") {
+		t.Fatalf("unmatched reference: %v", err)
+	}
+	if _, err := b.read("C"); err == nil {
+		// C exists only as a manifest entry; the file itself was never written.
+		t.Fatalf("expected read error for missing file")
+	}
+}
+
+func TestSnapshotDirMoved(t *testing.T) {
+	// Snapshot the same scene twice the way `cameras snapshot` writes it, then move both
+	// directories elsewhere before comparing.
+	root := t.TempDir()
+	for i, name := range []string{"monday", "tuesday"} {
+		out := filepath.Join(root, "snaps", name)
+		paths, err := snapshotPaths(out, "{site}/{camera_id}.jpg", []cameraInfo{{CameraID: "CAM1", Site: "HQ"}}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		img := jpegOf(t, sceneImage(160, 120, i*40))
+		results := runSnapshots(context.Background(), []cameraInfo{{CameraID: "CAM1", Site: "HQ"}}, paths, 1, nil, func(cameraInfo) ([]byte, error) { return img, nil })
+		relativeSnapshotPaths(out, results)
+		if results[0].Path != "hq/cam1.jpg" {
+			t.Fatalf("manifest path = %q", results[0].Path)
+		}
+		b, _ := json.Marshal(snapshotManifest{Cameras: results})
+		if err := os.WriteFile(filepath.Join(out, "index.json"), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	moved := filepath.Join(t.TempDir(), "archive")
+	if err := os.Rename(filepath.Join(root, "snaps"), moved); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := loadSnapshotDir(filepath.Join(moved, "monday"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadSnapshotDir(filepath.Join(moved, "tuesday"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cams, err := snapshotDirCameras(a, b, "", nil)
+	if err != nil || len(cams) != 1 {
+		t.Fatalf("cameras = %+v, %v", cams, err)
+	}
+	if r := compareCamera(cams[0], a.read, b.read, 4, 5, ""); r.Status != "changed" {
+		t.Fatalf("compare of moved snapshots = %+v", r)
+	}
+}
+
+func cameraInfoIDs(cams []cameraInfo) string {
+	ids := make([]string, len(cams))
+	for i, c := range cams {
+		ids[i] = c.CameraID
+	}
+	return strings.Join(ids, ",")
+}
@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+type camerasThumbnailMosaicFlags struct {
+	Set        cameraSetFlags
+	Cols       int
+	TileWidth  int
+	Timestamp  string
+	Timezone   string
+	Resolution string
+	OutPath    string
+	Format     string
+	View       bool
+	ViewProto  string
+	Workers    int
+	Rate       float64
+	Timeout    time.Duration
+}
+
+// mosaicTile is one cell of the contact sheet. Img is nil for failed fetches, which render as
+// a placeholder with the error in the caption.
+type mosaicTile struct {
+	Camera cameraInfo
+	Img    image.Image
+	Err    string
+	At     time.Time
+}
+
+var (
+	mosaicBackground  = color.RGBA{0x11, 0x11, 0x11, 0xff}
+	mosaicPlaceholder = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	mosaicCaptionBG   = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	mosaicText        = color.RGBA{0xee, 0xee, 0xee, 0xff}
+	mosaicSubtext     = color.RGBA{0xaa, 0xaa, 0xaa, 0xff}
+	mosaicErrorText   = color.RGBA{0xff, 0x6b, 0x6b, 0xff}
+)
+
+const (
+	mosaicGap     = 4
+	mosaicPadding = 4
+)
+
+func newCamerasThumbnailMosaicCmd(rf *rootFlags) *cobra.Command {
+	var f camerasThumbnailMosaicFlags
+
+	cmd := &cobra.Command{
+		Use:   "mosaic [CAMERA...]",
+		Short: "Compose thumbnails from many cameras into one contact-sheet image",
+		Long: strings.TrimSpace(`
+Fetches a thumbnail from every selected camera and lays them out in a grid with a caption per
+tile (label/name, site, time, status). Cameras whose thumbnail could not be fetched get a
+placeholder tile with the error.
+
+Output follows "cameras thumbnail": --out writes a file (PNG or JPEG by extension), piped stdout
+receives the image bytes, and terminals with an image protocol render it inline (--view-protocol).
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras thumbnail mosaic --site HQ --out hq.png
+  verkcli cameras thumbnail mosaic --query lobby --cols 3 --view
+  verkcli cameras thumbnail mosaic "Front Door" CAM123 CAM456 > sheet.png
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if f.Resolution != "low-res" && f.Resolution != "hi-res" {
+				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
+			}
+			if f.TileWidth < 64 || f.TileWidth > 1920 {
+				return errors.New("--tile-width must be between 64 and 1920")
+			}
+			if f.Cols < 0 {
+				return errors.New("--cols must not be negative")
+			}
+			if f.Workers < 1 {
+				return errors.New("--workers must be at least 1")
+			}
+			format, err := mosaicFormat(f.Format, f.OutPath)
+			if err != nil {
+				return err
+			}
+			if _, err := resolveViewProtocol(f.ViewProto, os.Getenv); err != nil {
+				return err
+			}
+			ts, err := parseThumbnailTimestamp(f.Timestamp, f.Timezone)
+			if err != nil {
+				return err
+			}
+			at := time.Unix(ts, 0).UTC()
+
+			stdoutIsTTY := isTerminalWriter(cmd.OutOrStdout())
+			writeStdout, viewEnabled, err := decideThumbnailOutput(stdoutIsTTY, terminalSupportsInlineImages() || viewProtocolExplicit(f.ViewProto), f.OutPath, f.View)
+			if rf.Output == "json" {
+				// stdout carries the summary; the image needs a file or the inline view.
+				if strings.TrimSpace(f.OutPath) == "" && !f.View {
+					return errors.New("--out or --view is required with --output json")
+				}
+				writeStdout, err = false, nil
+			}
+			if err != nil {
+				return err
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			cams, err := resolveCameraSet(client, &cfg, rf, f.Set, args)
+			if err != nil {
+				return err
+			}
+
+			fetch := newSharedThumbnailFetcher(client, cfg, rf, f.Resolution)
+			limiter := newRateLimiter(f.Rate)
+			tiles := make([]mosaicTile, len(cams))
+			forEachConcurrent(len(cams), f.Workers, func(i int) {
+				t := mosaicTile{Camera: newCameraInfo(cams[i], cfg.Labels), At: at}
+				if err := limiter.Wait(cmd.Context()); err != nil {
+					t.Err = err.Error()
+				} else if b, _, err := fetch(t.Camera.CameraID, ts); err != nil {
+					t.Err = err.Error()
+				} else if img, err := jpeg.Decode(bytes.NewReader(b)); err != nil {
+					t.Err = "decode: " + err.Error()
+				} else {
+					t.Img = img
+				}
+				tiles[i] = t
+			})
+
+			sheet := renderMosaic(tiles, f.Cols, f.TileWidth)
+			encoded, err := encodeMosaic(sheet, format)
+			if err != nil {
+				return err
+			}
+
+			if f.OutPath != "" {
+				if dir := filepath.Dir(f.OutPath); dir != "." {
+					if err := os.MkdirAll(dir, 0o755); err != nil {
+						return err
+					}
+				}
+				if err := os.WriteFile(f.OutPath, encoded, 0o644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d bytes)\n", f.OutPath, len(encoded))
+			} else if writeStdout {
+				_, _ = cmd.OutOrStdout().Write(encoded)
+			}
+			if viewEnabled {
+				inline := encoded
+				if format != "jpg" {
+					// The inline renderers take JPEG bytes.
+					if inline, err = encodeMosaic(sheet, "jpg"); err != nil {
+						return err
+					}
+				}
+				if err := renderInlineImage(cmd.ErrOrStderr(), f.ViewProto, inline, fmt.Sprintf("mosaic_%d.jpg", ts)); err != nil {
+					return err
+				}
+			}
+
+			failed := 0
+			summary := make([]map[string]any, 0, len(tiles))
+			for _, t := range tiles {
+				status := "ok"
+				if t.Img == nil {
+					status = "error"
+					failed++
+				}
+				row := map[string]any{"camera_id": t.Camera.CameraID, "name": t.Camera.DisplayName(), "status": status}
+				if t.Err != "" {
+					row["error"] = t.Err
+				}
+				summary = append(summary, row)
+			}
+			if rf.Output == "json" {
+				blob, err := json.MarshalIndent(map[string]any{
+					"timestamp": at.Format(time.RFC3339),
+					"out":       f.OutPath,
+					"tiles":     summary,
+				}, "", "  ")
+				if err != nil {
+					return err
+				}
+				blob = append(blob, '\n')
+				_, _ = cmd.OutOrStdout().Write(blob)
+			}
+			if failed > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%d of %d thumbnails failed (placeholder tiles)\n", failed, len(tiles))
+			}
+			return nil
+		},
+	}
+
+	addCameraSetFlags(cmd, &f.Set)
+	cmd.Flags().IntVar(&f.Cols, "cols", 0, "Tiles per row (0 picks a square-ish grid)")
+	cmd.Flags().IntVar(&f.TileWidth, "tile-width", 320, "Width of each tile in pixels")
+	cmd.Flags().StringVar(&f.Timestamp, "timestamp", "", "Thumbnail time (same formats as `cameras thumbnail --timestamp`). Omit to use now.")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for a naive --timestamp.")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low-res", "Thumbnail resolution: low-res|hi-res")
+	cmd.Flags().StringVarP(&f.OutPath, "out", "o", "", "Write the mosaic to a file (.png or .jpg)")
+	cmd.Flags().StringVar(&f.Format, "format", "", "Image format: png|jpg (default: from --out extension, else png)")
+	cmd.Flags().BoolVar(&f.View, "view", false, "Render the mosaic inline in terminal")
+	cmd.Flags().StringVar(&f.ViewProto, "view-protocol", "auto", "Inline image protocol: "+viewProtocolNames())
+	cmd.Flags().IntVar(&f.Workers, "workers", 4, "Concurrent thumbnail requests")
+	cmd.Flags().Float64Var(&f.Rate, "rate", 5, "Maximum thumbnail requests per second across all workers (0 = unlimited)")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+func mosaicFormat(format, outPath string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(outPath)) {
+		case ".jpg", ".jpeg":
+			format = "jpg"
+		default:
+			format = "png"
+		}
+	}
+	switch format {
+	case "png":
+		return "png", nil
+	case "jpg", "jpeg":
+		return "jpg", nil
+	default:
+		return "", fmt.Errorf("invalid --format %q (expected png|jpg)", format)
+	}
+}
+
+func encodeMosaic(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "jpg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	return buf.Bytes(), err
+}
+
+// mosaicGrid returns the column/row count for n tiles. cols <= 0 picks the smallest square
+// grid that fits.
+func mosaicGrid(n, cols int) (int, int) {
+	if n <= 0 {
+		return 1, 1
+	}
+	if cols <= 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	cols = min(cols, n)
+	return cols, (n + cols - 1) / cols
+}
+
+// renderMosaic lays tiles out row by row. Each tile is a 16:9 image area (images are scaled to
+// fit and centered) above a two-line caption.
+func renderMosaic(tiles []mosaicTile, cols, tileW int) *image.RGBA {
+	face := basicfont.Face7x13
+	lineH := face.Metrics().Height.Ceil()
+	imgH := tileW * 9 / 16
+	captionH := 2*lineH + 2*mosaicPadding
+	tileH := imgH + captionH
+
+	cols, rows := mosaicGrid(len(tiles), cols)
+	w := cols*tileW + (cols+1)*mosaicGap
+	h := rows*tileH + (rows+1)*mosaicGap
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(mosaicBackground), image.Point{}, draw.Src)
+
+	for i, t := range tiles {
+		x0 := mosaicGap + (i%cols)*(tileW+mosaicGap)
+		y0 := mosaicGap + (i/cols)*(tileH+mosaicGap)
+		imgRect := image.Rect(x0, y0, x0+tileW, y0+imgH)
+		capRect := image.Rect(x0, y0+imgH, x0+tileW, y0+tileH)
+
+		if t.Img != nil {
+			draw.Draw(dst, imgRect, image.NewUniform(color.Black), image.Point{}, draw.Src)
+			draw.ApproxBiLinear.Scale(dst, fitRect(t.Img.Bounds(), imgRect), t.Img, t.Img.Bounds(), draw.Src, nil)
+		} else {
+			draw.Draw(dst, imgRect, image.NewUniform(mosaicPlaceholder), image.Point{}, draw.Src)
+			msg := "no image"
+			mw := font.MeasureString(face, msg).Ceil()
+			drawMosaicText(dst, face, msg, x0+(tileW-mw)/2, y0+imgH/2, mosaicSubtext)
+		}
+
+		draw.Draw(dst, capRect, image.NewUniform(mosaicCaptionBG), image.Point{}, draw.Src)
+		maxChars := (tileW - 2*mosaicPadding) / face.Advance
+		title := t.Camera.DisplayName()
+		if t.Camera.Label != "" && t.Camera.Name != "" && t.Camera.Name != t.Camera.Label {
+			title += " (" + t.Camera.Name + ")"
+		}
+		sub := strings.Join(nonEmptyStrings(t.Camera.Site, t.At.UTC().Format("2006-01-02 15:04Z")), " - ")
+		subColor := mosaicSubtext
+		if t.Img == nil {
+			sub = "error: " + t.Err
+			subColor = mosaicErrorText
+		}
+		baseline := capRect.Min.Y + mosaicPadding + face.Ascent
+		drawMosaicText(dst, face, trunc(asciiOnly(title), maxChars), x0+mosaicPadding, baseline, mosaicText)
+		drawMosaicText(dst, face, trunc(asciiOnly(sub), maxChars), x0+mosaicPadding, baseline+lineH, subColor)
+	}
+	return dst
+}
+
+// fitRect scales src into dst preserving aspect ratio, centered.
+func fitRect(src, dst image.Rectangle) image.Rectangle {
+	sw, sh := src.Dx(), src.Dy()
+	dw, dh := dst.Dx(), dst.Dy()
+	if sw <= 0 || sh <= 0 {
+		return dst
+	}
+	w, h := dw, sh*dw/sw
+	if h > dh {
+		w, h = sw*dh/sh, dh
+	}
+	x := dst.Min.X + (dw-w)/2
+	y := dst.Min.Y + (dh-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+func drawMosaicText(dst draw.Image, face font.Face, s string, x, y int, c color.Color) {
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	d.DrawString(s)
+}
+
+// asciiOnly replaces characters the built-in bitmap font cannot draw.
+func asciiOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+func nonEmptyStrings(vals ...string) []string {
+	out := vals[:0]
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
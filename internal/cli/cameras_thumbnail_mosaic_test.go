@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestMosaicGrid(t *testing.T) {
+	cases := []struct{ n, cols, wantC, wantR int }{
+		{1, 0, 1, 1},
+		{4, 0, 2, 2},
+		{5, 0, 3, 2},
+		{10, 4, 4, 3},
+		{2, 5, 2, 1},
+	}
+	for _, c := range cases {
+		gc, gr := mosaicGrid(c.n, c.cols)
+		if gc != c.wantC || gr != c.wantR {
+			t.Fatalf("mosaicGrid(%d,%d)=%d,%d want %d,%d", c.n, c.cols, gc, gr, c.wantC, c.wantR)
+		}
+	}
+}
+
+func TestFitRect(t *testing.T) {
+	dst := image.Rect(0, 0, 320, 180)
+	if got := fitRect(image.Rect(0, 0, 640, 360), dst); got != dst {
+		t.Fatalf("same aspect: %v", got)
+	}
+	// 4:3 source is pillarboxed.
+	if got := fitRect(image.Rect(0, 0, 400, 300), dst); got != image.Rect(40, 0, 280, 180) {
+		t.Fatalf("4:3: %v", got)
+	}
+}
+
+func TestRenderMosaic_TilesAndPlaceholders(t *testing.T) {
+	red := image.NewRGBA(image.Rect(0, 0, 160, 90))
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 160; x++ {
+			red.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+	// Round-trip through JPEG like real thumbnails.
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, red, nil); err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC)
+	tiles := []mosaicTile{
+		{Camera: cameraInfo{CameraID: "A", Name: "Lobby", Site: "HQ"}, Img: img, At: at},
+		{Camera: cameraInfo{CameraID: "B", Name: "Dock"}, Err: "thumbnail request failed with status 404", At: at},
+		{Camera: cameraInfo{CameraID: "C", Label: "Front Door"}, Img: img, At: at},
+	}
+	sheet := renderMosaic(tiles, 2, 160)
+
+	tileH := 90 + 2*13 + 2*mosaicPadding
+	wantW := 2*160 + 3*mosaicGap
+	wantH := 2*tileH + 3*mosaicGap
+	if b := sheet.Bounds(); b.Dx() != wantW || b.Dy() != wantH {
+		t.Fatalf("sheet size %v, want %dx%d", b, wantW, wantH)
+	}
+
+	// Center of the first image area is red.
+	r, g, _, _ := sheet.At(mosaicGap+80, mosaicGap+45).RGBA()
+	if r>>8 < 0xe0 || g>>8 > 0x30 {
+		t.Fatalf("tile A center not red: r=%d g=%d", r>>8, g>>8)
+	}
+	// The failed tile's image area is the placeholder color (sampled away from its text).
+	x := 2*mosaicGap + 160 + 5
+	if got := color.RGBAModel.Convert(sheet.At(x, mosaicGap+5)); got != mosaicPlaceholder {
+		t.Fatalf("placeholder color = %v", got)
+	}
+	// Caption text is drawn: some pixel in tile A's caption differs from the caption background.
+	found := false
+	for y := mosaicGap + 90; y < mosaicGap+tileH && !found; y++ {
+		for x := mosaicGap; x < mosaicGap+160; x++ {
+			if color.RGBAModel.Convert(sheet.At(x, y)) != mosaicCaptionBG {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("caption text not drawn")
+	}
+
+	b, err := encodeMosaic(sheet, "png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+		t.Fatalf("png round-trip: %v", err)
+	}
+}
+
+func TestMosaicFormat(t *testing.T) {
+	if f, _ := mosaicFormat("", "a.JPG"); f != "jpg" {
+		t.Fatalf("got %q", f)
+	}
+	if f, _ := mosaicFormat("", ""); f != "png" {
+		t.Fatalf("got %q", f)
+	}
+	if _, err := mosaicFormat("bmp", ""); err == nil {
+		t.Fatalf("expected error")
+	}
+}
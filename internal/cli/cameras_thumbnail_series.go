@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font/basicfont"
+)
+
+type camerasThumbnailSeriesFlags struct {
+	CameraID   string
+	Start      string
+	End        string
+	Every      time.Duration
+	Timezone   string
+	Resolution string
+	OutDir     string
+	GIFPath    string
+	GIFDelay   time.Duration
+	GIFWidth   int
+	MaxFrames  int
+	Workers    int
+	Rate       float64
+	Timeout    time.Duration
+}
+
+type seriesFrame struct {
+	RequestedAt string `json:"requested_at"`
+	Path        string `json:"path,omitempty"`
+	SHA256      string `json:"sha256,omitempty"`
+	Bytes       int    `json:"bytes"`
+	// DuplicateOf is the requested_at of the earlier step that returned the same image.
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	Error       string `json:"error,omitempty"`
+
+	at   time.Time
+	data []byte
+}
+
+type seriesManifest struct {
+	CameraID   string        `json:"camera_id"`
+	Start      string        `json:"start"`
+	End        string        `json:"end"`
+	Every      string        `json:"every"`
+	Resolution string        `json:"resolution"`
+	Unique     int           `json:"unique"`
+	Duplicates int           `json:"duplicates"`
+	Failed     int           `json:"failed"`
+	GIF        string        `json:"gif,omitempty"`
+	Frames     []seriesFrame `json:"frames"`
+}
+
+func newCamerasThumbnailSeriesCmd(rf *rootFlags) *cobra.Command {
+	var f camerasThumbnailSeriesFlags
+
+	cmd := &cobra.Command{
+		Use:   "series",
+		Short: "Fetch thumbnails over a time range (timelapse), skipping repeats",
+		Long: strings.TrimSpace(`
+Fetches a thumbnail at every --every step from --start to --end (inclusive) and writes each
+distinct image to --out-dir as <camera>_<UTC time>.jpg, with an index.json manifest.
+
+The API returns the closest cached thumbnail, so consecutive steps often return the same image.
+Repeats are detected by content hash and recorded in the manifest (duplicate_of) instead of
+being written again. --gif renders the distinct images into an animated GIF.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras thumbnail series --camera CAM123 --start "2026-02-15 22:00:00" --end "2026-02-16 06:00:00" --every 5m --out-dir overnight/
+  verkcli cameras thumbnail series --camera "Front Door" --start 2026-02-15T14:00:00Z --end 2026-02-15T15:00:00Z --every 1m --out-dir frames/ --gif frames.gif
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.CameraID) == "" {
+				return errors.New("--camera is required")
+			}
+			if strings.TrimSpace(f.OutDir) == "" {
+				return errors.New("--out-dir is required")
+			}
+			if strings.TrimSpace(f.Start) == "" || strings.TrimSpace(f.End) == "" {
+				return errors.New("both --start and --end are required")
+			}
+			if f.Resolution != "low-res" && f.Resolution != "hi-res" {
+				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
+			}
+			if f.Every < time.Second {
+				return errors.New("--every must be at least 1s")
+			}
+			if f.Workers < 1 {
+				return errors.New("--workers must be at least 1")
+			}
+			if f.GIFWidth < 16 {
+				return errors.New("--gif-width must be at least 16")
+			}
+			cameraID, err := resolveCameraArg(*rf, cfg, f.CameraID)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: f.Timeout}
+			tz, err := resolveTimezoneFlag(client, &cfg, rf, f.Timezone, cameraID)
+			if err != nil {
+				return err
+			}
+			st, err := parseThumbnailTimestamp(f.Start, tz)
+			if err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+			et, err := parseThumbnailTimestamp(f.End, tz)
+			if err != nil {
+				return fmt.Errorf("invalid --end: %w", err)
+			}
+			if isCameraTimezone(f.Timezone) {
+				noteCameraTimezone(cmd.ErrOrStderr(), cameraID, tz, st)
+			}
+			start, end := time.Unix(st, 0).UTC(), time.Unix(et, 0).UTC()
+			if !end.After(start) {
+				return errors.New("--end must be after --start")
+			}
+			times, err := frameTimestamps(start, end, f.Every, f.MaxFrames)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(f.OutDir, 0o755); err != nil {
+				return err
+			}
+
+			fetch := newSharedThumbnailFetcher(client, cfg, rf, f.Resolution)
+			limiter := newRateLimiter(f.Rate)
+			frames := make([]seriesFrame, len(times))
+			forEachConcurrent(len(times), f.Workers, func(i int) {
+				fr := seriesFrame{RequestedAt: times[i].Format(time.RFC3339), at: times[i]}
+				if err := limiter.Wait(cmd.Context()); err != nil {
+					fr.Error = err.Error()
+				} else if b, _, err := fetch(cameraID, times[i].Unix()); err != nil {
+					fr.Error = err.Error()
+				} else {
+					fr.data = b
+				}
+				frames[i] = fr
+			})
+
+			m := seriesManifest{
+				CameraID:   cameraID,
+				Start:      start.Format(time.RFC3339),
+				End:        end.Format(time.RFC3339),
+				Every:      f.Every.String(),
+				Resolution: f.Resolution,
+			}
+			unique := dedupeSeriesFrames(frames)
+			for i := range frames {
+				fr := &frames[i]
+				switch {
+				case fr.Error != "":
+					m.Failed++
+				case fr.DuplicateOf != "":
+					m.Duplicates++
+				default:
+					m.Unique++
+					fr.Path = filepath.Join(f.OutDir, sanitizePathComponent(cameraID)+"_"+fr.at.Format("20060102T150405Z")+".jpg")
+					if err := os.WriteFile(fr.Path, fr.data, 0o644); err != nil {
+						return err
+					}
+				}
+			}
+
+			if f.GIFPath != "" {
+				if len(unique) == 0 {
+					return errors.New("no thumbnails fetched; nothing to render into --gif")
+				}
+				anim, err := buildSeriesGIF(unique, f.GIFWidth, f.GIFDelay)
+				if err != nil {
+					return err
+				}
+				if dir := filepath.Dir(f.GIFPath); dir != "." {
+					if err := os.MkdirAll(dir, 0o755); err != nil {
+						return err
+					}
+				}
+				fh, err := os.Create(f.GIFPath)
+				if err != nil {
+					return err
+				}
+				if err := gif.EncodeAll(fh, anim); err != nil {
+					_ = fh.Close()
+					return err
+				}
+				if err := fh.Close(); err != nil {
+					return err
+				}
+				m.GIF = f.GIFPath
+			}
+
+			m.Frames = frames
+			blob, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return err
+			}
+			blob = append(blob, '\n')
+			manifestPath := filepath.Join(f.OutDir, "index.json")
+			if err := os.WriteFile(manifestPath, blob, 0o644); err != nil {
+				return err
+			}
+			if rf.Output == "json" {
+				_, _ = cmd.OutOrStdout().Write(blob)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%d steps: %d unique, %d duplicates, %d failed; manifest: %s\n", len(frames), m.Unique, m.Duplicates, m.Failed, manifestPath)
+			if m.GIF != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d frames)\n", m.GIF, m.Unique)
+			}
+			if m.Failed > 0 {
+				return fmt.Errorf("%d of %d thumbnails failed", m.Failed, len(frames))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (required)")
+	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (alias of --camera)")
+	cmd.Flags().StringVar(&f.Start, "start", "", "First step (same formats as `cameras thumbnail --timestamp`)")
+	cmd.Flags().StringVar(&f.End, "end", "", "Last step, inclusive (same formats as --start)")
+	cmd.Flags().DurationVar(&f.Every, "every", 5*time.Minute, "Interval between steps")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --start/--end values (\"camera\" uses the camera's own timezone).")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low-res", "Thumbnail resolution: low-res|hi-res")
+	cmd.Flags().StringVar(&f.OutDir, "out-dir", "", "Directory for thumbnails and index.json (required)")
+	cmd.Flags().StringVar(&f.GIFPath, "gif", "", "Also render the distinct thumbnails into an animated GIF at this path")
+	cmd.Flags().DurationVar(&f.GIFDelay, "gif-delay", 500*time.Millisecond, "Time each GIF frame is shown")
+	cmd.Flags().IntVar(&f.GIFWidth, "gif-width", 480, "GIF width in pixels (height keeps the aspect ratio)")
+	cmd.Flags().IntVar(&f.MaxFrames, "max-frames", 1000, "Refuse series with more steps than this")
+	cmd.Flags().IntVar(&f.Workers, "workers", 4, "Concurrent thumbnail requests")
+	cmd.Flags().Float64Var(&f.Rate, "rate", 5, "Maximum thumbnail requests per second (0 = unlimited)")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+// dedupeSeriesFrames hashes fetched frames in time order, marks repeats of an earlier image with
+// DuplicateOf, and returns the distinct frames.
+func dedupeSeriesFrames(frames []seriesFrame) []seriesFrame {
+	first := map[string]string{}
+	var unique []seriesFrame
+	for i := range frames {
+		fr := &frames[i]
+		if fr.Error != "" {
+			continue
+		}
+		sum := sha256.Sum256(fr.data)
+		fr.SHA256 = hex.EncodeToString(sum[:])
+		fr.Bytes = len(fr.data)
+		if prev, ok := first[fr.SHA256]; ok {
+			fr.DuplicateOf = prev
+			continue
+		}
+		first[fr.SHA256] = fr.RequestedAt
+		unique = append(unique, *fr)
+	}
+	return unique
+}
+
+// buildSeriesGIF scales each frame to width, stamps the requested time in the corner, and
+// dithers it to the Plan 9 palette. The first frame sets the canvas height; later frames with
+// another aspect ratio (the camera's resolution changed) are letterboxed into it.
+func buildSeriesGIF(frames []seriesFrame, width int, delay time.Duration) (*gif.GIF, error) {
+	face := basicfont.Face7x13
+	anim := &gif.GIF{}
+	delayCS := max(int(delay/(10*time.Millisecond)), 1)
+	h := 0
+	for _, fr := range frames {
+		src, err := jpeg.Decode(bytes.NewReader(fr.data))
+		if err != nil {
+			return nil, fmt.Errorf("decode thumbnail at %s: %w", fr.RequestedAt, err)
+		}
+		b := src.Bounds()
+		if h == 0 {
+			h = max(b.Dy()*width/max(b.Dx(), 1), 1)
+		}
+		rgba := image.NewRGBA(image.Rect(0, 0, width, h))
+		draw.Draw(rgba, rgba.Bounds(), image.Black, image.Point{}, draw.Src)
+		draw.ApproxBiLinear.Scale(rgba, fitRect(b, rgba.Bounds()), src, b, draw.Src, nil)
+
+		label := fr.at.UTC().Format("2006-01-02 15:04:05Z")
+		lineH := face.Metrics().Height.Ceil()
+		box := image.Rect(0, h-lineH-2*mosaicPadding, len(label)*face.Advance+2*mosaicPadding, h)
+		draw.Draw(rgba, box, image.NewUniform(color.RGBA{0, 0, 0, 0xb0}), image.Point{}, draw.Over)
+		drawMosaicText(rgba, face, label, mosaicPadding, h-mosaicPadding-(lineH-face.Ascent), mosaicText)
+
+		pal := image.NewPaletted(rgba.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(pal, pal.Bounds(), rgba, image.Point{})
+		anim.Image = append(anim.Image, pal)
+		anim.Delay = append(anim.Delay, delayCS)
+	}
+	return anim, nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"image/color"
+	"image/gif"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestDedupeSeriesFrames(t *testing.T) {
+	at := time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)
+	step := func(i int, data string, errMsg string) seriesFrame {
+		ts := at.Add(time.Duration(i) * 5 * time.Minute)
+		fr := seriesFrame{RequestedAt: ts.Format(time.RFC3339), at: ts, Error: errMsg}
+		if errMsg == "" {
+			fr.data = []byte(data)
+		}
+		return fr
+	}
+	frames := []seriesFrame{
+		step(0, "A", ""),
+		step(1, "A", ""),
+		step(2, "", "thumbnail request failed with status 500"),
+		step(3, "B", ""),
+		step(4, "A", ""),
+	}
+	unique := dedupeSeriesFrames(frames)
+
+	if len(unique) != 2 || string(unique[0].data) != "A" || string(unique[1].data) != "B" {
+		t.Fatalf("unexpected unique frames: %+v", unique)
+	}
+	if frames[1].DuplicateOf != frames[0].RequestedAt || frames[4].DuplicateOf != frames[0].RequestedAt {
+		t.Fatalf("duplicates not linked to first occurrence: %+v", frames)
+	}
+	if frames[3].DuplicateOf != "" || frames[3].SHA256 == frames[0].SHA256 || frames[3].Bytes != 1 {
+		t.Fatalf("distinct frame misreported: %+v", frames[3])
+	}
+	if frames[2].SHA256 != "" {
+		t.Fatalf("failed frame should not be hashed: %+v", frames[2])
+	}
+}
+
+func TestBuildSeriesGIF(t *testing.T) {
+	at := time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)
+	frames := []seriesFrame{
+		{RequestedAt: at.Format(time.RFC3339), at: at, data: testJPEG(t, 320, 180, color.RGBA{200, 0, 0, 255})},
+		{RequestedAt: at.Add(time.Minute).Format(time.RFC3339), at: at.Add(time.Minute), data: testJPEG(t, 320, 180, color.RGBA{0, 0, 200, 255})},
+	}
+	anim, err := buildSeriesGIF(frames, 160, 250*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 2 || anim.Delay[0] != 25 {
+		t.Fatalf("unexpected gif: %d frames, delay %v", len(anim.Image), anim.Delay)
+	}
+	if b := anim.Image[0].Bounds(); b.Dx() != 160 || b.Dy() != 90 {
+		t.Fatalf("frame size %v", b)
+	}
+	// The top-left corner keeps the thumbnail color; the timestamp box sits bottom-left.
+	r, _, bl, _ := anim.Image[1].At(150, 5).RGBA()
+	if bl>>8 < 150 || r>>8 > 60 {
+		t.Fatalf("second frame not blue: r=%d b=%d", r>>8, bl>>8)
+	}
+}
+
+func TestBuildSeriesGIF_LetterboxesResolutionChange(t *testing.T) {
+	at := time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)
+	frames := []seriesFrame{
+		{RequestedAt: at.Format(time.RFC3339), at: at, data: testJPEG(t, 320, 180, color.RGBA{200, 0, 0, 255})},
+		{RequestedAt: at.Add(time.Minute).Format(time.RFC3339), at: at.Add(time.Minute), data: testJPEG(t, 240, 320, color.RGBA{0, 0, 200, 255})},
+	}
+	anim, err := buildSeriesGIF(frames, 160, 250*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, img := range anim.Image {
+		if b := img.Bounds(); b.Dx() != 160 || b.Dy() != 90 {
+			t.Fatalf("frame %d size %v, want the first frame's 160x90", i, b)
+		}
+	}
+	// The portrait frame is centered with black bars at the sides.
+	if r, g, bl, _ := anim.Image[1].At(2, 5).RGBA(); r>>8 > 20 || g>>8 > 20 || bl>>8 > 20 {
+		t.Fatalf("letterbox bar not black: %d %d %d", r>>8, g>>8, bl>>8)
+	}
+	if _, _, bl, _ := anim.Image[1].At(80, 5).RGBA(); bl>>8 < 150 {
+		t.Fatalf("portrait frame not drawn in the middle: b=%d", bl>>8)
+	}
+	if err := gif.EncodeAll(io.Discard, anim); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+}
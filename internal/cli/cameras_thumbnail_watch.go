@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type camerasThumbnailWatchFlags struct {
+	Cameras      []string
+	Interval     time.Duration
+	Count        int
+	Resolution   string
+	ViewProtocol string
+	Timeout      time.Duration
+}
+
+func newCamerasThumbnailWatchCmd(rf *rootFlags) *cobra.Command {
+	var f camerasThumbnailWatchFlags
+
+	cmd := &cobra.Command{
+		Use:   "watch [CAMERA...]",
+		Short: "Keep one or a few camera thumbnails refreshed in the terminal",
+		Long: strings.TrimSpace(`
+Fetches the latest thumbnail of each camera every --interval and redraws them in place with the
+inline image protocol of the terminal (see --view-protocol). Each image has a header with the
+fetch time, the image age and the fetch status.
+
+The API returns the closest cached thumbnail, so the same image is often returned several times
+in a row. Unchanged images are not redrawn; only the status line at the bottom is updated. The
+age is how old the returned image is, from the API's Last-Modified header ("-" when the API
+does not send one).
+
+Stop with Ctrl-C.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras thumbnail watch --camera CAM123
+  verkcli cameras thumbnail watch --camera "Front Door",CAM456 --interval 30s
+  verkcli cameras thumbnail watch CAM123 --view-protocol blocks
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			refs := splitCameraRefs(append(append([]string{}, f.Cameras...), args...))
+			if len(refs) == 0 {
+				return errors.New("--camera is required")
+			}
+			if f.Interval < time.Second {
+				return errors.New("--interval must be at least 1s")
+			}
+			if f.Resolution != "low-res" && f.Resolution != "hi-res" {
+				return fmt.Errorf("invalid --resolution %q (expected low-res or hi-res)", f.Resolution)
+			}
+			protocol, err := resolveViewProtocol(f.ViewProtocol, os.Getenv)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if !isTerminalWriter(out) {
+				return errors.New("watch redraws images in place and needs an interactive terminal; use `cameras thumbnail series` to save images over time")
+			}
+
+			cams := make([]cameraInfo, 0, len(refs))
+			for _, ref := range refs {
+				id, err := resolveCameraArg(*rf, cfg, ref)
+				if err != nil {
+					return err
+				}
+				info := cameraInfo{CameraID: id}
+				if cfg.Labels != nil {
+					info.Label = cfg.Labels.Cameras[id]
+				}
+				cams = append(cams, info)
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			fetch := newSharedThumbnailFetcher(client, cfg, rf, f.Resolution)
+			w := newThumbnailWatcher(cams, func(id string) ([]byte, http.Header, error) {
+				return fetch(id, time.Now().Unix())
+			}, out, protocol)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// Use the alternate screen so the shell's scrollback is restored on exit.
+			fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+			defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+			return w.Run(ctx, f.Interval, f.Count)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&f.Cameras, "camera", nil, "Camera references (camera_id or local label); repeat or comma-separate")
+	cmd.Flags().DurationVar(&f.Interval, "interval", 10*time.Second, "Time between refreshes")
+	cmd.Flags().IntVar(&f.Count, "count", 0, "Stop after this many refreshes (0 = until Ctrl-C)")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low-res", "Thumbnail resolution: low-res|hi-res")
+	cmd.Flags().StringVar(&f.ViewProtocol, "view-protocol", "auto", "Inline image protocol: "+viewProtocolNames())
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+type watchState struct {
+	hash      [32]byte
+	data      []byte
+	fetchedAt time.Time
+	// imageTime is the Last-Modified time of the last image; zero when the API sent none.
+	imageTime time.Time
+	err       string
+}
+
+// thumbnailWatcher fetches thumbnails on each tick and redraws only when an image (or its
+// fetch status) changed.
+type thumbnailWatcher struct {
+	cams     []cameraInfo
+	fetch    func(cameraID string) ([]byte, http.Header, error)
+	out      io.Writer
+	protocol string
+	now      func() time.Time
+	// columns is the terminal width, read from out on every redraw since frames are rendered
+	// into a buffer first and the terminal may have been resized.
+	columns func() int
+
+	states []watchState
+	drawn  bool
+}
+
+func newThumbnailWatcher(cams []cameraInfo, fetch func(string) ([]byte, http.Header, error), out io.Writer, protocol string) *thumbnailWatcher {
+	return &thumbnailWatcher{
+		cams:     cams,
+		fetch:    fetch,
+		out:      out,
+		protocol: protocol,
+		now:      time.Now,
+		columns:  func() int { return terminalColumns(out) },
+		states:   make([]watchState, len(cams)),
+	}
+}
+
+func (w *thumbnailWatcher) Run(ctx context.Context, interval time.Duration, count int) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for n := 1; ; n++ {
+		if _, err := w.Tick(); err != nil {
+			return err
+		}
+		if count > 0 && n >= count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Tick fetches every camera once and reports whether the screen was redrawn.
+func (w *thumbnailWatcher) Tick() (bool, error) {
+	changed := !w.drawn
+	for i, c := range w.cams {
+		st := &w.states[i]
+		b, hdr, err := w.fetch(c.CameraID)
+		st.fetchedAt = w.now()
+		if err != nil {
+			if st.err != err.Error() {
+				changed = true
+			}
+			st.err = err.Error()
+			continue
+		}
+		if st.err != "" {
+			changed = true
+		}
+		st.err = ""
+		st.imageTime = time.Time{}
+		if t, err := http.ParseTime(hdr.Get("Last-Modified")); err == nil {
+			st.imageTime = t
+		}
+		if sum := sha256.Sum256(b); sum != st.hash || st.data == nil {
+			st.hash, st.data = sum, b
+			changed = true
+		}
+	}
+
+	if !changed {
+		_, err := fmt.Fprintf(w.out, "\r\x1b[2K%s", w.statusLine())
+		return false, err
+	}
+
+	cols := w.columns()
+	var buf bytes.Buffer
+	buf.WriteString(termClearScreen(w.protocol))
+	for i, c := range w.cams {
+		buf.WriteString(w.header(c, w.states[i]))
+		buf.WriteByte('\n')
+		if w.states[i].data != nil {
+			if err := renderInlineImageCols(&buf, w.protocol, w.states[i].data, fmt.Sprintf("thumbnail_%s.jpg", c.CameraID), cols); err != nil {
+				fmt.Fprintf(&buf, "render: %v\n", err)
+			}
+		}
+	}
+	buf.WriteString(w.statusLine())
+	w.drawn = true
+	_, err := w.out.Write(buf.Bytes())
+	return true, err
+}
+
+func (w *thumbnailWatcher) header(c cameraInfo, st watchState) string {
+	name := c.DisplayName()
+	if name != c.CameraID {
+		name += " (" + c.CameraID + ")"
+	}
+	status := "ok"
+	if st.err != "" {
+		status = "error: " + st.err
+		if st.data != nil {
+			status += " (showing last image)"
+		}
+	}
+	return fmt.Sprintf("%s  fetched %s  age %s  %s", name, st.fetchedAt.Local().Format("15:04:05"), w.age(st), status)
+}
+
+// age is how old the current image is according to its Last-Modified time, or "-".
+func (w *thumbnailWatcher) age(st watchState) string {
+	if st.imageTime.IsZero() {
+		return "-"
+	}
+	return max(w.now().Sub(st.imageTime), 0).Round(time.Second).String()
+}
+
+func (w *thumbnailWatcher) statusLine() string {
+	var ages []string
+	for i, c := range w.cams {
+		if st := w.states[i]; st.data != nil {
+			ages = append(ages, fmt.Sprintf("%s %s", c.DisplayName(), w.age(st)))
+		}
+	}
+	line := "checked " + w.now().Local().Format("15:04:05")
+	if len(ages) > 0 {
+		line += " - ages: " + strings.Join(ages, ", ")
+	}
+	return line + " - Ctrl-C to stop"
+}
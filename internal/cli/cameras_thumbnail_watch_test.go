@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThumbnailWatcher_SkipsUnchangedFrames(t *testing.T) {
+	red := testJPEG(t, 8, 8, color.RGBA{255, 0, 0, 255})
+	blue := testJPEG(t, 8, 8, color.RGBA{0, 0, 255, 255})
+	responses := [][]byte{red, red, blue, nil}
+	// The red image is five minutes old; the blue one comes without Last-Modified.
+	modified := []string{"Sun, 15 Feb 2026 13:55:00 GMT", "Sun, 15 Feb 2026 13:55:00 GMT", "", ""}
+	tick := 0
+	fetch := func(id string) ([]byte, http.Header, error) {
+		b := responses[tick]
+		if b == nil {
+			return nil, nil, errors.New("thumbnail request failed with status 503")
+		}
+		hdr := http.Header{}
+		if modified[tick] != "" {
+			hdr.Set("Last-Modified", modified[tick])
+		}
+		return b, hdr, nil
+	}
+
+	var out bytes.Buffer
+	w := newThumbnailWatcher([]cameraInfo{{CameraID: "CAM-1", Label: "Lobby"}}, fetch, &out, "blocks")
+	clock := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return clock }
+
+	step := func() (bool, string) {
+		out.Reset()
+		redrawn, err := w.Tick()
+		if err != nil {
+			t.Fatalf("tick %d: %v", tick, err)
+		}
+		tick++
+		clock = clock.Add(10 * time.Second)
+		return redrawn, out.String()
+	}
+
+	redrawn, s := step()
+	if !redrawn || !strings.HasPrefix(s, "\x1b[H\x1b[2J") || !strings.Contains(s, "Lobby (CAM-1)  fetched") || !strings.Contains(s, "age 5m0s  ok") || !strings.Contains(s, "▀") {
+		t.Fatalf("first tick should draw: %q", s)
+	}
+
+	redrawn, s = step()
+	if redrawn || strings.Contains(s, "▀") || !strings.HasPrefix(s, "\r\x1b[2Kchecked ") || !strings.Contains(s, "Lobby 5m10s") {
+		t.Fatalf("unchanged image should only update the status line: %q", s)
+	}
+
+	redrawn, s = step()
+	if !redrawn || !strings.Contains(s, "age -  ok") {
+		t.Fatalf("changed image without Last-Modified should redraw with an unknown age: %q", s)
+	}
+
+	redrawn, s = step()
+	if !redrawn || !strings.Contains(s, "error: thumbnail request failed with status 503 (showing last image)") || !strings.Contains(s, "▀") {
+		t.Fatalf("fetch error should redraw with the last image: %q", s)
+	}
+}
+
+func TestTermClearScreen(t *testing.T) {
+	t.Setenv("TMUX", "")
+	if got := termClearScreen("blocks"); got != "\x1b[H\x1b[2J\x1b[3J" {
+		t.Fatalf("blocks: %q", got)
+	}
+	if got := termClearScreen("kitty"); !strings.HasPrefix(got, "\x1b_Ga=d,q=2\x1b\\") {
+		t.Fatalf("kitty: %q", got)
+	}
+}
+
+func TestThumbnailWatcher_UsesTerminalWidth(t *testing.T) {
+	img := testJPEG(t, 16, 16, color.RGBA{0, 255, 0, 255})
+	var out bytes.Buffer
+	w := newThumbnailWatcher([]cameraInfo{{CameraID: "CAM-1"}}, func(string) ([]byte, http.Header, error) { return img, nil, nil }, &out, "blocks")
+	w.columns = func() int { return 9 }
+	if _, err := w.Tick(); err != nil {
+		t.Fatal(err)
+	}
+	rows := 0
+	for _, line := range strings.Split(out.String(), "\n") {
+		if n := strings.Count(line, "▀"); n > 0 {
+			rows++
+			if n != 8 {
+				t.Fatalf("image row is %d cells wide, want 8 for a 9 column terminal: %q", n, line)
+			}
+		}
+	}
+	if rows == 0 {
+		t.Fatalf("no image drawn: %q", out.String())
+	}
+}
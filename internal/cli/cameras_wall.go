@@ -0,0 +1,490 @@
+package cli
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+//go:embed cameras_wall.html
+var camerasWallHTML []byte
+
+// wallAssets holds vendored static files for the wall page (see wallassets/README.md).
+//
+//go:embed wallassets
+var wallAssets embed.FS
+
+type camerasWallFlags struct {
+	Set        cameraSetFlags
+	Listen     string
+	Cols       int
+	Rows       int
+	Rotate     time.Duration
+	Resolution string
+	Codec      string
+	NoOpen     bool
+	Timeout    time.Duration
+	// FootageWindow is how much recent footage each tile's "footage" link plays.
+	FootageWindow time.Duration
+}
+
+func newCamerasWallCmd(rf *rootFlags) *cobra.Command {
+	var f camerasWallFlags
+
+	cmd := &cobra.Command{
+		Use:   "wall [CAMERA...]",
+		Short: "Serve a local web page with a grid of live camera streams",
+		Long: strings.TrimSpace(`
+Starts a local HTTP server and opens a browser page showing live HLS streams in a grid.
+
+Cameras are selected by --site, --query, --all, or by camera references (camera_id, local
+label, or exact camera name). When more cameras are selected than fit in the grid, the page
+rotates through them every --rotate interval.
+
+Each tile links to its camera's last --footage-window of recorded footage as a playlist for an
+external player (VLC, Safari), so you can look back at something the live tile just showed.
+
+The server proxies playlists and segments so the browser never needs API credentials;
+streaming JWTs are refreshed automatically. Press Ctrl-C to stop.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras wall --site HQ
+  verkcli cameras wall --query lobby --cols 2 --rows 2
+  verkcli cameras wall "Front Door" CAM123 --no-open --listen 127.0.0.1:8080
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if f.Cols <= 0 || f.Rows <= 0 {
+				return errors.New("--cols and --rows must be positive")
+			}
+			if f.FootageWindow < time.Minute {
+				return errors.New("--footage-window must be at least 1m")
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
+				return err
+			}
+			if strings.TrimSpace(cfg.OrgID) == "" {
+				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
+			}
+
+			cams, err := resolveCameraSet(client, &cfg, rf, f.Set, args)
+			if err != nil {
+				return err
+			}
+
+			srv, err := newWallServer(client, cfg, rf, f, cams)
+			if err != nil {
+				return err
+			}
+			// Fail fast on auth problems instead of showing a wall of broken tiles.
+			if _, err := srv.jwt.JWT(); err != nil {
+				return err
+			}
+
+			ln, err := net.Listen("tcp", f.Listen)
+			if err != nil {
+				return err
+			}
+			pageURL := "http://" + ln.Addr().String() + "/"
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			hs := &http.Server{Handler: srv.Handler(ln.Addr()), ReadHeaderTimeout: 10 * time.Second}
+			errCh := make(chan error, 1)
+			go func() { errCh <- hs.Serve(ln) }()
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "serving %d cameras at %s (Ctrl-C to stop)\n", len(srv.tiles), pageURL)
+			if !f.NoOpen {
+				if err := openBrowser(pageURL); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "could not open browser: %v\n", err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+			case err := <-errCh:
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					return err
+				}
+			}
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return hs.Shutdown(shutdownCtx)
+		},
+	}
+
+	addCameraSetFlags(cmd, &f.Set)
+	cmd.Flags().StringVar(&f.Listen, "listen", "127.0.0.1:0", "Address for the local server (port 0 picks a free port)")
+	cmd.Flags().IntVar(&f.Cols, "cols", 3, "Grid columns")
+	cmd.Flags().IntVar(&f.Rows, "rows", 3, "Grid rows")
+	cmd.Flags().DurationVar(&f.Rotate, "rotate", 30*time.Second, "Rotate to the next page of cameras at this interval (0 disables)")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low_res", "Stream resolution: low_res|high_res")
+	cmd.Flags().StringVar(&f.Codec, "codec", "h264", "Stream codec: h264|hevc (browsers generally require h264)")
+	cmd.Flags().BoolVar(&f.NoOpen, "no-open", false, "Don't open a browser; just print the URL")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout for upstream requests")
+	cmd.Flags().DurationVar(&f.FootageWindow, "footage-window", 15*time.Minute, "Recent footage played by each tile's footage link")
+	return cmd
+}
+
+type wallTile struct {
+	CameraID     string `json:"camera_id"`
+	Title        string `json:"title"`
+	Name         string `json:"name,omitempty"`
+	Site         string `json:"site,omitempty"`
+	Label        string `json:"label,omitempty"`
+	StreamURL    string `json:"stream_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	FootageURL   string `json:"footage_url"`
+}
+
+type wallServer struct {
+	client *http.Client
+	cfg    Config
+	rf     *rootFlags
+	jwt    *streamingJWTSource
+
+	upstream   *url.URL
+	resolution string
+	codec      string
+	cols       int
+	rows       int
+	rotate     time.Duration
+	title      string
+	window     time.Duration
+	now        func() time.Time
+
+	tiles []wallTile
+	known map[string]bool
+}
+
+func newWallServer(client *http.Client, cfg Config, rf *rootFlags, f camerasWallFlags, cams []map[string]any) (*wallServer, error) {
+	bu, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	s := &wallServer{
+		client:     client,
+		cfg:        cfg,
+		rf:         rf,
+		jwt:        newStreamingJWTSource(client, cfg, rf),
+		upstream:   bu,
+		resolution: f.Resolution,
+		codec:      f.Codec,
+		cols:       f.Cols,
+		rows:       f.Rows,
+		rotate:     f.Rotate,
+		window:     f.FootageWindow,
+		now:        time.Now,
+		title:      firstNonEmpty(f.Set.Site, f.Set.Query, "verkcli camera wall"),
+		known:      map[string]bool{},
+	}
+	for _, c := range cams {
+		info := newCameraInfo(c, cfg.Labels)
+		if info.CameraID == "" || s.known[info.CameraID] {
+			continue
+		}
+		s.known[info.CameraID] = true
+		id := url.PathEscape(info.CameraID)
+		s.tiles = append(s.tiles, wallTile{
+			CameraID:     info.CameraID,
+			Title:        info.DisplayName(),
+			Name:         info.Name,
+			Site:         info.Site,
+			Label:        info.Label,
+			StreamURL:    "/stream/" + id + "/live.m3u8",
+			ThumbnailURL: "/thumbnail/" + id,
+			FootageURL:   "/footage/" + id,
+		})
+	}
+	return s, nil
+}
+
+// Handler serves the wall on the listener bound to addr. Requests whose Host header names
+// anything else are rejected, so a DNS-rebinding page can't use the server's credentials.
+func (s *wallServer) Handler(addr net.Addr) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(camerasWallHTML)
+	})
+	mux.HandleFunc("GET /hls.min.js", func(w http.ResponseWriter, r *http.Request) {
+		b, err := wallAssets.ReadFile("wallassets/hls.min.js")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		_, _ = w.Write(b)
+	})
+	mux.HandleFunc("GET /api/wall", s.handleConfig)
+	mux.HandleFunc("GET /stream/{camera}/live.m3u8", s.handleStream)
+	mux.HandleFunc("GET /proxy", s.handleProxy)
+	mux.HandleFunc("GET /thumbnail/{camera}", s.handleThumbnail)
+	mux.HandleFunc("GET /footage/{camera}", s.handleFootage)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !wallHostAllowed(r.Host, addr.String()) {
+			http.Error(w, "host not allowed", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// wallHostAllowed reports whether a request Host header names the wall's own listener:
+// the bound address itself or localhost on the bound port. When bound to a wildcard
+// address, any IP literal on the port is accepted too; rebinding needs a DNS name.
+func wallHostAllowed(host, listenAddr string) bool {
+	if strings.EqualFold(host, listenAddr) {
+		return true
+	}
+	h, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return false
+	}
+	lh, lport, err := net.SplitHostPort(listenAddr)
+	if err != nil || port != lport {
+		return false
+	}
+	if strings.EqualFold(h, "localhost") {
+		return true
+	}
+	if ip := net.ParseIP(lh); ip != nil && ip.IsUnspecified() {
+		return net.ParseIP(h) != nil
+	}
+	return false
+}
+
+func (s *wallServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	blob, err := json.MarshalIndent(map[string]any{
+		"title":          s.title,
+		"cols":           s.cols,
+		"rows":           s.rows,
+		"rotate_seconds": int(s.rotate / time.Second),
+		"tiles":          s.tiles,
+	}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(blob)
+}
+
+func (s *wallServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	cameraID := r.PathValue("camera")
+	if !s.known[cameraID] {
+		http.NotFound(w, r)
+		return
+	}
+	u, err := buildFootageStreamM3U8URL(s.cfg.BaseURL, s.cfg.OrgID, cameraID, "", 0, 0, s.resolution, s.codec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.proxy(w, u)
+}
+
+func (s *wallServer) handleProxy(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("u")
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() {
+		http.Error(w, "invalid upstream url", http.StatusBadRequest)
+		return
+	}
+	// Only ever forward to the configured API host; this is not a general-purpose proxy.
+	if !strings.EqualFold(u.Host, s.upstream.Host) || u.Scheme != s.upstream.Scheme {
+		http.Error(w, "upstream host not allowed", http.StatusForbidden)
+		return
+	}
+	s.proxy(w, raw)
+}
+
+func (s *wallServer) handleThumbnail(w http.ResponseWriter, r *http.Request) {
+	cameraID := r.PathValue("camera")
+	if !s.known[cameraID] {
+		http.NotFound(w, r)
+		return
+	}
+	cfg := s.cfg
+	b, _, err := fetchThumbnailJPEG(s.client, &cfg, s.rf, cameraID, time.Now().Unix(), "low-res")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(b)
+}
+
+// handleFootage serves the camera's recorded footage of the last footage window as a playlist
+// for an external player (VLC, Safari). Like the grid it goes through the proxy, so the jwt
+// never leaves this server.
+func (s *wallServer) handleFootage(w http.ResponseWriter, r *http.Request) {
+	cameraID := r.PathValue("camera")
+	if !s.known[cameraID] {
+		http.NotFound(w, r)
+		return
+	}
+	end := s.now().Unix()
+	u, err := buildFootageStreamM3U8URL(s.cfg.BaseURL, s.cfg.OrgID, cameraID, "", end-int64(s.window/time.Second), end, s.resolution, s.codec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.proxy(w, u)
+}
+
+// proxy fetches an upstream HLS resource with a current JWT. Playlists are rewritten so every
+// nested URI points back at /proxy (without the jwt); other content is streamed through.
+func (s *wallServer) proxy(w http.ResponseWriter, rawURL string) {
+	resp, err := s.fetchUpstream(rawURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		b, _ := ioReadAllLimit(resp.Body, 64*1024)
+		w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(b)
+		return
+	}
+
+	ct := resp.Header.Get("Content-Type")
+	if isM3U8ContentType(ct) || strings.HasSuffix(strings.ToLower(resp.Request.URL.Path), ".m3u8") {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		out, err := rewriteM3U8ForWallProxy(b, resp.Request.URL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(out)
+		return
+	}
+
+	if ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	if cl := resp.Header.Get("Content-Length"); cl != "" {
+		w.Header().Set("Content-Length", cl)
+	}
+	_, _ = io.Copy(w, resp.Body)
+}
+
+func (s *wallServer) fetchUpstream(rawURL string) (*http.Response, error) {
+	do := func() (*http.Response, error) {
+		jwt, err := s.jwt.JWT()
+		if err != nil {
+			return nil, err
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		q := u.Query()
+		q.Set("jwt", jwt)
+		u.RawQuery = q.Encode()
+
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		applyDefaultHeaders(req, s.cfg)
+		if err := applyHeaderFlags(req, s.rf.Headers); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if s.rf.Debug {
+			fmt.Fprintf(os.Stderr, "HTTP %s %s -> %d (%s)\n", req.Method, redactURLSecrets(req.URL.String()), resp.StatusCode, time.Since(start))
+		}
+		return resp, nil
+	}
+
+	resp, err := do()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		// The cached JWT may have been revoked or expired early; refresh once.
+		resp.Body.Close()
+		s.jwt.Invalidate()
+		return do()
+	}
+	return resp, nil
+}
+
+// rewriteM3U8ForWallProxy points every playlist URI at the local /proxy endpoint. The jwt is
+// stripped so it never reaches the browser; the proxy re-adds a fresh one per request.
+func rewriteM3U8ForWallProxy(in []byte, playlistURL *url.URL) ([]byte, error) {
+	required := playlistURL.Query()
+	required.Del("jwt")
+	return mapM3U8URIs(in, playlistURL, func(u *url.URL) string {
+		q := u.Query()
+		for k, vals := range required {
+			if q.Has(k) {
+				continue
+			}
+			for _, v := range vals {
+				q.Add(k, v)
+			}
+		}
+		q.Del("jwt")
+		u.RawQuery = q.Encode()
+		return "/proxy?u=" + url.QueryEscape(u.String())
+	})
+}
+
+func isM3U8ContentType(ct string) bool {
+	ct = strings.ToLower(ct)
+	return strings.Contains(ct, "mpegurl") || strings.Contains(ct, "m3u8")
+}
+
+func openBrowser(u string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", u)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		c = exec.Command("xdg-open", u)
+	}
+	if err := c.Start(); err != nil {
+		return err
+	}
+	go func() { _ = c.Wait() }()
+	return nil
+}
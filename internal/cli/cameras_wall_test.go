@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRewriteM3U8ForWallProxy_StripsJWT(t *testing.T) {
+	playlistURL, _ := url.Parse("https://api.verkada.com/stream/cameras/v1/footage/stream/stream.m3u8?org_id=ORG&camera_id=CAM&jwt=SECRET")
+	in := "#EXTM3U\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:2.0,\nseg1.m4s\n"
+
+	out, err := rewriteM3U8ForWallProxy([]byte(in), playlistURL)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	s := string(out)
+	if strings.Contains(s, "SECRET") {
+		t.Fatalf("jwt leaked into rewritten playlist:\n%s", s)
+	}
+	if !strings.Contains(s, `URI="/proxy?u=`) || !strings.Contains(s, "\n/proxy?u=") {
+		t.Fatalf("expected proxied URIs, got:\n%s", s)
+	}
+	if !strings.Contains(s, url.QueryEscape("camera_id=CAM")) {
+		t.Fatalf("expected required params carried over, got:\n%s", s)
+	}
+}
+
+func TestWallServer_ProxiesPlaylistAndSegments(t *testing.T) {
+	var tokenCalls int32
+	var lastWindow atomic.Value
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/footage/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenCalls, 1)
+		fmt.Fprintf(w, `{"jwt":"jwt-1","expiration":1800}`)
+	})
+	mux.HandleFunc("/stream/cameras/v1/footage/stream/stream.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("jwt") != "jwt-1" {
+			w.WriteHeader(401)
+			return
+		}
+		lastWindow.Store(r.URL.Query().Get("start_time") + "-" + r.URL.Query().Get("end_time"))
+		w.Header().Set("Content-Type", "application/x-mpegURL")
+		fmt.Fprint(w, "#EXTM3U\n#EXTINF:2.0,\nseg1.ts\n")
+	})
+	mux.HandleFunc("/stream/cameras/v1/footage/stream/seg1.ts", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("jwt") != "jwt-1" || r.URL.Query().Get("camera_id") != "cam-1" {
+			w.WriteHeader(401)
+			return
+		}
+		w.Header().Set("Content-Type", "video/mp2t")
+		fmt.Fprint(w, "SEGMENT")
+	})
+	upstream := httptest.NewServer(mux)
+	t.Cleanup(upstream.Close)
+
+	cfg := Config{BaseURL: upstream.URL, OrgID: "org-1", Headers: map[string]string{}}
+	f := camerasWallFlags{Cols: 2, Rows: 2, Resolution: "low_res", Codec: "h264", FootageWindow: 10 * time.Minute}
+	srv, err := newWallServer(upstream.Client(), cfg, &rootFlags{}, f, []map[string]any{{"camera_id": "cam-1", "name": "Door"}})
+	if err != nil {
+		t.Fatalf("newWallServer: %v", err)
+	}
+	srv.now = func() time.Time { return time.Unix(1771000000, 0) }
+	local := httptest.NewUnstartedServer(nil)
+	local.Config.Handler = srv.Handler(local.Listener.Addr())
+	local.Start()
+	t.Cleanup(local.Close)
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(local.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	status, playlist := get("/stream/cam-1/live.m3u8")
+	if status != 200 {
+		t.Fatalf("playlist status %d: %s", status, playlist)
+	}
+	if got := lastWindow.Load(); got != "0-0" {
+		t.Fatalf("live playlist window = %v", got)
+	}
+	var segPath string
+	for _, line := range strings.Split(playlist, "\n") {
+		if strings.HasPrefix(line, "/proxy?") {
+			segPath = line
+		}
+	}
+	if segPath == "" {
+		t.Fatalf("no proxied segment in playlist:\n%s", playlist)
+	}
+	status, body := get(segPath)
+	if status != 200 || body != "SEGMENT" {
+		t.Fatalf("segment status=%d body=%q", status, body)
+	}
+	if n := atomic.LoadInt32(&tokenCalls); n != 1 {
+		t.Fatalf("expected jwt to be cached, got %d token calls", n)
+	}
+
+	// The footage link for external players is proxied too; no jwt reaches the browser.
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noRedirect.Get(local.URL + "/footage/cam-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || resp.Header.Get("Location") != "" || strings.Contains(string(b), "jwt") || !strings.Contains(string(b), "/proxy?") {
+		t.Fatalf("footage status=%d location=%q body:\n%s", resp.StatusCode, resp.Header.Get("Location"), b)
+	}
+	// The footage link plays the last --footage-window of recordings, not the live stream.
+	if got := lastWindow.Load(); got != "1770999400-1771000000" {
+		t.Fatalf("footage playlist window = %v", got)
+	}
+
+	if status, _ := get("/stream/other/live.m3u8"); status != 404 {
+		t.Fatalf("expected 404 for unknown camera, got %d", status)
+	}
+	if status, _ := get("/proxy?u=" + url.QueryEscape("https://evil.example.com/x.ts")); status != 403 {
+		t.Fatalf("expected 403 for foreign host, got %d", status)
+	}
+}
+
+func TestStreamingTokenExpiry(t *testing.T) {
+	now := time.Unix(1771000000, 0)
+	if got := streamingTokenExpiry(footageTokenResponseV1{ExpiresAt: 1771001800}, now); got.Unix() != 1771001800 {
+		t.Fatalf("expiresAt: got %d", got.Unix())
+	}
+	if got := streamingTokenExpiry(footageTokenResponseV1{ExpiresAt: 1771001800 * 1000}, now); got.Unix() != 1771001800 {
+		t.Fatalf("expiresAt ms: got %d", got.Unix())
+	}
+	if got := streamingTokenExpiry(footageTokenResponseV1{Expiration: 60}, now); got.Unix() != 1771000060 {
+		t.Fatalf("expiration: got %d", got.Unix())
+	}
+}
+
+func TestWallPage_LoadsHLSFromLocalServer(t *testing.T) {
+	page := string(camerasWallHTML)
+	if strings.Contains(page, "https://") || !strings.Contains(page, `<script src="/hls.min.js">`) {
+		t.Fatalf("wall page must load hls.js from the local server:\n%s", page)
+	}
+}
+
+func TestWallServer_ServesVendoredHLS(t *testing.T) {
+	b, err := wallAssets.ReadFile("wallassets/hls.min.js")
+	if err != nil {
+		t.Skip("wallassets/hls.min.js is not vendored; run scripts/vendor-hlsjs.sh")
+	}
+	if len(b) == 0 {
+		t.Fatal("wallassets/hls.min.js is empty")
+	}
+	srv, err := newWallServer(http.DefaultClient, Config{BaseURL: "https://api.verkada.com", OrgID: "org-1"}, &rootFlags{}, camerasWallFlags{Cols: 1, Rows: 1}, []map[string]any{{"camera_id": "cam-1"}})
+	if err != nil {
+		t.Fatalf("newWallServer: %v", err)
+	}
+	local := httptest.NewUnstartedServer(nil)
+	local.Config.Handler = srv.Handler(local.Listener.Addr())
+	local.Start()
+	t.Cleanup(local.Close)
+
+	resp, err := http.Get(local.URL + "/hls.min.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/javascript") || !bytes.Equal(got, b) {
+		t.Fatalf("GET /hls.min.js: status %d, type %q, %d bytes", resp.StatusCode, resp.Header.Get("Content-Type"), len(got))
+	}
+}
+
+func TestWallServer_RejectsForeignHost(t *testing.T) {
+	srv, err := newWallServer(http.DefaultClient, Config{BaseURL: "https://api.verkada.com", OrgID: "org-1"}, &rootFlags{}, camerasWallFlags{Cols: 1, Rows: 1}, []map[string]any{{"camera_id": "cam-1"}})
+	if err != nil {
+		t.Fatalf("newWallServer: %v", err)
+	}
+	local := httptest.NewUnstartedServer(nil)
+	local.Config.Handler = srv.Handler(local.Listener.Addr())
+	local.Start()
+	t.Cleanup(local.Close)
+	_, port, _ := net.SplitHostPort(local.Listener.Addr().String())
+
+	for host, want := range map[string]int{
+		local.Listener.Addr().String(): 200,
+		"localhost:" + port:            200,
+		"LOCALHOST:" + port:            200,
+		"evil.example.com:" + port:     403,
+		"evil.example.com":             403,
+		"localhost:1":                  403,
+		"10.0.0.5:" + port:             403,
+	} {
+		req, _ := http.NewRequest("GET", local.URL+"/api/wall", nil)
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET with Host %q: %v", host, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Host %q: status %d, want %d", host, resp.StatusCode, want)
+		}
+	}
+
+	if !wallHostAllowed("192.168.1.20:8080", "[::]:8080") || wallHostAllowed("evil.example.com:8080", "[::]:8080") {
+		t.Fatalf("wildcard listener should accept IP literals only")
+	}
+}
@@ -8,16 +8,23 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
 
 type Config struct {
+	// Extends names a parent profile whose values this profile inherits (see resolveProfile).
+	Extends string            `json:"extends,omitempty"`
 	BaseURL string            `json:"base_url"`
 	OrgID   string            `json:"org_id,omitempty"`
 	Auth    AuthConfig        `json:"auth,omitempty"`
 	Headers map[string]string `json:"headers,omitempty"`
 	Labels  *LocalLabels      `json:"labels,omitempty"`
+	// LabelStore is where `cameras label pull/push` sync labels (see parseLabelStore).
+	LabelStore string `json:"label_store,omitempty"`
+	// Capabilities is what login's preflight found the API key can do (see runLoginPreflight).
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
 }
 
 type AuthConfig struct {
@@ -26,8 +33,29 @@ type AuthConfig struct {
 	TokenAcquiredAt int64  `json:"token_acquired_at,omitempty"` // unix seconds
 }
 
+// Capabilities records the outcome of the login preflight checks for a profile's API key.
+type Capabilities struct {
+	CheckedAt string `json:"checked_at"` // RFC 3339, UTC
+	// Checks maps each preflight check (list_cameras, core_organization, footage_token,
+	// live_m3u8, thumbnail) to pass, skip or fail.
+	Checks map[string]string `json:"checks"`
+	// The remaining fields are copied from the footage token response.
+	Permissions       []string `json:"permissions,omitempty"`
+	AccessibleCameras []string `json:"accessible_cameras,omitempty"`
+	AccessibleSites   []string `json:"accessible_sites,omitempty"`
+}
+
+// LocalLabels is the local metadata kept per camera, keyed by camera_id: the primary label
+// (Cameras, the only field older versions know), a tag set and timestamped notes.
 type LocalLabels struct {
-	Cameras map[string]string `json:"cameras,omitempty"`
+	Cameras map[string]string       `json:"cameras,omitempty"`
+	Tags    map[string][]string     `json:"tags,omitempty"`
+	Notes   map[string][]CameraNote `json:"notes,omitempty"`
+}
+
+type CameraNote struct {
+	At   string `json:"at"` // RFC 3339, UTC
+	Text string `json:"text"`
 }
 
 // ConfigFile is the on-disk config format. It supports named profiles.
@@ -35,8 +63,10 @@ type LocalLabels struct {
 // Backward compatibility: legacy configs may contain top-level base_url/auth/headers,
 // which are treated as an implicit "default" profile on load.
 type ConfigFile struct {
-	CurrentProfile string            `json:"current_profile,omitempty"`
-	Profiles       map[string]Config `json:"profiles,omitempty"`
+	CurrentProfile string `json:"current_profile,omitempty"`
+	// Defaults is merged under every profile (see resolveProfile).
+	Defaults *Config           `json:"defaults,omitempty"`
+	Profiles map[string]Config `json:"profiles,omitempty"`
 
 	// Legacy fields (pre-profiles).
 	BaseURL string            `json:"base_url,omitempty"`
@@ -56,6 +86,12 @@ func defaultConfigPath() (string, error) {
 	if _, err := os.Stat(newPath); err == nil {
 		return newPath, nil
 	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		p := filepath.Join(dir, "verkcli", name)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
 	if _, err := os.Stat(legacyPath); err == nil {
 		return legacyPath, nil
 	}
@@ -68,6 +104,9 @@ func loadConfig(path string) (ConfigFile, error) {
 	if err != nil {
 		return cfg, err
 	}
+	if b, err = configToJSON(path, b); err != nil {
+		return cfg, err
+	}
 	if err := json.Unmarshal(b, &cfg); err != nil {
 		return cfg, err
 	}
@@ -89,8 +128,11 @@ func writeConfig(path string, cfg ConfigFile) error {
 	if err != nil {
 		return err
 	}
-	b = append(b, '\n')
-	return os.WriteFile(path, b, 0o600)
+	// Keep the format the file was written in (see configFormatFor).
+	if b, err = configFromJSON(path, b); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, b, 0o600)
 }
 
 func normalizeConfigFile(cfg *ConfigFile) {
@@ -166,6 +208,9 @@ func NewConfigCmd(rf *rootFlags) *cobra.Command {
 	cmd.AddCommand(newConfigViewCmd(rf))
 	cmd.AddCommand(newConfigUseCmd(rf))
 	cmd.AddCommand(newConfigProfilesCmd(rf))
+	cmd.AddCommand(newConfigDoctorCmd(rf))
+	cmd.AddCommand(newConfigExportCmd(rf))
+	cmd.AddCommand(newConfigImportCmd(rf))
 
 	return cmd
 }
@@ -180,19 +225,18 @@ func newConfigUseCmd(rf *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			cf, err := loadConfig(p)
-			if err != nil {
-				return err
-			}
 			name := strings.TrimSpace(args[0])
 			if name == "" {
 				return errors.New("profile name is empty")
 			}
-			if _, ok := cf.Profiles[name]; !ok {
-				return fmt.Errorf("profile %q not found in %s", name, p)
-			}
-			cf.CurrentProfile = name
-			if err := writeConfig(p, cf); err != nil {
+			err = updateConfig(p, func(cf *ConfigFile) error {
+				if _, ok := cf.Profiles[name]; !ok {
+					return fmt.Errorf("profile %q not found in %s", name, p)
+				}
+				cf.CurrentProfile = name
+				return nil
+			})
+			if err != nil {
 				return err
 			}
 			fmt.Fprintf(cmd.OutOrStdout(), "current profile: %s\n", name)
@@ -208,6 +252,7 @@ func newConfigProfilesCmd(rf *rootFlags) *cobra.Command {
 		Short: "Manage named profiles",
 	}
 	cmd.AddCommand(newConfigProfilesListCmd(rf))
+	addProfileManageCmds(cmd, rf)
 	return cmd
 }
 
@@ -287,6 +332,11 @@ func newConfigInitCmd(rf *rootFlags) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			unlock, err := lockConfig(p)
+			if err != nil {
+				return err
+			}
+			defer unlock()
 			if !force {
 				if _, err := os.Stat(p); err == nil {
 					return fmt.Errorf("config already exists at %s (use --force to overwrite)", p)
@@ -325,44 +375,77 @@ func newConfigInitCmd(rf *rootFlags) *cobra.Command {
 }
 
 func newConfigViewCmd(rf *rootFlags) *cobra.Command {
+	var explain bool
+
 	cmd := &cobra.Command{
 		Use:   "view",
 		Short: "Print the effective config (file + env + flags)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			profileName, ecfg, err := effectiveProfileConfig(*rf)
+			profileName, ecfg, src, err := effectiveProfileConfigSources(*rf)
 			if err != nil {
 				if errors.Is(err, os.ErrNotExist) {
 					// Still allow viewing env/flags-only config.
-					profileName = firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), "default")
-					ecfg = Config{
-						BaseURL: envFirst("", "VERKCLI_BASE_URL", "VERKADA_BASE_URL"),
-						OrgID:   envFirst("", "VERKCLI_ORG_ID", "VERKADA_ORG_ID"),
-						Auth: AuthConfig{
-							APIKey: envFirst("", "VERKCLI_API_KEY", "VERKADA_API_KEY"),
-							Token:  envFirst("", "VERKCLI_TOKEN", "VERKADA_TOKEN"),
-						},
-						Headers: map[string]string{},
+					if profileName, err = selectedProfileName(*rf, ConfigFile{}); err != nil {
+						return err
+					}
+					ecfg = Config{Headers: map[string]string{}}
+					src = configSources{}
+					if projPath, proj, err := discoverProjectConfig(); err == nil && projPath != "" {
+						if err := applyProjectConfig(&ecfg, proj, projPath, src, projectTrusted(*rf)); err != nil {
+							return err
+						}
 					}
+					applyConfigOverrides(&ecfg, rootFlags{}, src)
 				} else {
 					return err
 				}
 			}
 
+			out := cmd.OutOrStdout()
+			files := configFilesInUse(*rf)
+			if explain {
+				values := explainConfig(ecfg, src)
+				if rf.Output == "json" {
+					b, err := json.MarshalIndent(map[string]any{
+						"profile": profileName,
+						"files":   files,
+						"values":  values,
+					}, "", "  ")
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(out, string(b))
+					return nil
+				}
+				fmt.Fprintf(out, "profile: %s\n", profileName)
+				writeConfigFilesText(out, files)
+				tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+				for _, v := range values {
+					fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Key, v.Value, v.Source)
+				}
+				return tw.Flush()
+			}
+
 			view := struct {
-				Profile string `json:"profile"`
+				Profile string           `json:"profile"`
+				Files   []configFileInfo `json:"files"`
 				Config
 			}{
 				Profile: profileName,
+				Files:   files,
 				Config:  ecfg,
 			}
 			b, err := json.MarshalIndent(view, "", "  ")
 			if err != nil {
 				return err
 			}
-			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			fmt.Fprintln(out, string(b))
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show which layer (defaults, profile, env, flag) set each value")
 	return cmd
 }
 
@@ -372,57 +455,62 @@ func effectiveConfig(rf rootFlags) (Config, error) {
 }
 
 func effectiveProfileConfig(rf rootFlags) (string, Config, error) {
+	name, cfg, _, err := effectiveProfileConfigSources(rf)
+	return name, cfg, err
+}
+
+// effectiveProfileConfigSources resolves the selected profile (defaults, extends chain, profile),
+// then applies the project config and env and flag overrides, recording where each value came
+// from.
+func effectiveProfileConfigSources(rf rootFlags) (string, Config, configSources, error) {
 	p, err := resolveConfigPath(rf.ConfigPath)
 	if err != nil {
-		return "", Config{}, err
+		return "", Config{}, nil, err
 	}
 
 	cf, err := loadConfig(p)
 	if err != nil {
-		return "", Config{}, err
+		return "", Config{}, nil, err
 	}
-
-	profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
-	profile, ok := cf.Profiles[profileName]
-	if !ok {
-		return "", Config{}, fmt.Errorf("profile %q not found in %s", profileName, p)
+	profileName, profile, src, err := resolveEffectiveConfig(rf, p, cf)
+	if err != nil {
+		return "", Config{}, nil, err
 	}
 
-	// Env overrides config.
-	if v := envFirst("", "VERKCLI_BASE_URL", "VERKADA_BASE_URL"); v != "" {
-		profile.BaseURL = v
-	}
-	if v := envFirst("", "VERKCLI_ORG_ID", "VERKADA_ORG_ID"); v != "" {
-		profile.OrgID = v
-	}
-	if v := envFirst("", "VERKCLI_API_KEY", "VERKADA_API_KEY"); v != "" {
-		profile.Auth.APIKey = v
+	if profile.BaseURL == "" {
+		return "", Config{}, nil, errors.New("base URL is empty (set in config, VERKCLI_BASE_URL / VERKADA_BASE_URL, or --base-url)")
 	}
-	if v := envFirst("", "VERKCLI_TOKEN", "VERKADA_TOKEN"); v != "" {
-		profile.Auth.Token = v
+	if profile.Headers == nil {
+		profile.Headers = map[string]string{}
 	}
+	return profileName, profile, src, nil
+}
 
-	// Flags override env/config.
-	if rf.BaseURL != "" {
-		profile.BaseURL = rf.BaseURL
-	}
-	if rf.OrgID != "" {
-		profile.OrgID = rf.OrgID
+// resolveEffectiveConfig applies inheritance, the project config, env and flags to the profile
+// selected from cf (loaded from path p).
+func resolveEffectiveConfig(rf rootFlags, p string, cf ConfigFile) (string, Config, configSources, error) {
+	projPath, proj, err := discoverProjectConfig()
+	if err != nil {
+		return "", Config{}, nil, err
 	}
-	if rf.APIKey != "" {
-		profile.Auth.APIKey = rf.APIKey
+
+	profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), proj.Profile, cf.CurrentProfile, "default")
+	if _, ok := cf.Profiles[profileName]; !ok {
+		return "", Config{}, nil, fmt.Errorf("profile %q not found in %s", profileName, p)
 	}
-	if rf.Token != "" {
-		profile.Auth.Token = rf.Token
+	profile, src, err := resolveProfile(cf, profileName)
+	if err != nil {
+		return "", Config{}, nil, fmt.Errorf("%w (in %s)", err, p)
 	}
 
-	if profile.BaseURL == "" {
-		return "", Config{}, errors.New("base URL is empty (set in config, VERKCLI_BASE_URL / VERKADA_BASE_URL, or --base-url)")
-	}
-	if profile.Headers == nil {
-		profile.Headers = map[string]string{}
+	// The project config overrides the user config; env overrides both; flags override env/config.
+	if projPath != "" {
+		if err := applyProjectConfig(&profile, proj, projPath, src, projectTrusted(rf)); err != nil {
+			return "", Config{}, nil, err
+		}
 	}
-	return profileName, profile, nil
+	applyConfigOverrides(&profile, rf, src)
+	return profileName, profile, src, nil
 }
 
 func envFirst(def string, keys ...string) string {
@@ -436,26 +524,26 @@ func envFirst(def string, keys ...string) string {
 
 func persistProfileOrgID(rf rootFlags, orgID string) error {
 	orgID = strings.TrimSpace(orgID)
-	if orgID == "" {
+	if orgID == "" || rf.noPersist {
 		return nil
 	}
 	p, err := resolveConfigPath(rf.ConfigPath)
 	if err != nil {
 		return err
 	}
-	cf, err := loadConfig(p)
-	if err != nil {
-		return err
-	}
-	normalizeConfigFile(&cf)
-	profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
-	profile, ok := cf.Profiles[profileName]
-	if !ok {
-		return fmt.Errorf("profile %q not found in %s", profileName, p)
-	}
-	profile.OrgID = orgID
-	cf.Profiles[profileName] = profile
-	return writeConfig(p, cf)
+	return updateConfig(p, func(cf *ConfigFile) error {
+		profileName, err := selectedProfileName(rf, *cf)
+		if err != nil {
+			return err
+		}
+		profile, ok := cf.Profiles[profileName]
+		if !ok {
+			return fmt.Errorf("profile %q not found in %s", profileName, p)
+		}
+		profile.OrgID = orgID
+		cf.Profiles[profileName] = profile
+		return nil
+	})
 }
 
 var errNoBody = errors.New("no body provided")
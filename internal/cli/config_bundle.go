@@ -0,0 +1,580 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	bundleVersion       = 1
+	bundleKDF           = "pbkdf2-sha256"
+	bundleKDFIterations = 600_000
+	bundlePassphraseEnv = "VERKCLI_BUNDLE_PASSPHRASE"
+	bundleAAD           = "verkcli-bundle-v1"
+)
+
+// configBundle is the file written by `config export`. Profiles and defaults never carry auth;
+// with --include-secrets the auth of each profile (and of the defaults, under
+// bundleDefaultsSecret) is stored encrypted in Secrets.
+type configBundle struct {
+	Version    int               `json:"verkcli_bundle"`
+	ExportedAt string            `json:"exported_at"`
+	Defaults   *Config           `json:"defaults,omitempty"`
+	Profiles   map[string]Config `json:"profiles"`
+	Secrets    *bundleSecrets    `json:"secrets,omitempty"`
+}
+
+// bundleDefaultsSecret is the Secrets entry for the defaults block's auth. It has a space, so no
+// profile can be named like it.
+const bundleDefaultsSecret = "defaults auth"
+
+// bundleSecrets is an AES-256-GCM encrypted JSON object of profile name to AuthConfig, keyed with
+// PBKDF2-SHA256 from a passphrase.
+type bundleSecrets struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func newConfigExportCmd(rf *rootFlags) *cobra.Command {
+	var profiles []string
+	var includeSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write profiles and camera labels as a bundle for another machine",
+		Long: strings.TrimSpace(`
+Writes a JSON bundle of profiles (default: all) to stdout. Profiles named with extends bring
+their parents along, and the defaults block is included.
+
+Credentials are left out unless --include-secrets is set; they are then encrypted with a
+passphrase read from ` + bundlePassphraseEnv + ` or prompted for.
+`),
+		Example: strings.TrimSpace(`
+  verkcli config export --profiles prod,eu > bundle.json
+  verkcli config export --include-secrets > bundle.json
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cf, err := loadProfilesConfig(rf)
+			if err != nil {
+				return err
+			}
+			names, err := bundleProfileNames(cf, profiles)
+			if err != nil {
+				return fmt.Errorf("%w in %s", err, p)
+			}
+
+			bundle := configBundle{
+				Version:    bundleVersion,
+				ExportedAt: time.Now().UTC().Format(time.RFC3339),
+				Defaults:   cf.Defaults,
+				Profiles:   map[string]Config{},
+			}
+			secrets := map[string]AuthConfig{}
+			for _, n := range names {
+				prof := cloneProfile(cf.Profiles[n])
+				if prof.Auth.APIKey != "" || prof.Auth.Token != "" {
+					secrets[n] = prof.Auth
+				}
+				prof.Auth = AuthConfig{}
+				bundle.Profiles[n] = prof
+			}
+			if bundle.Defaults != nil {
+				d := *bundle.Defaults
+				if d.Auth.APIKey != "" || d.Auth.Token != "" {
+					secrets[bundleDefaultsSecret] = d.Auth
+				}
+				d.Auth = AuthConfig{}
+				bundle.Defaults = &d
+			}
+			if includeSecrets && len(secrets) > 0 {
+				pass, err := bundlePassphrase(cmd, true)
+				if err != nil {
+					return err
+				}
+				if bundle.Secrets, err = sealBundleSecrets(secrets, pass); err != nil {
+					return err
+				}
+			}
+
+			b, err := json.MarshalIndent(bundle, "", "  ")
+			if err != nil {
+				return err
+			}
+			_, _ = cmd.OutOrStdout().Write(append(b, '\n'))
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %d profile(s): %s\n", len(names), strings.Join(names, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&profiles, "profiles", nil, "Profiles to export (comma-separated; default: all)")
+	cmd.Flags().BoolVar(&includeSecrets, "include-secrets", false, "Include API keys and tokens, encrypted with a passphrase")
+	return cmd
+}
+
+// bundleProfileNames expands the requested profiles with their extends ancestors, sorted.
+func bundleProfileNames(cf ConfigFile, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		for n := range cf.Profiles {
+			requested = append(requested, n)
+		}
+	}
+	set := map[string]bool{}
+	for _, n := range requested {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		chain, err := profileChain(cf, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range chain {
+			set[c] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil, errors.New("no profiles to export")
+	}
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func bundlePassphrase(cmd *cobra.Command, confirm bool) (string, error) {
+	if v := os.Getenv(bundlePassphraseEnv); v != "" {
+		return v, nil
+	}
+	in := bufio.NewReader(cmd.InOrStdin())
+	pass, err := promptStringFrom(cmd, in, "Bundle passphrase", "", true /* secret */)
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", fmt.Errorf("passphrase is empty (set %s or type one at the prompt)", bundlePassphraseEnv)
+	}
+	if confirm {
+		again, err := promptStringFrom(cmd, in, "Repeat passphrase", "", true /* secret */)
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
+func bundleKey(pass string, salt []byte, iterations int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, pass, salt, iterations, 32)
+}
+
+func sealBundleSecrets(secrets map[string]AuthConfig, pass string) (*bundleSecrets, error) {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	s := &bundleSecrets{KDF: bundleKDF, Iterations: bundleKDFIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := bundleCipher(pass, s)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Ciphertext = gcm.Seal(nil, s.Nonce, plain, []byte(bundleAAD))
+	return s, nil
+}
+
+func openBundleSecrets(s *bundleSecrets, pass string) (map[string]AuthConfig, error) {
+	if s.KDF != bundleKDF {
+		return nil, fmt.Errorf("unsupported bundle kdf %q", s.KDF)
+	}
+	if s.Iterations < 100_000 || s.Iterations > 10_000_000 {
+		return nil, fmt.Errorf("bundle kdf iterations %d out of range", s.Iterations)
+	}
+	gcm, err := bundleCipher(pass, s)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, s.Nonce, s.Ciphertext, []byte(bundleAAD))
+	if err != nil {
+		return nil, errors.New("cannot decrypt bundle secrets: wrong passphrase or corrupted bundle")
+	}
+	var out map[string]AuthConfig
+	if err := json.Unmarshal(plain, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func bundleCipher(pass string, s *bundleSecrets) (cipher.AEAD, error) {
+	key, err := bundleKey(pass, s.Salt, s.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// importConflict is a key set differently in the config and the bundle.
+type importConflict struct {
+	Profile string `json:"profile"`
+	Key     string `json:"key"`
+	Ours    string `json:"ours"`
+	Theirs  string `json:"theirs"`
+	Kept    string `json:"kept"` // ours or theirs
+}
+
+// importStrategy picks ours or theirs for conflicting keys. Rules match a key exactly or by
+// dotted prefix ("headers" covers "headers.X-Team"); the longest match wins; the default is ours.
+type importStrategy map[string]string
+
+func parseImportStrategy(rules []string) (importStrategy, error) {
+	s := importStrategy{}
+	for _, r := range rules {
+		key, side, ok := strings.Cut(r, "=")
+		key, side = strings.TrimSpace(key), strings.ToLower(strings.TrimSpace(side))
+		if !ok || key == "" || (side != "ours" && side != "theirs") {
+			return nil, fmt.Errorf("invalid --prefer %q (expected KEY=ours|theirs, e.g. labels=theirs)", r)
+		}
+		s[key] = side
+	}
+	return s, nil
+}
+
+func (s importStrategy) side(key string) string {
+	best, side := -1, "ours"
+	for k, v := range s {
+		if (k == "*" || key == k || strings.HasPrefix(key, k+".")) && len(k) > best {
+			best, side = len(k), v
+		}
+	}
+	return side
+}
+
+// bundleMerger merges one bundle profile into an existing one, recording conflicts.
+type bundleMerger struct {
+	profile   string
+	strategy  importStrategy
+	conflicts []importConflict
+}
+
+// value returns the merged value of key: theirs fills an empty ours; differing values are
+// resolved by the strategy and reported.
+func (m *bundleMerger) value(key, ours, theirs string) string {
+	if theirs == "" || ours == theirs {
+		return ours
+	}
+	if ours == "" {
+		return theirs
+	}
+	side := m.strategy.side(key)
+	m.conflicts = append(m.conflicts, importConflict{
+		Profile: m.profile,
+		Key:     key,
+		Ours:    bundleDisplayValue(key, ours),
+		Theirs:  bundleDisplayValue(key, theirs),
+		Kept:    side,
+	})
+	if side == "theirs" {
+		return theirs
+	}
+	return ours
+}
+
+func (m *bundleMerger) mergeMap(prefix string, ours, theirs map[string]string) map[string]string {
+	if ours == nil {
+		ours = map[string]string{}
+	}
+	for k, v := range theirs {
+		ours[k] = m.value(prefix+k, ours[k], v)
+	}
+	return ours
+}
+
+func (m *bundleMerger) merge(ours, theirs Config) Config {
+	out := cloneProfile(ours)
+	out.Extends = m.value("extends", ours.Extends, theirs.Extends)
+	out.BaseURL = m.value("base_url", ours.BaseURL, theirs.BaseURL)
+	out.OrgID = m.value("org_id", ours.OrgID, theirs.OrgID)
+	out.LabelStore = m.value("label_store", ours.LabelStore, theirs.LabelStore)
+	out.Auth.APIKey = m.value("auth.api_key", ours.Auth.APIKey, theirs.Auth.APIKey)
+	if out.Auth.APIKey == theirs.Auth.APIKey && out.Auth.APIKey != ours.Auth.APIKey {
+		// A session token and the recorded capabilities belong to the API key they came from.
+		out.Auth.Token, out.Auth.TokenAcquiredAt = theirs.Auth.Token, theirs.Auth.TokenAcquiredAt
+		out.Capabilities = theirs.Capabilities
+	}
+	out.Headers = m.mergeMap("headers.", out.Headers, theirs.Headers)
+	if theirs.Labels != nil {
+		out.Labels.Cameras = m.mergeMap("labels.cameras.", out.Labels.Cameras, theirs.Labels.Cameras)
+		// Tags and notes only accumulate, so they merge without conflicts.
+		for id, tags := range theirs.Labels.Tags {
+			if out.Labels.Tags == nil {
+				out.Labels.Tags = map[string][]string{}
+			}
+			out.Labels.Tags[id] = addTags(out.Labels.Tags[id], tags)
+		}
+		for id, notes := range theirs.Labels.Notes {
+			if out.Labels.Notes == nil {
+				out.Labels.Notes = map[string][]CameraNote{}
+			}
+			out.Labels.Notes[id] = mergeCameraNotes(out.Labels.Notes[id], notes)
+		}
+	}
+	return out
+}
+
+type importResult struct {
+	Created   []string         `json:"created"`
+	Merged    []string         `json:"merged"`
+	Replaced  []string         `json:"replaced"`
+	Conflicts []importConflict `json:"conflicts"`
+	Secrets   bool             `json:"secrets"`
+	DryRun    bool             `json:"dry_run"`
+}
+
+// applyConfigBundle imports bundle into cf. With replace, bundle profiles overwrite existing ones
+// wholesale; otherwise they are merged key by key (see bundleMerger).
+func applyConfigBundle(cf *ConfigFile, bundle configBundle, secrets map[string]AuthConfig, replace bool, strategy importStrategy) importResult {
+	res := importResult{Created: []string{}, Merged: []string{}, Replaced: []string{}, Conflicts: []importConflict{}, Secrets: secrets != nil}
+	names := make([]string, 0, len(bundle.Profiles))
+	for n := range bundle.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		theirs := cloneProfile(bundle.Profiles[n])
+		theirs.Auth = secrets[n]
+		ours, exists := cf.Profiles[n]
+		switch {
+		case !exists:
+			cf.Profiles[n] = theirs
+			res.Created = append(res.Created, n)
+		case replace:
+			if secrets == nil {
+				theirs.Auth = ours.Auth // keep local credentials when the bundle has none
+			}
+			cf.Profiles[n] = theirs
+			res.Replaced = append(res.Replaced, n)
+		default:
+			m := &bundleMerger{profile: n, strategy: strategy}
+			cf.Profiles[n] = m.merge(ours, theirs)
+			res.Merged = append(res.Merged, n)
+			res.Conflicts = append(res.Conflicts, m.conflicts...)
+		}
+	}
+
+	if bundle.Defaults != nil {
+		theirs := cloneProfile(*bundle.Defaults)
+		theirs.Auth = secrets[bundleDefaultsSecret]
+		switch {
+		case cf.Defaults == nil || replace:
+			if secrets == nil && cf.Defaults != nil {
+				theirs.Auth = cf.Defaults.Auth
+			}
+			cf.Defaults = &theirs
+		default:
+			m := &bundleMerger{profile: "defaults", strategy: strategy}
+			d := m.merge(*cf.Defaults, theirs)
+			cf.Defaults = &d
+			res.Conflicts = append(res.Conflicts, m.conflicts...)
+		}
+	}
+	if cf.CurrentProfile == "" && len(names) > 0 {
+		cf.CurrentProfile = names[0]
+	}
+	return res
+}
+
+func newConfigImportCmd(rf *rootFlags) *cobra.Command {
+	var replace, merge, skipSecrets, dryRun bool
+	var prefer []string
+
+	cmd := &cobra.Command{
+		Use:   "import BUNDLE",
+		Short: "Import profiles and camera labels from a `config export` bundle",
+		Long: strings.TrimSpace(`
+Imports the profiles of a bundle into the config file (created if missing).
+
+--merge (default) adds keys that are missing locally and reports keys that differ. Conflicts keep
+the local value unless --prefer says otherwise; rules match a key or a dotted prefix, the longest
+match wins:
+
+  --prefer labels=theirs --prefer labels.cameras.CAM1=ours --prefer base_url=theirs
+
+--replace overwrites each bundle profile wholesale (local credentials are kept when the bundle has
+none). Encrypted credentials need the passphrase from ` + bundlePassphraseEnv + ` or the prompt;
+--skip-secrets imports everything else.
+`),
+		Example: strings.TrimSpace(`
+  verkcli config import bundle.json --dry-run
+  verkcli config import bundle.json --prefer labels=theirs
+  verkcli config import bundle.json --replace --skip-secrets
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if replace && merge {
+				return errors.New("--merge and --replace are mutually exclusive")
+			}
+			strategy, err := parseImportStrategy(prefer)
+			if err != nil {
+				return err
+			}
+			bundle, err := readConfigBundle(args[0])
+			if err != nil {
+				return err
+			}
+			var secrets map[string]AuthConfig
+			if bundle.Secrets != nil && !skipSecrets {
+				pass, err := bundlePassphrase(cmd, false)
+				if err != nil {
+					return err
+				}
+				if secrets, err = openBundleSecrets(bundle.Secrets, pass); err != nil {
+					return err
+				}
+			}
+
+			p, err := resolveConfigPath(rf.ConfigPath)
+			if err != nil {
+				return err
+			}
+			unlock, err := lockConfig(p)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			cf, err := loadConfig(p)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					return err
+				}
+				cf = ConfigFile{Profiles: map[string]Config{}}
+			}
+			before := make(map[string]Config, len(cf.Profiles))
+			for n, prof := range cf.Profiles {
+				before[n] = cloneProfile(prof)
+			}
+			names := ConfigFile{Profiles: maps.Clone(cf.Profiles)}
+			for _, n := range sortedKeys(bundle.Profiles) {
+				if _, ok := names.Profiles[n]; !ok {
+					if err := validateNewProfileName(names, n, ""); err != nil {
+						return fmt.Errorf("bundle profile %q: %w", n, err)
+					}
+					names.Profiles[n] = Config{}
+				}
+			}
+			res := applyConfigBundle(&cf, bundle, secrets, replace, strategy)
+			res.DryRun = dryRun
+			if !dryRun {
+				for n := range bundle.Profiles {
+					if _, err := profileChain(cf, n); err != nil {
+						return fmt.Errorf("imported profiles do not resolve: %w", err)
+					}
+				}
+				if err := writeConfig(p, cf); err != nil {
+					return err
+				}
+				for n := range bundle.Profiles {
+					syncIndexLabels(rf, n, resolvedProfileOrRaw(cf, n), profileLabels(before[n]), profileLabels(cf.Profiles[n]))
+				}
+			}
+			return writeImportResult(cmd, rf, p, res)
+		},
+	}
+
+	cmd.Flags().BoolVar(&merge, "merge", false, "Merge into existing profiles key by key (default)")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Replace existing profiles with the bundle's")
+	cmd.Flags().StringArrayVar(&prefer, "prefer", nil, "Conflict rule KEY=ours|theirs (repeatable; KEY may be a prefix like labels or headers)")
+	cmd.Flags().BoolVar(&skipSecrets, "skip-secrets", false, "Do not import encrypted credentials")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would change without writing the config")
+	return cmd
+}
+
+func readConfigBundle(path string) (configBundle, error) {
+	var bundle configBundle
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return bundle, err
+	}
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return bundle, fmt.Errorf("read bundle %s: %w", path, err)
+	}
+	if bundle.Version != bundleVersion {
+		return bundle, fmt.Errorf("%s is not a verkcli bundle (verkcli_bundle = %d)", path, bundle.Version)
+	}
+	for n, prof := range bundle.Profiles {
+		if err := validateProfileName(n); err != nil {
+			return bundle, fmt.Errorf("bundle profile %q: %w", n, err)
+		}
+		if prof.Headers == nil {
+			prof.Headers = map[string]string{}
+		}
+		if prof.Labels == nil {
+			prof.Labels = &LocalLabels{Cameras: map[string]string{}}
+		}
+		bundle.Profiles[n] = prof
+	}
+	return bundle, nil
+}
+
+func writeImportResult(cmd *cobra.Command, rf *rootFlags, path string, res importResult) error {
+	out := cmd.OutOrStdout()
+	if rf.Output == "json" {
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = out.Write(append(b, '\n'))
+		return nil
+	}
+	for _, c := range res.Conflicts {
+		fmt.Fprintf(out, "conflict %s %s: ours=%q theirs=%q (kept %s)\n", c.Profile, c.Key, c.Ours, c.Theirs, c.Kept)
+	}
+	verb := "imported into"
+	if res.DryRun {
+		verb = "would import into"
+	}
+	fmt.Fprintf(out, "%s %s: created %d, merged %d, replaced %d, %d conflict(s)", verb, path, len(res.Created), len(res.Merged), len(res.Replaced), len(res.Conflicts))
+	if !res.Secrets {
+		fmt.Fprint(out, " (no credentials imported)")
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// bundleDisplayValue masks credentials in conflict reports.
+func bundleDisplayValue(key, v string) string {
+	if strings.HasPrefix(key, "auth.") {
+		return maskSecret(v)
+	}
+	return v
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigExportImportRoundTrip(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(bundlePassphraseEnv, "correct horse")
+	src := filepath.Join(t.TempDir(), "src.json")
+	if err := writeConfig(src, ConfigFile{
+		CurrentProfile: "eu",
+		Profiles: map[string]Config{
+			"base": {BaseURL: "https://api.verkada.com", Headers: map[string]string{"X-Team": "ops"}},
+			"eu": {
+				Extends: "base",
+				OrgID:   "ORG-EU",
+				Auth:    AuthConfig{APIKey: "key-eu-1234567890"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Lobby", "CAM2": "Dock"}},
+			},
+			"lab": {BaseURL: "https://api.lab.example.com"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	bundleJSON, err := runProfilesCLI(t, "--config", src, "config", "export", "--profiles", "eu", "--include-secrets")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if strings.Contains(bundleJSON, "key-eu-1234567890") {
+		t.Fatalf("bundle leaks the API key:\n%s", bundleJSON)
+	}
+	var bundle configBundle
+	if err := json.Unmarshal([]byte(bundleJSON), &bundle); err != nil {
+		t.Fatalf("decode bundle: %v", err)
+	}
+	if _, ok := bundle.Profiles["base"]; !ok || len(bundle.Profiles) != 2 || bundle.Secrets == nil {
+		t.Fatalf("bundle profiles = %v, secrets = %v", bundle.Profiles, bundle.Secrets != nil)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(bundlePath, []byte(bundleJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst.json")
+	if err := writeConfig(dst, ConfigFile{
+		CurrentProfile: "eu",
+		Profiles: map[string]Config{
+			"eu": {
+				BaseURL: "https://api.eu.verkada.com",
+				Headers: map[string]string{"X-Mine": "1"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Front", "CAM3": "Yard"}},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runProfilesCLI(t, "--config", dst, "--output", "json", "config", "import", bundlePath, "--prefer", "labels=theirs", "--prefer", "base_url=ours")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	var res importResult
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("decode result: %v\n%s", err, out)
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].Key != "labels.cameras.CAM1" || res.Conflicts[0].Kept != "theirs" {
+		t.Fatalf("conflicts = %+v", res.Conflicts)
+	}
+
+	cf, err := loadConfig(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eu := cf.Profiles["eu"]
+	if eu.Auth.APIKey != "key-eu-1234567890" || eu.OrgID != "ORG-EU" || eu.Extends != "base" || eu.BaseURL != "https://api.eu.verkada.com" {
+		t.Fatalf("eu = %+v", eu)
+	}
+	want := map[string]string{"CAM1": "Lobby", "CAM2": "Dock", "CAM3": "Yard"}
+	for id, label := range want {
+		if eu.Labels.Cameras[id] != label {
+			t.Fatalf("labels = %v", eu.Labels.Cameras)
+		}
+	}
+	if eu.Headers["X-Mine"] != "1" || cf.Profiles["base"].Headers["X-Team"] != "ops" {
+		t.Fatalf("headers eu = %v base = %v", eu.Headers, cf.Profiles["base"].Headers)
+	}
+}
+
+func TestConfigImportWrongPassphrase(t *testing.T) {
+	s, err := sealBundleSecrets(map[string]AuthConfig{"p": {APIKey: "k"}}, "right")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openBundleSecrets(s, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Fatalf("err = %v", err)
+	}
+	got, err := openBundleSecrets(s, "right")
+	if err != nil || got["p"].APIKey != "k" {
+		t.Fatalf("open = %v, %v", got, err)
+	}
+}
+
+func TestImportStrategyLongestMatch(t *testing.T) {
+	s, err := parseImportStrategy([]string{"labels=theirs", "labels.cameras.CAM1=ours", "*=theirs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"labels.cameras.CAM1": "ours",
+		"labels.cameras.CAM2": "theirs",
+		"base_url":            "theirs",
+	}
+	for key, want := range cases {
+		if got := s.side(key); got != want {
+			t.Errorf("side(%s) = %s, want %s", key, got, want)
+		}
+	}
+	if _, err := parseImportStrategy([]string{"labels"}); err == nil {
+		t.Fatal("expected error for rule without side")
+	}
+}
+
+func TestConfigExportDefaultsAuthPromptedPassphrase(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv(bundlePassphraseEnv, "")
+	src := filepath.Join(t.TempDir(), "src.json")
+	if err := writeConfig(src, ConfigFile{
+		CurrentProfile: "prod",
+		Defaults:       &Config{BaseURL: "https://api.verkada.com", Auth: AuthConfig{APIKey: "key-shared-1234567890"}},
+		Profiles:       map[string]Config{"prod": {OrgID: "ORG1"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both prompts read from one piped stdin.
+	run := func(stdin string, args ...string) (string, error) {
+		cmd := NewRootCmd()
+		var out, errBuf bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errBuf)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+	bundleJSON, err := run("s3cret\ns3cret\n", "--config", src, "config", "export", "--include-secrets")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if strings.Contains(bundleJSON, "key-shared-1234567890") {
+		t.Fatalf("bundle leaks the defaults API key:\n%s", bundleJSON)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(bundlePath, []byte(bundleJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "dst.json")
+	if _, err := run("s3cret\n", "--config", dst, "config", "import", bundlePath); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	cf, err := loadConfig(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cf.Defaults == nil || cf.Defaults.Auth.APIKey != "key-shared-1234567890" {
+		t.Fatalf("defaults = %+v", cf.Defaults)
+	}
+	if _, ok := cf.Profiles[bundleDefaultsSecret]; ok {
+		t.Fatal("defaults auth imported as a profile")
+	}
+}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+type doctorCheck struct {
+	Profile string `json:"profile,omitempty"` // empty for file-level checks
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+type doctorReport struct {
+	Config  string         `json:"config"`
+	Checks  []doctorCheck  `json:"checks"`
+	Summary map[string]int `json:"summary"`
+}
+
+func (r *doctorReport) add(profile, check, status, detail string) {
+	r.Checks = append(r.Checks, doctorCheck{Profile: profile, Check: check, Status: status, Detail: detail})
+	r.Summary[status]++
+}
+
+func newConfigDoctorCmd(rf *rootFlags) *cobra.Command {
+	var online, strict bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Validate the config file and every profile",
+		Long: strings.TrimSpace(`
+Checks the config file offline: file permissions, legacy format, unknown keys, current_profile,
+the project config, and per profile its inheritance, base_url, credentials, org_id (needed for
+footage) and duplicate camera labels. --profile limits the per-profile checks to one profile.
+
+With --online each profile also runs the login preflight (list cameras, streaming token, live
+playlist) against the API.
+
+Exits non-zero when any check fails (or, with --strict, warns).
+`),
+		Example: strings.TrimSpace(`
+  verkcli config doctor
+  verkcli config doctor --online --output json
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := resolveConfigPath(rf.ConfigPath)
+			if err != nil {
+				return err
+			}
+			rep := runConfigDoctor(p, rf)
+			if online {
+				runOnlineDoctor(&rep, p, rf, &http.Client{Timeout: timeout})
+			}
+
+			if err := writeDoctorReport(cmd, rf, rep); err != nil {
+				return err
+			}
+			if n := rep.Summary[doctorFail]; n > 0 {
+				return fmt.Errorf("config doctor: %d check(s) failed", n)
+			}
+			if n := rep.Summary[doctorWarn]; strict && n > 0 {
+				return fmt.Errorf("config doctor: %d warning(s) (--strict)", n)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&online, "online", false, "Also run the login preflight against the API for each profile")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Exit non-zero on warnings too")
+	cmd.Flags().DurationVar(&timeout, "timeout", 20*time.Second, "HTTP timeout for --online checks")
+	return cmd
+}
+
+// runConfigDoctor runs the offline checks on the config file at path.
+func runConfigDoctor(path string, rf *rootFlags) doctorReport {
+	rep := doctorReport{Config: path, Summary: map[string]int{doctorPass: 0, doctorWarn: 0, doctorFail: 0}}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			rep.add("", "file", doctorFail, "no config file (run: verkcli login or verkcli config init)")
+		} else {
+			rep.add("", "file", doctorFail, err.Error())
+		}
+		return rep
+	}
+	raw, err := configToJSON(path, b)
+	var tree map[string]any
+	if err == nil {
+		err = json.Unmarshal(raw, &tree)
+	}
+	var cf ConfigFile
+	if err == nil {
+		err = json.Unmarshal(raw, &cf)
+	}
+	if err != nil {
+		rep.add("", "file", doctorFail, fmt.Sprintf("cannot parse: %v", err))
+		return rep
+	}
+	rep.add("", "file", doctorPass, configFormatFor(path))
+
+	hasSecrets := cf.Auth != nil && (cf.Auth.APIKey != "" || cf.Auth.Token != "")
+	for _, prof := range cf.Profiles {
+		hasSecrets = hasSecrets || prof.Auth.APIKey != "" || prof.Auth.Token != ""
+	}
+	checkConfigPermissions(&rep, path, hasSecrets)
+
+	if strings.TrimSpace(cf.BaseURL) != "" || cf.Auth != nil || cf.Headers != nil {
+		rep.add("", "format", doctorWarn, "legacy top-level base_url/auth/headers; any profile edit (e.g. verkcli config use default) rewrites it as profiles")
+	} else {
+		rep.add("", "format", doctorPass, "")
+	}
+
+	if unknown := unknownConfigKeys(tree, reflect.TypeOf(ConfigFile{}), ""); len(unknown) > 0 {
+		rep.add("", "unknown_keys", doctorWarn, "ignored: "+strings.Join(unknown, ", "))
+	} else {
+		rep.add("", "unknown_keys", doctorPass, "")
+	}
+
+	normalizeConfigFile(&cf)
+	if _, ok := cf.Profiles[cf.CurrentProfile]; !ok && cf.CurrentProfile != "" {
+		rep.add("", "current_profile", doctorFail, fmt.Sprintf("%q is not a profile", cf.CurrentProfile))
+	} else {
+		rep.add("", "current_profile", doctorPass, cf.CurrentProfile)
+	}
+
+	if projPath, _, err := discoverProjectConfig(); err != nil {
+		rep.add("", "project_config", doctorFail, err.Error())
+	} else if projPath != "" {
+		rep.add("", "project_config", doctorPass, projPath)
+	}
+
+	names, err := doctorProfileNames(cf, rf)
+	if err != nil {
+		rep.add("", "profile", doctorFail, err.Error())
+	}
+	for _, name := range names {
+		checkDoctorProfile(&rep, cf, name)
+	}
+	return rep
+}
+
+func checkConfigPermissions(rep *doctorReport, path string, hasSecrets bool) {
+	st, err := os.Stat(path)
+	if err != nil {
+		rep.add("", "permissions", doctorFail, err.Error())
+		return
+	}
+	perm := st.Mode().Perm()
+	switch {
+	case perm&0o077 == 0:
+		rep.add("", "permissions", doctorPass, fmt.Sprintf("%04o", perm))
+	case hasSecrets && perm&0o044 != 0:
+		rep.add("", "permissions", doctorFail, fmt.Sprintf("%04o is readable by other users and the file holds credentials (chmod 600 %s)", perm, path))
+	default:
+		rep.add("", "permissions", doctorWarn, fmt.Sprintf("%04o is wider than 0600 (chmod 600 %s)", perm, path))
+	}
+}
+
+// doctorProfileNames is --profile when given, otherwise every profile, sorted.
+func doctorProfileNames(cf ConfigFile, rf *rootFlags) ([]string, error) {
+	if rf.Profile != "" {
+		if _, ok := cf.Profiles[rf.Profile]; !ok {
+			return nil, fmt.Errorf("profile %q not found", rf.Profile)
+		}
+		return []string{rf.Profile}, nil
+	}
+	names := make([]string, 0, len(cf.Profiles))
+	for n := range cf.Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func checkDoctorProfile(rep *doctorReport, cf ConfigFile, name string) {
+	prof, _, err := resolveProfile(cf, name)
+	if err != nil {
+		rep.add(name, "inheritance", doctorFail, err.Error())
+		return
+	}
+	if prof.Extends != "" {
+		rep.add(name, "inheritance", doctorPass, "extends "+prof.Extends)
+	}
+
+	if strings.TrimSpace(prof.BaseURL) == "" {
+		rep.add(name, "base_url", doctorFail, "base_url is empty")
+	} else if _, err := validateBaseURL(prof.BaseURL); err != nil {
+		rep.add(name, "base_url", doctorFail, err.Error())
+	} else {
+		rep.add(name, "base_url", doctorPass, prof.BaseURL)
+	}
+
+	if strings.TrimSpace(prof.Auth.APIKey) == "" && strings.TrimSpace(prof.Auth.Token) == "" {
+		rep.add(name, "credentials", doctorWarn, "no api_key or token stored; commands need --api-key or VERKCLI_API_KEY")
+	} else {
+		rep.add(name, "credentials", doctorPass, "")
+	}
+
+	if strings.TrimSpace(prof.OrgID) == "" {
+		rep.add(name, "org_id", doctorWarn, "org_id is empty; footage commands will fail (verkcli --profile "+name+" profiles set org_id ORG)")
+	} else {
+		rep.add(name, "org_id", doctorPass, prof.OrgID)
+	}
+
+	if prof.LabelStore != "" {
+		if _, err := parseLabelStore(prof.LabelStore, "", nil); err != nil {
+			rep.add(name, "label_store", doctorFail, err.Error())
+		} else {
+			rep.add(name, "label_store", doctorPass, prof.LabelStore)
+		}
+	}
+
+	if dups := duplicateLabels(prof.Labels); len(dups) > 0 {
+		rep.add(name, "labels", doctorWarn, "duplicate labels: "+strings.Join(dups, "; "))
+	} else {
+		rep.add(name, "labels", doctorPass, "")
+	}
+}
+
+// duplicateLabels reports labels (compared case-insensitively) used by more than one camera; such
+// labels cannot be used in place of a camera_id.
+func duplicateLabels(l *LocalLabels) []string {
+	if l == nil {
+		return nil
+	}
+	byLabel := map[string][]string{}
+	for id, label := range l.Cameras {
+		key := strings.ToLower(strings.TrimSpace(label))
+		byLabel[key] = append(byLabel[key], id)
+	}
+	var out []string
+	for _, ids := range byLabel {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Strings(ids)
+		out = append(out, fmt.Sprintf("%q on %s", l.Cameras[ids[0]], strings.Join(ids, ", ")))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// unknownConfigKeys lists keys in tree that do not map to a json field of t, as dotted paths.
+// Map-typed fields (profiles, headers, labels.cameras) accept any key.
+func unknownConfigKeys(tree any, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	obj, ok := tree.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var out []string
+	switch t.Kind() {
+	case reflect.Map:
+		for k, v := range obj {
+			out = append(out, unknownConfigKeys(v, t.Elem(), prefix+k+".")...)
+		}
+	case reflect.Struct:
+		fields := map[string]reflect.Type{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if f.Anonymous && name == "" {
+				continue
+			}
+			fields[name] = f.Type
+		}
+		for k, v := range obj {
+			ft, ok := fields[k]
+			if !ok {
+				out = append(out, prefix+k)
+				continue
+			}
+			out = append(out, unknownConfigKeys(v, ft, prefix+k+".")...)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// runOnlineDoctor runs the login preflight for each profile that passed the offline checks, one
+// row per preflight check; unavailable optional capabilities are warnings.
+func runOnlineDoctor(rep *doctorReport, path string, rf *rootFlags, client *http.Client) {
+	cf, err := loadConfig(path)
+	if err != nil {
+		return
+	}
+	failed := map[string]bool{}
+	for _, c := range rep.Checks {
+		if c.Profile != "" && c.Status == doctorFail {
+			failed[c.Profile] = true
+		}
+	}
+	names, err := doctorProfileNames(cf, rf)
+	if err != nil {
+		return // reported by runConfigDoctor
+	}
+	for _, name := range names {
+		if failed[name] {
+			rep.add(name, "online", doctorWarn, "skipped: offline checks failed")
+			continue
+		}
+		prof, _, err := resolveProfile(cf, name)
+		if err != nil {
+			continue
+		}
+		// Check each profile as itself, and never write back what the checks discover.
+		prf := *rf
+		prf.Profile, prf.noPersist = name, true
+		for _, c := range runLoginPreflight(client, &prof, &prf).Checks {
+			status := doctorPass
+			switch {
+			case c.blocking():
+				status = doctorFail
+			case c.Status != preflightPass:
+				status = doctorWarn
+			}
+			rep.add(name, "online."+c.Name, status, firstNonEmpty(c.Reason, "ok"))
+		}
+	}
+}
+
+func writeDoctorReport(cmd *cobra.Command, rf *rootFlags, rep doctorReport) error {
+	out := cmd.OutOrStdout()
+	if rf.Output == "json" {
+		b, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = out.Write(append(b, '\n'))
+		return nil
+	}
+
+	fmt.Fprintf(out, "config: %s\n", rep.Config)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROFILE\tCHECK\tSTATUS\tDETAIL")
+	for _, c := range rep.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", firstNonEmpty(c.Profile, "-"), c.Check, c.Status, c.Detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%d passed, %d warnings, %d failed\n", rep.Summary[doctorPass], rep.Summary[doctorWarn], rep.Summary[doctorFail])
+	return nil
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigDoctorReportsProblems(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	raw := `{
+  "current_profile": "prod",
+  "profiles": {
+    "prod": {
+      "base_url": "https://api.verkada.com",
+      "org_id": "ORG1",
+      "auth": {"api_key": "key", "tokn": "x"},
+      "labels": {"cameras": {"CAM1": "Front Door", "CAM2": "front door"}}
+    },
+    "ui": {"base_url": "https://acme.command.verkada.com", "base_ur": "typo"},
+    "loop": {"extends": "loop"}
+  }
+}`
+	if err := os.WriteFile(cfgPath, []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runProfilesCLI(t, "--config", cfgPath, "--output", "json", "config", "doctor")
+	if err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Fatalf("err = %v, want failure", err)
+	}
+	var rep doctorReport
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	status := map[string]doctorCheck{}
+	for _, c := range rep.Checks {
+		status[c.Profile+"/"+c.Check] = c
+	}
+	want := map[string]string{
+		"/permissions":     doctorFail,
+		"/unknown_keys":    doctorWarn,
+		"/current_profile": doctorPass,
+		"prod/base_url":    doctorPass,
+		"prod/labels":      doctorWarn,
+		"ui/base_url":      doctorFail,
+		"ui/credentials":   doctorWarn,
+		"ui/org_id":        doctorWarn,
+		"loop/inheritance": doctorFail,
+		"prod/credentials": doctorPass,
+		"prod/org_id":      doctorPass,
+		"/format":          doctorPass,
+		"/file":            doctorPass,
+	}
+	for k, v := range want {
+		if status[k].Status != v {
+			t.Errorf("%s = %q (%s), want %q", k, status[k].Status, status[k].Detail, v)
+		}
+	}
+	if d := status["/unknown_keys"].Detail; !strings.Contains(d, "profiles.prod.auth.tokn") || !strings.Contains(d, "profiles.ui.base_ur") {
+		t.Errorf("unknown keys detail = %q", d)
+	}
+	if d := status["prod/labels"].Detail; !strings.Contains(d, "CAM1, CAM2") {
+		t.Errorf("labels detail = %q", d)
+	}
+}
+
+func TestConfigDoctorCleanConfigPasses(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "prod",
+		Profiles: map[string]Config{
+			"prod": {BaseURL: "https://api.verkada.com", OrgID: "ORG1", Auth: AuthConfig{APIKey: "key"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runProfilesCLI(t, "--config", cfgPath, "config", "doctor", "--strict")
+	if err != nil {
+		t.Fatalf("doctor: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "0 warnings, 0 failed") {
+		t.Fatalf("output:\n%s", out)
+	}
+}
+
+func TestConfigDoctorOnlineLeavesProfilesUnchanged(t *testing.T) {
+	t.Chdir(t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/token":
+			fmt.Fprintf(w, `{"token":"fresh-%s"}`, r.Header.Get("x-api-key"))
+		case r.Header.Get("x-verkada-auth") == "":
+			w.WriteHeader(400)
+			fmt.Fprint(w, `{"message":"API token is required"}`)
+		case r.URL.Path == "/cameras/v1/devices":
+			fmt.Fprint(w, `{"cameras":[]}`)
+		case r.URL.Path == "/core/v1/organization":
+			fmt.Fprint(w, `{"organization_id":"ORG-OTHER"}`)
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "a",
+		Profiles: map[string]Config{
+			"a": {BaseURL: srv.URL, OrgID: "ORG-A", Auth: AuthConfig{APIKey: "key-a"}},
+			"b": {BaseURL: srv.URL, OrgID: "ORG-B", Auth: AuthConfig{APIKey: "key-b"}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(cfgPath, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	before := mustReadFile(t, cfgPath)
+
+	out, _ := runProfilesCLI(t, "--config", cfgPath, "--output", "json", "config", "doctor", "--online")
+	var rep doctorReport
+	if err := json.Unmarshal([]byte(out), &rep); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	checked := map[string]bool{}
+	for _, c := range rep.Checks {
+		if c.Check == "online.list_cameras" && c.Status == doctorPass {
+			checked[c.Profile] = true
+		}
+	}
+	if !checked["a"] || !checked["b"] {
+		t.Fatalf("online checks = %+v", rep.Checks)
+	}
+	if after := mustReadFile(t, cfgPath); string(after) != string(before) {
+		t.Fatalf("doctor --online changed the config:\n%s", after)
+	}
+}
+
+func TestConfigDoctorUnknownProfileFails(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "prod",
+		Profiles:       map[string]Config{"prod": {BaseURL: "https://api.verkada.com", OrgID: "ORG1", Auth: AuthConfig{APIKey: "key"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runProfilesCLI(t, "--config", cfgPath, "--profile", "staging", "config", "doctor")
+	if err == nil || !strings.Contains(out, `profile "staging" not found`) {
+		t.Fatalf("doctor --profile staging: %v\n%s", err, out)
+	}
+}
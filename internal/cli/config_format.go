@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config files may be JSON, YAML or TOML, chosen by extension. YAML and TOML are converted to
+// and from JSON so the json tags on Config and ConfigFile stay the only schema.
+const (
+	configFormatJSON = "json"
+	configFormatYAML = "yaml"
+	configFormatTOML = "toml"
+)
+
+func configFormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return configFormatYAML
+	case ".toml":
+		return configFormatTOML
+	default:
+		return configFormatJSON
+	}
+}
+
+// configToJSON returns the contents of a config file as JSON.
+func configToJSON(path string, b []byte) ([]byte, error) {
+	var v any
+	switch configFormatFor(path) {
+	case configFormatYAML:
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		v = stringMapKeys(v)
+	case configFormatTOML:
+		m := map[string]any{}
+		if _, err := toml.Decode(string(b), &m); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		v = m
+	default:
+		return b, nil
+	}
+	return json.Marshal(v)
+}
+
+// configFromJSON converts marshaled JSON into the format of path.
+func configFromJSON(path string, b []byte) ([]byte, error) {
+	switch configFormatFor(path) {
+	case configFormatYAML:
+		// JSON is YAML: decoding into a node keeps the field order of the structs.
+		var node yaml.Node
+		if err := yaml.Unmarshal(b, &node); err != nil {
+			return nil, err
+		}
+		clearYAMLStyle(&node)
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&node); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case configFormatTOML:
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var v map[string]any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(tomlNumbers(v)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return append(b, '\n'), nil
+	}
+}
+
+// stringMapKeys turns YAML mappings with non-string keys (e.g. a numeric camera_id) into
+// map[string]any so they can be marshaled as JSON.
+func stringMapKeys(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = stringMapKeys(e)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = stringMapKeys(e)
+		}
+		return m
+	case []any:
+		for i, e := range t {
+			t[i] = stringMapKeys(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func tomlNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = tomlNumbers(e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = tomlNumbers(e)
+		}
+		return t
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+func clearYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearYAMLStyle(c)
+	}
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// configSources records which layer set each effective config key. Keys are the JSON paths of
+// the value ("base_url", "auth.api_key", "headers.X-Foo", "labels.cameras.CAM1"); layers are
+// "defaults", "profile:NAME", "env:VAR" or "flag:--name".
+type configSources map[string]string
+
+// resolveProfile returns a profile with inheritance applied: the top-level defaults block, then
+// each profile in its extends chain from the root down, then the profile itself. Scalars are
+// overridden by the first non-empty value of a later layer; headers and labels merge per key.
+func resolveProfile(cf ConfigFile, name string) (Config, configSources, error) {
+	chain, err := profileChain(cf, name)
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	var out Config
+	src := configSources{}
+	if cf.Defaults != nil {
+		if strings.TrimSpace(cf.Defaults.Extends) != "" {
+			return Config{}, nil, errors.New("defaults cannot use extends")
+		}
+		mergeConfigLayer(&out, *cf.Defaults, "defaults", src)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		mergeConfigLayer(&out, cf.Profiles[chain[i]], "profile:"+chain[i], src)
+	}
+	if out.Extends = cf.Profiles[name].Extends; out.Extends != "" {
+		src["extends"] = "profile:" + name
+	}
+	return out, src, nil
+}
+
+// profileChain returns name followed by its ancestors, nearest first.
+func profileChain(cf ConfigFile, name string) ([]string, error) {
+	if _, ok := cf.Profiles[name]; !ok {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	chain := []string{name}
+	seen := map[string]bool{name: true}
+	for cur := name; ; {
+		parent := strings.TrimSpace(cf.Profiles[cur].Extends)
+		if parent == "" {
+			return chain, nil
+		}
+		if seen[parent] {
+			return nil, fmt.Errorf("profile inheritance cycle: %s -> %s", strings.Join(chain, " -> "), parent)
+		}
+		if _, ok := cf.Profiles[parent]; !ok {
+			return nil, fmt.Errorf("profile %q extends unknown profile %q", cur, parent)
+		}
+		seen[parent] = true
+		chain = append(chain, parent)
+		cur = parent
+	}
+}
+
+// resolvedProfileOrRaw is resolveProfile for callers that only need a best-effort view (for
+// example to locate a profile's cameras index); on error the stored profile is returned as is.
+func resolvedProfileOrRaw(cf ConfigFile, name string) Config {
+	if c, _, err := resolveProfile(cf, name); err == nil {
+		return c
+	}
+	return cf.Profiles[name]
+}
+
+// profileChildren lists the profiles that extend name directly, sorted.
+func profileChildren(cf ConfigFile, name string) []string {
+	var out []string
+	for n, p := range cf.Profiles {
+		if strings.TrimSpace(p.Extends) == name {
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func mergeConfigLayer(dst *Config, layer Config, source string, src configSources) {
+	setString := func(key string, to *string, v string) {
+		if strings.TrimSpace(v) != "" {
+			*to = v
+			src[key] = source
+		}
+	}
+	setString("base_url", &dst.BaseURL, layer.BaseURL)
+	setString("org_id", &dst.OrgID, layer.OrgID)
+	setString("label_store", &dst.LabelStore, layer.LabelStore)
+	setString("auth.api_key", &dst.Auth.APIKey, layer.Auth.APIKey)
+	if strings.TrimSpace(layer.Auth.APIKey) != "" {
+		// Capabilities describe the API key they were checked with.
+		dst.Capabilities = layer.Capabilities
+		delete(src, "capabilities")
+		if layer.Capabilities != nil {
+			src["capabilities"] = source
+		}
+	}
+	if strings.TrimSpace(layer.Auth.Token) != "" {
+		// The acquisition time belongs to the token it was stored with.
+		dst.Auth.TokenAcquiredAt = layer.Auth.TokenAcquiredAt
+		delete(src, "auth.token_acquired_at")
+		if layer.Auth.TokenAcquiredAt != 0 {
+			src["auth.token_acquired_at"] = source
+		}
+	}
+	setString("auth.token", &dst.Auth.Token, layer.Auth.Token)
+
+	if len(layer.Headers) > 0 && dst.Headers == nil {
+		dst.Headers = map[string]string{}
+	}
+	for k, v := range layer.Headers {
+		dst.Headers[k] = v
+		src["headers."+k] = source
+	}
+	mergeLabelsLayer(dst, layer.Labels, source, src)
+}
+
+// mergeLabelsLayer merges camera labels per camera, while tags and notes accumulate: a camera
+// has the union of the tags and notes of every layer. Each tag's source is recorded under
+// labels.tags.ID.TAG so that tag rm can name the layer an inherited tag comes from.
+func mergeLabelsLayer(dst *Config, l *LocalLabels, source string, src configSources) {
+	if l == nil || len(l.Cameras) == 0 && len(l.Tags) == 0 && len(l.Notes) == 0 {
+		return
+	}
+	if dst.Labels == nil {
+		dst.Labels = &LocalLabels{}
+	}
+	if len(l.Cameras) > 0 && dst.Labels.Cameras == nil {
+		dst.Labels.Cameras = map[string]string{}
+	}
+	for k, v := range l.Cameras {
+		dst.Labels.Cameras[k] = v
+		src["labels.cameras."+k] = source
+	}
+	if len(l.Tags) > 0 && dst.Labels.Tags == nil {
+		dst.Labels.Tags = map[string][]string{}
+	}
+	for k, v := range l.Tags {
+		dst.Labels.Tags[k] = addTags(dst.Labels.Tags[k], v)
+		src["labels.tags."+k] = source
+		for _, t := range v {
+			src["labels.tags."+k+"."+t] = source
+		}
+	}
+	if len(l.Notes) > 0 && dst.Labels.Notes == nil {
+		dst.Labels.Notes = map[string][]CameraNote{}
+	}
+	for k, v := range l.Notes {
+		dst.Labels.Notes[k] = mergeCameraNotes(dst.Labels.Notes[k], v)
+		src["labels.notes."+k] = source
+	}
+}
+
+// applyConfigOverrides layers the VERKCLI_*/VERKADA_* environment and then the global flags
+// over cfg.
+func applyConfigOverrides(cfg *Config, rf rootFlags, src configSources) {
+	env := func(key string, to *string, names ...string) {
+		for _, n := range names {
+			if v, ok := os.LookupEnv(n); ok {
+				if v != "" {
+					*to = v
+					src[key] = "env:" + n
+				}
+				return
+			}
+		}
+	}
+	env("base_url", &cfg.BaseURL, "VERKCLI_BASE_URL", "VERKADA_BASE_URL")
+	env("org_id", &cfg.OrgID, "VERKCLI_ORG_ID", "VERKADA_ORG_ID")
+	env("auth.api_key", &cfg.Auth.APIKey, "VERKCLI_API_KEY", "VERKADA_API_KEY")
+	env("auth.token", &cfg.Auth.Token, "VERKCLI_TOKEN", "VERKADA_TOKEN")
+
+	flag := func(key string, to *string, v, name string) {
+		if v != "" {
+			*to = v
+			src[key] = "flag:--" + name
+		}
+	}
+	flag("base_url", &cfg.BaseURL, rf.BaseURL, "base-url")
+	flag("org_id", &cfg.OrgID, rf.OrgID, "org-id")
+	flag("auth.api_key", &cfg.Auth.APIKey, rf.APIKey, "api-key")
+	flag("auth.token", &cfg.Auth.Token, rf.Token, "token")
+}
+
+type explainedValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// explainConfig lists every non-empty effective value with its source, in config-file order.
+func explainConfig(cfg Config, src configSources) []explainedValue {
+	var out []explainedValue
+	add := func(key, value string) {
+		if value != "" {
+			out = append(out, explainedValue{Key: key, Value: value, Source: src[key]})
+		}
+	}
+	add("extends", cfg.Extends)
+	add("base_url", cfg.BaseURL)
+	add("org_id", cfg.OrgID)
+	add("label_store", cfg.LabelStore)
+	add("auth.api_key", cfg.Auth.APIKey)
+	add("auth.token", cfg.Auth.Token)
+	if cfg.Auth.TokenAcquiredAt != 0 {
+		add("auth.token_acquired_at", fmt.Sprint(cfg.Auth.TokenAcquiredAt))
+	}
+	if cfg.Capabilities != nil {
+		add("capabilities", formatCapabilities(cfg.Capabilities))
+	}
+	for _, k := range sortedKeys(cfg.Headers) {
+		add("headers."+k, cfg.Headers[k])
+	}
+	if cfg.Labels != nil {
+		for _, k := range sortedKeys(cfg.Labels.Cameras) {
+			add("labels.cameras."+k, cfg.Labels.Cameras[k])
+		}
+		for _, k := range sortedKeys(cfg.Labels.Tags) {
+			add("labels.tags."+k, strings.Join(cfg.Labels.Tags[k], ","))
+		}
+		for _, k := range sortedKeys(cfg.Labels.Notes) {
+			if n := len(cfg.Labels.Notes[k]); n > 0 {
+				add("labels.notes."+k, fmt.Sprintf("%d note(s)", n))
+			}
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveInherited resolves the selected profile as if edit had been applied to its own stored
+// labels, which tells what the other layers (defaults, parent profiles, the project config)
+// still provide once the profile's own value is gone.
+func resolveInherited(rf rootFlags, edit func(l *LocalLabels)) (Config, configSources, error) {
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	cf, err := loadConfig(p)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	name, err := selectedProfileName(rf, cf)
+	if err != nil {
+		return Config{}, nil, err
+	}
+	if prof, ok := cf.Profiles[name]; ok {
+		prof = cloneProfile(prof)
+		edit(prof.Labels)
+		cf.Profiles[name] = prof
+	}
+	_, cfg, src, err := resolveEffectiveConfig(rf, p, cf)
+	return cfg, src, err
+}
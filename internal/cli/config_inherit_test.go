@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func inheritanceConfig() ConfigFile {
+	return ConfigFile{
+		CurrentProfile: "eu-prod",
+		Defaults: &Config{
+			BaseURL: "https://api.verkada.com",
+			Headers: map[string]string{"X-Team": "ops", "X-Env": "unknown"},
+		},
+		Profiles: map[string]Config{
+			"base": {
+				OrgID:   "ORG-BASE",
+				Headers: map[string]string{"X-Env": "prod"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Lobby", "CAM2": "Dock"}},
+			},
+			"eu": {
+				Extends: "base",
+				BaseURL: "https://api.eu.verkada.com",
+			},
+			"eu-prod": {
+				Extends: "eu",
+				OrgID:   "ORG-EU",
+				Auth:    AuthConfig{APIKey: "key-eu"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM2": "Loading dock"}},
+			},
+		},
+	}
+}
+
+func TestResolveProfileMergeOrder(t *testing.T) {
+	got, src, err := resolveProfile(inheritanceConfig(), "eu-prod")
+	if err != nil {
+		t.Fatalf("resolveProfile: %v", err)
+	}
+	if got.BaseURL != "https://api.eu.verkada.com" || src["base_url"] != "profile:eu" {
+		t.Fatalf("base_url = %q from %q", got.BaseURL, src["base_url"])
+	}
+	if got.OrgID != "ORG-EU" || src["org_id"] != "profile:eu-prod" {
+		t.Fatalf("org_id = %q from %q", got.OrgID, src["org_id"])
+	}
+	if got.Headers["X-Team"] != "ops" || src["headers.X-Team"] != "defaults" {
+		t.Fatalf("X-Team = %q from %q", got.Headers["X-Team"], src["headers.X-Team"])
+	}
+	if got.Headers["X-Env"] != "prod" || src["headers.X-Env"] != "profile:base" {
+		t.Fatalf("X-Env = %q from %q", got.Headers["X-Env"], src["headers.X-Env"])
+	}
+	if got.Labels.Cameras["CAM1"] != "Lobby" || got.Labels.Cameras["CAM2"] != "Loading dock" {
+		t.Fatalf("labels = %v", got.Labels.Cameras)
+	}
+	if got.Extends != "eu" {
+		t.Fatalf("extends = %q", got.Extends)
+	}
+}
+
+func TestResolveProfileErrors(t *testing.T) {
+	cf := inheritanceConfig()
+	base := cf.Profiles["base"]
+	base.Extends = "eu-prod"
+	cf.Profiles["base"] = base
+	_, _, err := resolveProfile(cf, "eu-prod")
+	if err == nil || !strings.Contains(err.Error(), "cycle: eu-prod -> eu -> base -> eu-prod") {
+		t.Fatalf("cycle err = %v", err)
+	}
+
+	cf = inheritanceConfig()
+	eu := cf.Profiles["eu"]
+	eu.Extends = "missing"
+	cf.Profiles["eu"] = eu
+	_, _, err = resolveProfile(cf, "eu-prod")
+	if err == nil || !strings.Contains(err.Error(), `"eu" extends unknown profile "missing"`) {
+		t.Fatalf("missing parent err = %v", err)
+	}
+}
+
+func TestConfigViewExplain(t *testing.T) {
+	for _, k := range []string{"VERKCLI_BASE_URL", "VERKADA_BASE_URL", "VERKCLI_API_KEY", "VERKADA_API_KEY", "VERKCLI_TOKEN", "VERKADA_TOKEN", "VERKCLI_ORG_ID", "VERKADA_ORG_ID", "VERKCLI_PROFILE", "VERKADA_PROFILE"} {
+		t.Setenv(k, "")
+	}
+	t.Setenv("VERKCLI_ORG_ID", "ORG-ENV")
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, inheritanceConfig()); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	out, err := runProfilesCLI(t, "--config", cfgPath, "--api-key", "key-flag", "--output", "json", "config", "view", "--explain")
+	if err != nil {
+		t.Fatalf("config view --explain: %v", err)
+	}
+	var got struct {
+		Profile string           `json:"profile"`
+		Values  []explainedValue `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	sources := map[string]string{}
+	for _, v := range got.Values {
+		sources[v.Key] = v.Source
+	}
+	want := map[string]string{
+		"extends":             "profile:eu-prod",
+		"base_url":            "profile:eu",
+		"org_id":              "env:VERKCLI_ORG_ID",
+		"auth.api_key":        "flag:--api-key",
+		"headers.X-Team":      "defaults",
+		"labels.cameras.CAM1": "profile:base",
+		"labels.cameras.CAM2": "profile:eu-prod",
+	}
+	for k, v := range want {
+		if sources[k] != v {
+			t.Errorf("%s source = %q, want %q", k, sources[k], v)
+		}
+	}
+
+	out, err = runProfilesCLI(t, "--config", cfgPath, "config", "view", "--explain")
+	if err != nil {
+		t.Fatalf("config view --explain (text): %v", err)
+	}
+	if !strings.Contains(out, "profile: eu-prod") || !strings.Contains(out, "env:VERKCLI_ORG_ID") {
+		t.Fatalf("text output:\n%s", out)
+	}
+}
+
+func TestProfilesRenameAndRmWithChildren(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, inheritanceConfig()); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "profiles", "rm", "base"); err == nil || !strings.Contains(err.Error(), "extended by eu") {
+		t.Fatalf("rm base err = %v", err)
+	}
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "profiles", "rename", "eu", "europe"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cf.Profiles["eu-prod"].Extends; got != "europe" {
+		t.Fatalf("eu-prod extends = %q after rename", got)
+	}
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "--profile", "base", "profiles", "set", "extends", "eu-prod"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("set extends cycle err = %v", err)
+	}
+}
+
+func TestCamerasLabelRmInherited(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, k := range []string{"VERKCLI_PROFILE", "VERKADA_PROFILE"} {
+		t.Setenv(k, "")
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "prod",
+		Defaults:       &Config{Labels: &LocalLabels{Cameras: map[string]string{"CAM9": "Roof"}}},
+		Profiles: map[string]Config{
+			"base": {BaseURL: "https://api.verkada.com", OrgID: "ORG1", Labels: &LocalLabels{Cameras: map[string]string{"CAM1": "Front", "CAM2": "Dock"}}},
+			"prod": {Extends: "base", Labels: &LocalLabels{Cameras: map[string]string{"CAM2": "Loading dock", "CAM3": "Lobby"}}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, cfg, err := effectiveProfileConfig(rootFlags{ConfigPath: cfgPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idxPath := mustBuildLabelTestIndex(t, cfg, []map[string]any{
+		{"camera_id": "CAM1", "name": "Door"},
+		{"camera_id": "CAM2", "name": "Bay"},
+	})
+
+	for id, layer := range map[string]string{"CAM1": "profile:base", "CAM9": "defaults"} {
+		_, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "rm", id)
+		if err == nil || !strings.Contains(err.Error(), layer) {
+			t.Fatalf("rm inherited %s: %v", id, err)
+		}
+	}
+	res, err := searchCamerasIndex(idxPath, "front", 10)
+	if err != nil || len(res.Results) != 1 {
+		t.Fatalf("failed rm changed the index: %+v, %v", res, err)
+	}
+
+	// Removing the profile's own override falls back to the parent's label.
+	for _, id := range []string{"CAM2", "CAM3"} {
+		if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "rm", id); err != nil {
+			t.Fatalf("rm own %s: %v", id, err)
+		}
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); len(got) != 0 {
+		t.Fatalf("prod labels = %v", got)
+	}
+	res, err = searchCamerasIndex(idxPath, "dock", 10)
+	if err != nil || len(res.Results) != 1 || res.Results[0].CameraID != "CAM2" {
+		t.Fatalf("index after rm of an override = %+v, %v", res, err)
+	}
+	if res, err := searchCamerasIndex(idxPath, "loading", 10); err != nil || len(res.Results) != 0 {
+		t.Fatalf("index kept the removed override: %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// configLockTimeout bounds how long a command waits for another verkcli process to finish
+// updating the config file.
+var configLockTimeout = 10 * time.Second
+
+// lockConfig takes an exclusive advisory lock for the config file at path and returns a func
+// that releases it. The lock lives on a sibling ".lock" file because writeConfig replaces the
+// config file itself on every write. For a symlinked config the lock sits next to the target, so
+// every path to the file shares it.
+func lockConfig(path string) (func(), error) {
+	path = resolveSymlinks(path)
+	return lockFileAt(path+".lock", path)
+}
+
+// lockFileAt takes an exclusive advisory lock on lockPath, creating it if needed. path is the
+// file the lock guards and is only used in errors.
+func lockFileAt(lockPath, path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(configLockTimeout)
+	for {
+		ok, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("lock %s: %w", f.Name(), err)
+		}
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("%s is locked by another verkcli process (waited %s)", path, configLockTimeout)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// updateConfig runs a locked load-modify-write cycle on the config file at path. fn is only
+// called when the file exists and parses; a non-nil error from fn skips the write.
+func updateConfig(path string, fn func(cf *ConfigFile) error) error {
+	unlock, err := lockConfig(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cf, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(&cf); err != nil {
+		return err
+	}
+	return writeConfig(path, cf)
+}
+
+// writeFileAtomic writes data to a temp file next to path, syncs it and renames it over path,
+// so readers see either the old or the new contents, never a partial file. A symlink at path
+// (e.g. a config kept in a dotfiles repository) is followed and the target replaced, so the link
+// survives.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	path = resolveSymlinks(path)
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func(err error) error {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return cleanup(err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return cleanup(err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return cleanup(err)
+	}
+	// Best-effort: persist the rename itself (not every platform can sync a directory).
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// resolveSymlinks returns path with symlinks resolved, or path itself when it does not exist yet.
+func resolveSymlinks(path string) string {
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		return p
+	}
+	return path
+}
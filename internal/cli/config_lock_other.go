@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cli
+
+import "os"
+
+// Release builds target darwin and linux; elsewhere config writes are atomic but unlocked.
+func tryLockFile(*os.File) (bool, error) { return true, nil }
+
+func unlockFile(*os.File) error { return nil }
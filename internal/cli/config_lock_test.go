@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	labelHelperEnv = "VERKCLI_TEST_LABEL_HELPER"
+	labelWrites    = 10
+)
+
+// TestLabelSetHelperProcess is run as a subprocess by TestConfigConcurrentLabelSet.
+func TestLabelSetHelperProcess(t *testing.T) {
+	spec := os.Getenv(labelHelperEnv)
+	if spec == "" {
+		t.Skip("helper process only")
+	}
+	cfgPath, prefix, _ := strings.Cut(spec, "|")
+	for i := 0; i < labelWrites; i++ {
+		if _, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "set", "--force", fmt.Sprintf("%s-%d", prefix, i), "label"); err != nil {
+			t.Fatalf("label set: %v", err)
+		}
+	}
+}
+
+func TestConfigConcurrentLabelSet(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("VERKCLI_PROFILE", "")
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "default",
+		Profiles:       map[string]Config{"default": {BaseURL: "https://api.example.com"}},
+	}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	const goroutines, processes = 8, 4
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines+processes)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < labelWrites; i++ {
+				cmd := NewRootCmd()
+				cmd.SetOut(&strings.Builder{})
+				cmd.SetErr(&strings.Builder{})
+				cmd.SetArgs([]string{"--config", cfgPath, "cameras", "label", "set", "--force", fmt.Sprintf("g%d-%d", g, i), "label"})
+				if err := cmd.Execute(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	for p := 0; p < processes; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestLabelSetHelperProcess$")
+			cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s|p%d", labelHelperEnv, cfgPath, p))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errs <- fmt.Errorf("helper %d: %v\n%s", p, err, out)
+			}
+		}(p)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatalf("config left unreadable: %v", err)
+	}
+	labels := cf.Profiles["default"].Labels.Cameras
+	if want := (goroutines + processes) * labelWrites; len(labels) != want {
+		t.Fatalf("got %d labels, want %d (lost updates)", len(labels), want)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(cfgPath), ".config.json.tmp-*")); len(matches) > 0 {
+		t.Fatalf("temp files left behind: %v", matches)
+	}
+}
+
+func TestWriteConfigAtomicPermissions(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeConfig(cfgPath, ConfigFile{Profiles: map[string]Config{"default": {BaseURL: "https://api.example.com"}}}); err != nil {
+		t.Fatalf("writeConfig: %v", err)
+	}
+	st, err := os.Stat(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := st.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("perm = %o, want 600", perm)
+	}
+}
+
+func TestWriteConfigFollowsSymlink(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	target := filepath.Join(dotfiles, "verkcli.json")
+	if err := writeConfig(target, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": {BaseURL: "https://api.verkada.com"}}}); err != nil {
+		t.Fatal(err)
+	}
+	cfgDir := t.TempDir()
+	link := filepath.Join(cfgDir, "config.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	if _, err := runProfilesCLI(t, "--config", link, "cameras", "label", "set", "CAM1", "Lobby", "--force"); err != nil {
+		t.Fatalf("label set through a symlink: %v", err)
+	}
+	if st, err := os.Lstat(link); err != nil || st.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("config symlink was replaced: %v", err)
+	}
+	if got := mustLoadLabels(t, target, "prod"); got["CAM1"] != "Lobby" {
+		t.Fatalf("target labels = %v", got)
+	}
+	if _, err := os.Stat(target + ".lock"); err != nil {
+		t.Fatalf("lock should sit next to the target: %v", err)
+	}
+	entries, _ := os.ReadDir(cfgDir)
+	if len(entries) != 1 {
+		t.Fatalf("files written next to the symlink: %v", entries)
+	}
+}
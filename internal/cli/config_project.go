@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// projectConfigNames are the project-local config files looked up from the working directory
+// upwards; within one directory the first match wins.
+var projectConfigNames = []string{".verkcli.yaml", ".verkcli.yml", ".verkcli.toml", ".verkcli.json"}
+
+// ProjectConfig is a per-repository config meant to be checked in. It has no auth fields on
+// purpose: secrets stay in the user config or the environment.
+type ProjectConfig struct {
+	Profile string       `json:"profile,omitempty"`
+	BaseURL string       `json:"base_url,omitempty"`
+	OrgID   string       `json:"org_id,omitempty"`
+	Output  string       `json:"output,omitempty"`
+	Labels  *LocalLabels `json:"labels,omitempty"`
+	// LabelStore may be relative to the project file, e.g. a labels.yaml next to it.
+	LabelStore string `json:"label_store,omitempty"`
+}
+
+// configFileInfo describes a config file that contributed to the effective config.
+type configFileInfo struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"` // user or project
+	Format string `json:"format"`
+}
+
+// findProjectConfig returns the nearest project config at or above dir, or "" if there is none.
+func findProjectConfig(dir string) string {
+	for {
+		for _, name := range projectConfigNames {
+			p := filepath.Join(dir, name)
+			if st, err := os.Stat(p); err == nil && !st.IsDir() {
+				return p
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func loadProjectConfig(path string) (ProjectConfig, error) {
+	var pc ProjectConfig
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return pc, err
+	}
+	if b, err = configToJSON(path, b); err != nil {
+		return pc, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&pc); err != nil && !errors.Is(err, io.EOF) {
+		return pc, fmt.Errorf("project config %s: %w (allowed keys: profile, base_url, org_id, output, labels, label_store)", path, err)
+	}
+	if pc.Output != "" && pc.Output != "text" && pc.Output != "json" {
+		return pc, fmt.Errorf("project config %s: output must be text or json (got %q)", path, pc.Output)
+	}
+	return pc, nil
+}
+
+// discoverProjectConfig loads the project config for the working directory. path is "" when
+// there is none.
+func discoverProjectConfig() (string, ProjectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", ProjectConfig{}, nil
+	}
+	path := findProjectConfig(wd)
+	if path == "" {
+		return "", ProjectConfig{}, nil
+	}
+	pc, err := loadProjectConfig(path)
+	return path, pc, err
+}
+
+// selectedProfileName is the profile commands act on: --profile, VERKCLI_PROFILE, the project
+// config, the config file's current_profile, then "default". A project config that does not
+// parse is an error, as it is for effectiveProfileConfig.
+func selectedProfileName(rf rootFlags, cf ConfigFile) (string, error) {
+	_, pc, err := discoverProjectConfig()
+	if err != nil {
+		return "", err
+	}
+	return firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), pc.Profile, cf.CurrentProfile, "default"), nil
+}
+
+// applyProjectConfig layers a project config over the resolved profile; labels merge per camera.
+// A checked-in file must not redirect credentials: unless trusted (--trust-project or
+// VERKCLI_TRUST_PROJECT), it may not point base_url at another host than the profile's, nor
+// set an http(s) label_store, which is sent VERKCLI_LABEL_STORE_TOKEN.
+func applyProjectConfig(cfg *Config, pc ProjectConfig, path string, src configSources, trusted bool) error {
+	source := "project:" + path
+	if !trusted {
+		if pc.BaseURL != "" && !sameOrigin(pc.BaseURL, cfg.BaseURL) {
+			return fmt.Errorf("project config %s sets base_url to %s, not the profile's %s; credentials would be sent there (pass --trust-project if you trust this file)", path, pc.BaseURL, firstNonEmpty(cfg.BaseURL, "(none)"))
+		}
+		if isHTTPLabelStore(pc.LabelStore) && pc.LabelStore != cfg.LabelStore {
+			return fmt.Errorf("project config %s sets label_store to %s; the label store token would be sent there (pass --trust-project if you trust this file)", path, pc.LabelStore)
+		}
+	}
+	if pc.BaseURL != "" {
+		cfg.BaseURL = pc.BaseURL
+		src["base_url"] = source
+	}
+	if pc.OrgID != "" {
+		cfg.OrgID = pc.OrgID
+		src["org_id"] = source
+	}
+	if pc.LabelStore != "" {
+		cfg.LabelStore = projectLabelStore(pc.LabelStore, path)
+		src["label_store"] = source
+	}
+	mergeLabelsLayer(cfg, pc.Labels, source, src)
+	return nil
+}
+
+// projectTrusted reports whether the project config may change where credentials go.
+func projectTrusted(rf rootFlags) bool {
+	if rf.TrustProject {
+		return true
+	}
+	v, err := strconv.ParseBool(os.Getenv("VERKCLI_TRUST_PROJECT"))
+	return err == nil && v
+}
+
+// sameOrigin reports whether two base URLs share scheme and host.
+func sameOrigin(a, b string) bool {
+	ua, err := url.Parse(strings.TrimSpace(a))
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(strings.TrimSpace(b))
+	if err != nil {
+		return false
+	}
+	return ua.Host != "" && strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Host, ub.Host)
+}
+
+func isHTTPLabelStore(spec string) bool {
+	return strings.HasPrefix(spec, "https://") || strings.HasPrefix(spec, "http://")
+}
+
+// configFilesInUse lists the config files that feed the effective config, lowest precedence
+// first. Env vars and flags apply on top of the last one.
+func configFilesInUse(rf rootFlags) []configFileInfo {
+	var files []configFileInfo
+	if p, err := resolveConfigPath(rf.ConfigPath); err == nil {
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, configFileInfo{Path: p, Kind: "user", Format: configFormatFor(p)})
+		}
+	}
+	if p, _, err := discoverProjectConfig(); p != "" && err == nil {
+		files = append(files, configFileInfo{Path: p, Kind: "project", Format: configFormatFor(p)})
+	}
+	return files
+}
+
+// applyProjectOutput makes the project config's output the default for --output.
+func applyProjectOutput(cmd *cobra.Command, rf *rootFlags) {
+	if f := cmd.Flags().Lookup("output"); f == nil || f.Changed {
+		return
+	}
+	if _, pc, err := discoverProjectConfig(); err == nil && pc.Output != "" {
+		rf.Output = pc.Output
+	}
+}
+
+func writeConfigFilesText(w io.Writer, files []configFileInfo) {
+	fmt.Fprintln(w, "files (lowest precedence first; env vars and flags apply on top):")
+	if len(files) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	}
+	for i, f := range files {
+		fmt.Fprintf(w, "  %d. %s (%s, %s)\n", i+1, f.Path, f.Kind, f.Format)
+	}
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigFormatsRoundTrip(t *testing.T) {
+	orig := ConfigFile{
+		CurrentProfile: "prod",
+		Defaults:       &Config{Headers: map[string]string{"X-Team": "ops"}},
+		Profiles: map[string]Config{
+			"prod": {
+				BaseURL: "https://api.verkada.com",
+				OrgID:   "12345",
+				Auth:    AuthConfig{APIKey: "key", Token: "tok", TokenAcquiredAt: 1760000000},
+				Headers: map[string]string{},
+				Labels:  &LocalLabels{Cameras: map[string]string{"0042": "Lobby", "CAM2": "yes"}},
+			},
+		},
+	}
+	for _, name := range []string{"config.json", "config.yaml", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), name)
+			if err := writeConfig(p, orig); err != nil {
+				t.Fatalf("writeConfig: %v", err)
+			}
+			got, err := loadConfig(p)
+			if err != nil {
+				b, _ := os.ReadFile(p)
+				t.Fatalf("loadConfig: %v\n%s", err, b)
+			}
+			want := orig
+			normalizeConfigFile(&want)
+			if !reflect.DeepEqual(got.Profiles, want.Profiles) || got.CurrentProfile != "prod" || got.Defaults.Headers["X-Team"] != "ops" {
+				t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigYAMLHandWritten(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "config.yml")
+	yml := `
+current_profile: eu
+profiles:
+  eu:
+    base_url: https://api.eu.verkada.com
+    org_id: ORG1
+    labels:
+      cameras:
+        1234: Dock
+`
+	if err := os.WriteFile(p, []byte(yml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cf, err := loadConfig(p)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if got := cf.Profiles["eu"].Labels.Cameras["1234"]; got != "Dock" {
+		t.Fatalf("numeric camera key label = %q", got)
+	}
+}
+
+func TestProjectConfigDiscoveryAndPrecedence(t *testing.T) {
+	for _, k := range []string{"VERKCLI_BASE_URL", "VERKADA_BASE_URL", "VERKCLI_ORG_ID", "VERKADA_ORG_ID", "VERKCLI_PROFILE", "VERKADA_PROFILE"} {
+		t.Setenv(k, "")
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "default",
+		Profiles: map[string]Config{
+			"default": {BaseURL: "https://api.verkada.com", OrgID: "ORG-DEFAULT"},
+			"eu": {
+				BaseURL: "https://api.eu.verkada.com",
+				OrgID:   "ORG-EU",
+				Auth:    AuthConfig{APIKey: "key-eu"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Mine", "CAM2": "Dock"}},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	repo := t.TempDir()
+	proj := filepath.Join(repo, ".verkcli.yaml")
+	if err := os.WriteFile(proj, []byte("profile: eu\norg_id: ORG-PROJECT\noutput: json\nlabels:\n  cameras:\n    CAM1: Lobby\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+
+	// No --output: the project config makes JSON the default.
+	out, err := runProfilesCLI(t, "--config", cfgPath, "config", "view")
+	if err != nil {
+		t.Fatalf("config view: %v", err)
+	}
+	var view struct {
+		Profile string           `json:"profile"`
+		Files   []configFileInfo `json:"files"`
+		Config
+	}
+	if err := json.Unmarshal([]byte(out), &view); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if view.Profile != "eu" || view.OrgID != "ORG-PROJECT" || view.BaseURL != "https://api.eu.verkada.com" || view.Auth.APIKey != "key-eu" {
+		t.Fatalf("view = %+v", view)
+	}
+	if view.Labels.Cameras["CAM1"] != "Lobby" || view.Labels.Cameras["CAM2"] != "Dock" {
+		t.Fatalf("labels = %v", view.Labels.Cameras)
+	}
+	if len(view.Files) != 2 || view.Files[0].Kind != "user" || view.Files[1].Path != proj || view.Files[1].Format != "yaml" {
+		t.Fatalf("files = %+v", view.Files)
+	}
+
+	// Env and flags still win over the project file.
+	t.Setenv("VERKCLI_ORG_ID", "ORG-ENV")
+	out, err = runProfilesCLI(t, "--config", cfgPath, "--output", "text", "config", "view", "--explain")
+	if err != nil {
+		t.Fatalf("config view --explain: %v", err)
+	}
+	for _, want := range []string{"1. " + cfgPath + " (user, json)", "2. " + proj + " (project, yaml)", "env:VERKCLI_ORG_ID", "project:" + proj} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestProjectConfigRejectsSecrets(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".verkcli.toml")
+	if err := os.WriteFile(p, []byte("profile = \"eu\"\n[auth]\napi_key = \"secret\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadProjectConfig(p); err == nil || !strings.Contains(err.Error(), `unknown field "auth"`) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProjectConfigCannotRedirectCredentials(t *testing.T) {
+	for _, k := range []string{"VERKCLI_BASE_URL", "VERKADA_BASE_URL", "VERKCLI_PROFILE", "VERKADA_PROFILE", "VERKCLI_TRUST_PROJECT"} {
+		t.Setenv(k, "")
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := writeConfig(cfgPath, ConfigFile{
+		CurrentProfile: "prod",
+		Profiles:       map[string]Config{"prod": {BaseURL: "https://api.verkada.com", OrgID: "ORG1", Auth: AuthConfig{APIKey: "key"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	t.Chdir(repo)
+	proj := filepath.Join(repo, ".verkcli.yaml")
+	for _, tc := range []struct{ yml, want string }{
+		{"base_url: https://evil.example.com\n", "sets base_url to https://evil.example.com"},
+		{"base_url: http://api.verkada.com\n", "sets base_url to http://api.verkada.com"},
+		{"label_store: https://labels.example.com/prod\n", "sets label_store to https://labels.example.com/prod"},
+	} {
+		if err := os.WriteFile(proj, []byte(tc.yml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := runProfilesCLI(t, "--config", cfgPath, "config", "view")
+		if err == nil || !strings.Contains(err.Error(), tc.want) || !strings.Contains(err.Error(), "--trust-project") {
+			t.Fatalf("%q: err = %v", tc.yml, err)
+		}
+		if _, err := runProfilesCLI(t, "--config", cfgPath, "--trust-project", "config", "view"); err != nil {
+			t.Fatalf("%q with --trust-project: %v", tc.yml, err)
+		}
+	}
+
+	// The same host (and a file label store) need no trust.
+	if err := os.WriteFile(proj, []byte("base_url: https://API.verkada.com\nlabel_store: labels.yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runProfilesCLI(t, "--config", cfgPath, "config", "view"); err != nil {
+		t.Fatalf("same host: %v", err)
+	}
+
+	// A broken project file fails profile selection too, not just resolution.
+	if err := os.WriteFile(proj, []byte("bogus: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := selectedProfileName(rootFlags{}, ConfigFile{}); err == nil || !strings.Contains(err.Error(), "allowed keys") {
+		t.Fatalf("selectedProfileName with a broken project config: %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -49,6 +50,9 @@ func newCamerasFootageCmd(rf *rootFlags) *cobra.Command {
 	}
 	cmd.AddCommand(newCamerasFootageURLCmd(rf))
 	cmd.AddCommand(newCamerasFootageDownloadCmd(rf))
+	cmd.AddCommand(newCamerasFootageRecordCmd(rf))
+	cmd.AddCommand(newCamerasFootageCoverageCmd(rf))
+	cmd.AddCommand(newCamerasFootageFrameCmd(rf))
 	return cmd
 }
 
@@ -70,6 +74,9 @@ func newCamerasFootageURLCmd(rf *rootFlags) *cobra.Command {
 			if strings.TrimSpace(f.CameraID) == "" {
 				return errors.New("--camera-id is required")
 			}
+			if f.CameraID, err = resolveCameraArg(*rf, cfg, f.CameraID); err != nil {
+				return err
+			}
 
 			client := &http.Client{Timeout: f.Timeout}
 			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
@@ -79,7 +86,7 @@ func newCamerasFootageURLCmd(rf *rootFlags) *cobra.Command {
 				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
 			}
 
-			startTime, endTime, err := resolveStreamTimes(f)
+			startTime, endTime, err := resolveFootageWindow(cmd.ErrOrStderr(), client, &cfg, rf, f)
 			if err != nil {
 				return err
 			}
@@ -120,16 +127,19 @@ func newCamerasFootageDownloadCmd(rf *rootFlags) *cobra.Command {
 			if strings.TrimSpace(f.CameraID) == "" {
 				return errors.New("--camera-id is required")
 			}
+			if f.CameraID, err = resolveCameraArg(*rf, cfg, f.CameraID); err != nil {
+				return err
+			}
 			if strings.TrimSpace(f.OutPath) == "" {
 				return errors.New("--out is required")
 			}
 
-			startTime, endTime, err := resolveStreamTimes(f)
+			startTime, endTime, err := resolveFootageWindow(cmd.ErrOrStderr(), &http.Client{Timeout: f.Timeout}, &cfg, rf, f)
 			if err != nil {
 				return err
 			}
 			if startTime == 0 || endTime == 0 {
-				return errors.New("download requires historical times; provide --start and --end (or omit --live)")
+				return errors.New("download requires historical times; provide --start and --end (use `verkcli cameras footage record` for live footage)")
 			}
 
 			if _, err := exec.LookPath("ffmpeg"); err != nil {
@@ -153,52 +163,18 @@ func newCamerasFootageDownloadCmd(rf *rootFlags) *cobra.Command {
 				return err
 			}
 
-			playlist, err := fetchText(client, streamURL, cfg, rf)
+			tmpPath, err := writeFootagePlaylist(client, cfg, rf, streamURL)
 			if err != nil {
 				return err
 			}
-
-			rewriteURL, _ := url.Parse(streamURL)
-			baseQuery := rewriteURL.Query()
-			rewritten, err := rewriteM3U8(playlist, rewriteURL, baseQuery)
-			if err != nil {
-				return err
-			}
-
-			tmp, err := os.CreateTemp("", "verkcli_footage_*.m3u8")
-			if err != nil {
-				return err
-			}
-			tmpPath := tmp.Name()
-			_ = tmp.Close()
 			defer os.Remove(tmpPath)
 
-			if err := os.WriteFile(tmpPath, rewritten, 0o600); err != nil {
-				return err
-			}
-
 			if dir := filepath.Dir(f.OutPath); dir != "." {
 				if err := os.MkdirAll(dir, 0o755); err != nil {
 					return err
 				}
 			}
-
-			argsFF := []string{
-				"-hide_banner",
-				"-loglevel", "error",
-				"-protocol_whitelist", "file,http,https,tcp,tls,crypto",
-				"-allowed_extensions", "ALL",
-			}
-			if f.Force {
-				argsFF = append(argsFF, "-y")
-			} else {
-				argsFF = append(argsFF, "-n")
-			}
-			argsFF = append(argsFF,
-				"-i", tmpPath,
-				"-c", "copy",
-				f.OutPath,
-			)
+			argsFF := footageDownloadFFmpegArgs(tmpPath, f.OutPath, f.Force)
 
 			if f.PrintFFMpeg {
 				fmt.Fprintln(cmd.OutOrStdout(), "ffmpeg "+shellQuoteArgs(argsFF))
@@ -225,15 +201,75 @@ func newCamerasFootageDownloadCmd(rf *rootFlags) *cobra.Command {
 
 func addFootageCommonFlags(cmd *cobra.Command, f *camerasFootageFlags) {
 	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (required)")
-	cmd.Flags().StringVar(&f.Start, "start", "", "Start time for historical footage. Accepts Unix seconds, RFC3339, RFC3339 without timezone, or 'YYYY-MM-DD HH:MM:SS'.")
-	cmd.Flags().StringVar(&f.End, "end", "", "End time for historical footage. Accepts Unix seconds, RFC3339, RFC3339 without timezone, or 'YYYY-MM-DD HH:MM:SS'.")
-	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --start/--end values.")
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (alias of --camera-id)")
+	cmd.Flags().StringVar(&f.Start, "start", "", "Start time for historical footage: "+timeExprHelp)
+	cmd.Flags().StringVar(&f.End, "end", "", "End time for historical footage: "+timeExprHelp)
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --start/--end values (\"camera\" uses the camera's own timezone).")
 	cmd.Flags().BoolVar(&f.Live, "live", false, "Stream live footage (equivalent to start_time=0,end_time=0)")
 	cmd.Flags().StringVar(&f.Resolution, "resolution", "low_res", "Resolution: low_res|high_res")
 	cmd.Flags().StringVar(&f.Codec, "codec", "hevc", "Codec: hevc|h264 (depending on camera/availability)")
 	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout")
 }
 
+// resolveFootageWindow is resolveStreamTimes with support for --tz camera, which is only looked up
+// when there is a historical window to interpret.
+func resolveFootageWindow(w io.Writer, client *http.Client, cfg *Config, rf *rootFlags, f camerasFootageFlags) (int64, int64, error) {
+	cameraTZ := isCameraTimezone(f.Timezone) && !f.Live && strings.TrimSpace(f.Start) != ""
+	if cameraTZ {
+		tz, err := lookupCameraTimezone(client, cfg, rf, f.CameraID)
+		if err != nil {
+			return 0, 0, err
+		}
+		f.Timezone = tz
+	}
+	st, et, err := resolveStreamTimes(f)
+	if err == nil && cameraTZ {
+		noteCameraTimezone(w, f.CameraID, f.Timezone, st)
+	}
+	return st, et, err
+}
+
+// writeFootagePlaylist fetches the HLS playlist, rewrites its URIs to absolute signed URLs and
+// writes it to a temp file for ffmpeg. The caller removes the file.
+func writeFootagePlaylist(client *http.Client, cfg Config, rf *rootFlags, streamURL string) (string, error) {
+	playlist, err := fetchText(client, streamURL, cfg, rf)
+	if err != nil {
+		return "", err
+	}
+	rewriteURL, _ := url.Parse(streamURL)
+	rewritten, err := rewriteM3U8(playlist, rewriteURL, rewriteURL.Query())
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp("", "verkcli_footage_*.m3u8")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	if err := os.WriteFile(tmpPath, rewritten, 0o600); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+	return tmpPath, nil
+}
+
+// footageDownloadFFmpegArgs remuxes a local playlist into an MP4 without re-encoding.
+func footageDownloadFFmpegArgs(playlistPath, outPath string, force bool) []string {
+	args := []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-protocol_whitelist", "file,http,https,tcp,tls,crypto",
+		"-allowed_extensions", "ALL",
+	}
+	if force {
+		args = append(args, "-y")
+	} else {
+		args = append(args, "-n")
+	}
+	return append(args, "-i", playlistPath, "-c", "copy", outPath)
+}
+
 func resolveStreamTimes(f camerasFootageFlags) (startTime int64, endTime int64, err error) {
 	if f.Live {
 		return 0, 0, nil
@@ -280,47 +316,115 @@ func buildFootageTokenURL(baseURL string) (string, error) {
 }
 
 func fetchStreamingJWT(client *http.Client, cfg Config, rf *rootFlags) (string, error) {
-	tu, err := buildFootageTokenURL(cfg.BaseURL)
+	tok, err := fetchStreamingToken(client, cfg, rf)
 	if err != nil {
 		return "", err
 	}
+	return tok.JWT, nil
+}
+
+// fetchStreamingToken returns the full footage token response, including expiry and
+// permission details, for callers that need more than the jwt itself.
+func fetchStreamingToken(client *http.Client, cfg Config, rf *rootFlags) (footageTokenResponseV1, error) {
+	var out footageTokenResponseV1
+	tu, err := buildFootageTokenURL(cfg.BaseURL)
+	if err != nil {
+		return out, err
+	}
 	req, err := http.NewRequest("GET", tu, nil)
 	if err != nil {
-		return "", err
+		return out, err
 	}
 	applyDefaultHeaders(req, cfg)
 	if err := applyHeaderFlags(req, rf.Headers); err != nil {
-		return "", err
+		return out, err
 	}
 	applyBestEffortAuth(req, cfg) // ensures x-api-key is present when configured
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return out, err
 	}
 	defer resp.Body.Close()
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return out, err
 	}
 	if looksLikeHTML(resp.Header.Get("Content-Type"), b) {
-		return "", errors.New("received HTML from footage token endpoint (check --base-url is https://api(.eu|.au).verkada.com and auth header x-api-key)")
+		return out, errors.New("received HTML from footage token endpoint (check --base-url is https://api(.eu|.au).verkada.com and auth header x-api-key)")
 	}
 	if resp.StatusCode >= 400 {
 		if pretty, ok := tryPrettyJSON(b); ok {
-			return "", fmt.Errorf("footage token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(pretty)))
+			return out, fmt.Errorf("footage token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(pretty)))
 		}
-		return "", fmt.Errorf("footage token request failed with status %d", resp.StatusCode)
+		return out, fmt.Errorf("footage token request failed with status %d", resp.StatusCode)
 	}
 
-	var out footageTokenResponseV1
 	if err := json.Unmarshal(b, &out); err != nil {
-		return "", err
+		return out, err
 	}
 	if strings.TrimSpace(out.JWT) == "" {
-		return "", errors.New("footage token response missing jwt field")
+		return out, errors.New("footage token response missing jwt field")
 	}
-	return out.JWT, nil
+	return out, nil
+}
+
+// streamingJWTSource caches a streaming JWT and transparently refreshes it shortly before
+// it expires. Long-running commands (wall, record) share one source across goroutines.
+type streamingJWTSource struct {
+	client *http.Client
+	cfg    Config
+	rf     *rootFlags
+
+	mu        sync.Mutex
+	jwt       string
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+func newStreamingJWTSource(client *http.Client, cfg Config, rf *rootFlags) *streamingJWTSource {
+	return &streamingJWTSource{client: client, cfg: cfg, rf: rf, now: time.Now}
+}
+
+// JWT returns a cached token, fetching a new one when missing or within a minute of expiry.
+func (s *streamingJWTSource) JWT() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jwt != "" && s.now().Before(s.expiresAt.Add(-time.Minute)) {
+		return s.jwt, nil
+	}
+	tok, err := fetchStreamingToken(s.client, s.cfg, s.rf)
+	if err != nil {
+		return "", err
+	}
+	s.jwt = tok.JWT
+	s.expiresAt = streamingTokenExpiry(tok, s.now())
+	return s.jwt, nil
+}
+
+// Invalidate forces the next JWT call to fetch a fresh token (e.g. after a 401 from the stream).
+func (s *streamingJWTSource) Invalidate() {
+	s.mu.Lock()
+	s.jwt = ""
+	s.mu.Unlock()
+}
+
+func streamingTokenExpiry(tok footageTokenResponseV1, now time.Time) time.Time {
+	if tok.ExpiresAt > 0 {
+		// Some responses report milliseconds; normalize to seconds.
+		ts := tok.ExpiresAt
+		if ts > 1e12 {
+			ts /= 1000
+		}
+		if t := time.Unix(ts, 0); t.After(now) {
+			return t
+		}
+	}
+	if tok.Expiration > 0 {
+		return now.Add(time.Duration(tok.Expiration) * time.Second)
+	}
+	// Tokens are documented as short-lived; be conservative when the response omits expiry.
+	return now.Add(15 * time.Minute)
 }
 
 func buildFootageStreamM3U8URL(baseURL, orgID, cameraID, jwt string, startTime, endTime int64, resolution, codec string) (string, error) {
@@ -409,6 +513,24 @@ func fetchText(client *http.Client, reqURL string, cfg Config, rf *rootFlags) ([
 func rewriteM3U8(in []byte, playlistURL *url.URL, requiredQuery url.Values) ([]byte, error) {
 	// Rewrite relative URIs to absolute and ensure required query params (org_id/camera_id/jwt/etc) are present
 	// on segment/key URIs. This makes tooling like ffmpeg more reliable across HLS variants.
+	return mapM3U8URIs(in, playlistURL, func(u *url.URL) string {
+		q := u.Query()
+		for k, vals := range requiredQuery {
+			if q.Has(k) {
+				continue
+			}
+			for _, v := range vals {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	})
+}
+
+// mapM3U8URIs resolves every URI in a playlist (segment lines and URI="..." tag attributes)
+// against playlistURL and replaces it with the result of fn.
+func mapM3U8URIs(in []byte, playlistURL *url.URL, fn func(u *url.URL) string) ([]byte, error) {
 	lines := strings.Split(string(in), "\n")
 	var out strings.Builder
 	out.Grow(len(in) + 256)
@@ -418,7 +540,7 @@ func rewriteM3U8(in []byte, playlistURL *url.URL, requiredQuery url.Values) ([]b
 		if trim == "" {
 			out.WriteString(line)
 		} else if strings.HasPrefix(trim, "#") {
-			rewritten, err := rewriteM3U8TagLine(line, playlistURL, requiredQuery)
+			rewritten, err := rewriteM3U8TagLine(line, playlistURL, fn)
 			if err != nil {
 				return nil, fmt.Errorf("invalid m3u8 tag on line %d: %w", i+1, err)
 			}
@@ -431,17 +553,7 @@ func rewriteM3U8(in []byte, playlistURL *url.URL, requiredQuery url.Values) ([]b
 			if !u.IsAbs() {
 				u = playlistURL.ResolveReference(u)
 			}
-			q := u.Query()
-			for k, vals := range requiredQuery {
-				if q.Has(k) {
-					continue
-				}
-				for _, v := range vals {
-					q.Add(k, v)
-				}
-			}
-			u.RawQuery = q.Encode()
-			out.WriteString(u.String())
+			out.WriteString(fn(u))
 		}
 
 		// Preserve trailing newline behavior.
@@ -457,7 +569,7 @@ func rewriteM3U8(in []byte, playlistURL *url.URL, requiredQuery url.Values) ([]b
 	return b, nil
 }
 
-func rewriteM3U8TagLine(line string, playlistURL *url.URL, requiredQuery url.Values) (string, error) {
+func rewriteM3U8TagLine(line string, playlistURL *url.URL, fn func(u *url.URL) string) (string, error) {
 	// Some HLS tags embed URIs inside the tag line, e.g.:
 	// - #EXT-X-KEY:...URI="key.key"...
 	// - #EXT-X-MAP:URI="init.mp4"...
@@ -488,18 +600,8 @@ func rewriteM3U8TagLine(line string, playlistURL *url.URL, requiredQuery url.Val
 		if !u.IsAbs() {
 			u = playlistURL.ResolveReference(u)
 		}
-		q := u.Query()
-		for k, vals := range requiredQuery {
-			if q.Has(k) {
-				continue
-			}
-			for _, v := range vals {
-				q.Add(k, v)
-			}
-		}
-		u.RawQuery = q.Encode()
 
-		repl := u.String()
+		repl := fn(u)
 		out = out[:start] + repl + out[end:]
 		pos = start + len(repl)
 	}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// footageChunk is the largest window the streaming API accepts per historical playlist.
+const footageChunk = time.Hour
+
+// footageCoverageMaxWindow keeps a typo in --start from issuing thousands of playlist requests.
+const footageCoverageMaxWindow = 7 * 24 * time.Hour
+
+type camerasFootageCoverageFlags struct {
+	CameraID    string
+	Start       string
+	End         string
+	Timezone    string
+	Resolution  string
+	Codec       string
+	MinCoverage float64
+	Tolerance   time.Duration
+	Timeout     time.Duration
+}
+
+type coverageInterval struct {
+	Status          string  `json:"status"` // covered|missing
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+type footageCoverageReport struct {
+	CameraID        string             `json:"camera_id"`
+	Start           string             `json:"start"`
+	End             string             `json:"end"`
+	WindowSeconds   float64            `json:"window_seconds"`
+	CoveredSeconds  float64            `json:"covered_seconds"`
+	CoveragePercent float64            `json:"coverage_percent"`
+	Intervals       []coverageInterval `json:"intervals"`
+	ChunkErrors     []string           `json:"chunk_errors,omitempty"`
+}
+
+func newCamerasFootageCoverageCmd(rf *rootFlags) *cobra.Command {
+	var f camerasFootageCoverageFlags
+
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Report which parts of a time window have recorded footage",
+		Long: strings.TrimSpace(`
+Fetches the historical HLS playlist for the window (one request per hour) and computes covered
+and missing intervals from segment durations (EXTINF) and EXT-X-PROGRAM-DATE-TIME.
+
+Use --min-coverage to exit non-zero when the covered percentage is below a threshold, e.g. in
+scripts that check retention before downloading.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras footage coverage --camera CAM123 --start 2026-02-15T00:00:00Z --end 2026-02-15T06:00:00Z
+  verkcli --output json cameras footage coverage --camera "Front Door" --start "2026-02-15 08:00:00" --end "2026-02-15 10:00:00" --tz America/Los_Angeles
+  verkcli cameras footage coverage --camera CAM123 --start 1739570400 --end 1739577600 --min-coverage 95
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.CameraID) == "" {
+				return errors.New("--camera is required")
+			}
+			if strings.TrimSpace(f.Start) == "" || strings.TrimSpace(f.End) == "" {
+				return errors.New("both --start and --end are required")
+			}
+			cameraID, err := resolveCameraArg(*rf, cfg, f.CameraID)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: f.Timeout}
+			tz, err := resolveTimezoneFlag(client, &cfg, rf, f.Timezone, cameraID)
+			if err != nil {
+				return err
+			}
+			st, err := parseThumbnailTimestamp(f.Start, tz)
+			if err != nil {
+				return fmt.Errorf("invalid --start: %w", err)
+			}
+			et, err := parseThumbnailTimestamp(f.End, tz)
+			if err != nil {
+				return fmt.Errorf("invalid --end: %w", err)
+			}
+			if isCameraTimezone(f.Timezone) {
+				noteCameraTimezone(cmd.ErrOrStderr(), cameraID, tz, st)
+			}
+			if et <= st {
+				return errors.New("--end must be after --start")
+			}
+			start, end := time.Unix(st, 0).UTC(), time.Unix(et, 0).UTC()
+			if end.Sub(start) > footageCoverageMaxWindow {
+				return fmt.Errorf("window too large: end-start must be <= %s", footageCoverageMaxWindow)
+			}
+
+			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
+				return err
+			}
+			if strings.TrimSpace(cfg.OrgID) == "" {
+				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
+			}
+
+			jwt := newStreamingJWTSource(client, cfg, rf)
+			spans, chunkErrs, err := fetchFootageSpans(cmd.Context(), client, cfg, rf, jwt, cameraID, start, end, f.Resolution, f.Codec)
+			if err != nil {
+				return err
+			}
+
+			rep := computeFootageCoverage(start, end, spans, f.Tolerance)
+			rep.CameraID = cameraID
+			rep.ChunkErrors = chunkErrs
+
+			out := cmd.OutOrStdout()
+			if rf.Output == "json" {
+				blob, err := json.MarshalIndent(rep, "", "  ")
+				if err != nil {
+					return err
+				}
+				blob = append(blob, '\n')
+				_, _ = out.Write(blob)
+			} else {
+				fmt.Fprint(out, formatFootageCoverageText(rep))
+			}
+
+			if f.MinCoverage > 0 && rep.CoveragePercent < f.MinCoverage {
+				return fmt.Errorf("coverage %.1f%% is below --min-coverage %.1f%%", rep.CoveragePercent, f.MinCoverage)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (required)")
+	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (alias of --camera)")
+	cmd.Flags().StringVar(&f.Start, "start", "", "Window start: "+timeExprHelp)
+	cmd.Flags().StringVar(&f.End, "end", "", "Window end (same formats as --start)")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --start/--end values (\"camera\" uses the camera's own timezone).")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low_res", "Resolution: low_res|high_res")
+	cmd.Flags().StringVar(&f.Codec, "codec", "hevc", "Codec: hevc|h264 (depending on camera/availability)")
+	cmd.Flags().Float64Var(&f.MinCoverage, "min-coverage", 0, "Exit non-zero when coverage percent is below this value (0 disables)")
+	cmd.Flags().DurationVar(&f.Tolerance, "tolerance", 2*time.Second, "Gaps shorter than this are treated as continuous footage")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+type timeSpan struct {
+	Start time.Time
+	End   time.Time
+}
+
+// fetchFootageSpans requests the historical playlist hour by hour and returns the time span of
+// each listed segment. Chunks the API rejects (e.g. no footage retained) are reported in
+// chunkErrs and count as missing; auth failures abort.
+func fetchFootageSpans(ctx context.Context, client *http.Client, cfg Config, rf *rootFlags, jwt *streamingJWTSource, cameraID string, start, end time.Time, resolution, codec string) ([]timeSpan, []string, error) {
+	var spans []timeSpan
+	var chunkErrs []string
+	for cs := start; cs.Before(end); cs = cs.Add(footageChunk) {
+		ce := cs.Add(footageChunk)
+		if ce.After(end) {
+			ce = end
+		}
+
+		var pl hlsPlaylist
+		var err error
+		for attempt := 0; attempt < 2; attempt++ {
+			var token string
+			token, err = jwt.JWT()
+			if err != nil {
+				return nil, nil, err
+			}
+			var u string
+			u, err = buildFootageStreamM3U8URL(cfg.BaseURL, cfg.OrgID, cameraID, token, cs.Unix(), ce.Unix(), resolution, codec)
+			if err != nil {
+				return nil, nil, err
+			}
+			pl, _, err = fetchMediaPlaylist(ctx, client, u, cfg, rf)
+			if !errors.Is(err, errHLSUnauthorized) {
+				break
+			}
+			jwt.Invalidate()
+		}
+		if errors.Is(err, errHLSUnauthorized) {
+			return nil, nil, err
+		}
+		if err != nil {
+			chunkErrs = append(chunkErrs, fmt.Sprintf("%s..%s: %v", cs.Format(time.RFC3339), ce.Format(time.RFC3339), err))
+			continue
+		}
+
+		// Segments without a program date are laid out back to back from the chunk start.
+		cursor := cs
+		for _, seg := range pl.Segments {
+			d := time.Duration(seg.Duration * float64(time.Second))
+			s := cursor
+			if !seg.ProgramDateTime.IsZero() {
+				s = seg.ProgramDateTime
+			}
+			spans = append(spans, timeSpan{Start: s, End: s.Add(d)})
+			cursor = s.Add(d)
+		}
+	}
+	return spans, chunkErrs, nil
+}
+
+// computeFootageCoverage clips spans to the window, merges overlaps and gaps within tolerance,
+// and returns alternating covered/missing intervals that exactly tile the window.
+func computeFootageCoverage(start, end time.Time, spans []timeSpan, tolerance time.Duration) footageCoverageReport {
+	rep := footageCoverageReport{
+		Start:         start.UTC().Format(time.RFC3339),
+		End:           end.UTC().Format(time.RFC3339),
+		WindowSeconds: end.Sub(start).Seconds(),
+	}
+
+	clipped := make([]timeSpan, 0, len(spans))
+	for _, s := range spans {
+		if s.Start.Before(start) {
+			s.Start = start
+		}
+		if s.End.After(end) {
+			s.End = end
+		}
+		if s.End.After(s.Start) {
+			clipped = append(clipped, s)
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool { return clipped[i].Start.Before(clipped[j].Start) })
+
+	var merged []timeSpan
+	for _, s := range clipped {
+		if n := len(merged); n > 0 && !s.Start.After(merged[n-1].End.Add(tolerance)) {
+			if s.End.After(merged[n-1].End) {
+				merged[n-1].End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	add := func(status string, s, e time.Time) {
+		if !e.After(s) {
+			return
+		}
+		rep.Intervals = append(rep.Intervals, coverageInterval{
+			Status:          status,
+			Start:           s.UTC().Format(time.RFC3339),
+			End:             e.UTC().Format(time.RFC3339),
+			DurationSeconds: e.Sub(s).Seconds(),
+		})
+	}
+	cursor := start
+	for _, s := range merged {
+		add("missing", cursor, s.Start)
+		add("covered", s.Start, s.End)
+		rep.CoveredSeconds += s.End.Sub(s.Start).Seconds()
+		cursor = s.End
+	}
+	add("missing", cursor, end)
+
+	if rep.WindowSeconds > 0 {
+		rep.CoveragePercent = rep.CoveredSeconds / rep.WindowSeconds * 100
+	}
+	return rep
+}
+
+func formatFootageCoverageText(rep footageCoverageReport) string {
+	var b strings.Builder
+	window := time.Duration(rep.WindowSeconds * float64(time.Second))
+	covered := time.Duration(rep.CoveredSeconds * float64(time.Second)).Round(time.Second)
+	fmt.Fprintf(&b, "camera: %s\nwindow: %s .. %s (%s)\ncoverage: %.1f%% (%s of %s)\n\n",
+		rep.CameraID, rep.Start, rep.End, window, rep.CoveragePercent, covered, window)
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tSTART\tEND\tDURATION")
+	for _, iv := range rep.Intervals {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", iv.Status, iv.Start, iv.End, time.Duration(iv.DurationSeconds*float64(time.Second)).Round(time.Second))
+	}
+	_ = tw.Flush()
+
+	for _, e := range rep.ChunkErrors {
+		fmt.Fprintf(&b, "warning: %s\n", e)
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeFootageCoverage_MergesAndReportsGaps(t *testing.T) {
+	start := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Minute)
+	at := func(min, sec int) time.Time {
+		return start.Add(time.Duration(min)*time.Minute + time.Duration(sec)*time.Second)
+	}
+
+	spans := []timeSpan{
+		{Start: at(-1, 0), End: at(2, 0)},  // clipped at window start
+		{Start: at(2, 1), End: at(4, 0)},   // 1s gap, within tolerance
+		{Start: at(3, 0), End: at(3, 30)},  // overlap
+		{Start: at(6, 0), End: at(9, 0)},   // after a 2m gap
+		{Start: at(11, 0), End: at(12, 0)}, // outside the window
+	}
+	rep := computeFootageCoverage(start, end, spans, 2*time.Second)
+
+	want := []string{
+		"covered 2026-02-15T14:00:00Z 2026-02-15T14:04:00Z 240",
+		"missing 2026-02-15T14:04:00Z 2026-02-15T14:06:00Z 120",
+		"covered 2026-02-15T14:06:00Z 2026-02-15T14:09:00Z 180",
+		"missing 2026-02-15T14:09:00Z 2026-02-15T14:10:00Z 60",
+	}
+	var got []string
+	for _, iv := range rep.Intervals {
+		got = append(got, fmt.Sprintf("%s %s %s %.0f", iv.Status, iv.Start, iv.End, iv.DurationSeconds))
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("intervals:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if rep.CoveredSeconds != 420 || rep.WindowSeconds != 600 || rep.CoveragePercent != 70 {
+		t.Fatalf("unexpected totals: %+v", rep)
+	}
+}
+
+func TestComputeFootageCoverage_NoFootage(t *testing.T) {
+	start := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+	rep := computeFootageCoverage(start, start.Add(time.Hour), nil, time.Second)
+	if len(rep.Intervals) != 1 || rep.Intervals[0].Status != "missing" || rep.CoveragePercent != 0 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
+
+func TestFetchFootageSpans_ChunksHourly(t *testing.T) {
+	var windows []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/footage/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jwt":"jwt-1","expiration":1800}`)
+	})
+	mux.HandleFunc("/stream/cameras/v1/footage/stream/stream.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		windows = append(windows, q.Get("start_time")+"-"+q.Get("end_time"))
+		if len(windows) == 2 {
+			http.Error(w, `{"message":"no footage"}`, http.StatusNotFound)
+			return
+		}
+		// No PROGRAM-DATE-TIME: segments are laid out from the chunk start.
+		fmt.Fprint(w, "#EXTM3U\n#EXT-X-TARGETDURATION:60\n#EXTINF:60,\nseg0.ts\n#EXTINF:60,\nseg1.ts\n#EXT-X-ENDLIST\n")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cfg := Config{BaseURL: srv.URL, OrgID: "ORG", Headers: map[string]string{}}
+	rf := &rootFlags{}
+	client := srv.Client()
+	start := time.Unix(1771164000, 0).UTC()
+	end := start.Add(150 * time.Minute)
+
+	spans, chunkErrs, err := fetchFootageSpans(t.Context(), client, cfg, rf, newStreamingJWTSource(client, cfg, rf), "CAM", start, end, "low_res", "h264")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 hourly requests, got %v", windows)
+	}
+	if windows[2] != "1771171200-1771173000" {
+		t.Fatalf("last chunk should be clamped to end: %v", windows)
+	}
+	if len(chunkErrs) != 1 || !strings.Contains(chunkErrs[0], "no footage") {
+		t.Fatalf("chunk errors: %v", chunkErrs)
+	}
+	if len(spans) != 4 || !spans[2].Start.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("unexpected spans: %+v", spans)
+	}
+}
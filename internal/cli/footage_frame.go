@@ -0,0 +1,436 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type camerasFootageFrameFlags struct {
+	CameraID   string
+	At         string
+	Every      time.Duration
+	Until      string
+	Timezone   string
+	OutPath    string
+	OutDir     string
+	Format     string
+	Window     time.Duration
+	MaxFrames  int
+	Resolution string
+	Codec      string
+	Force      bool
+	Timeout    time.Duration
+}
+
+type extractedFrame struct {
+	At            string  `json:"at"`
+	Path          string  `json:"path,omitempty"`
+	SegmentStart  string  `json:"segment_start,omitempty"`
+	OffsetSeconds float64 `json:"offset_seconds"`
+	// DeltaSeconds is how far the decoded position is from the requested time; non-zero only
+	// when the requested time falls in a recording gap.
+	DeltaSeconds float64 `json:"delta_seconds"`
+	Error        string  `json:"error,omitempty"`
+}
+
+func newCamerasFootageFrameCmd(rf *rootFlags) *cobra.Command {
+	var f camerasFootageFrameFlags
+
+	cmd := &cobra.Command{
+		Use:   "frame",
+		Short: "Extract the exact still frame at a timestamp from historical footage (requires ffmpeg)",
+		Long: strings.TrimSpace(`
+Unlike "cameras thumbnail", which returns the closest cached thumbnail, this fetches the HLS
+segment covering --at from historical footage and decodes the frame nearest to that time.
+
+--every and --until go together: a frame is extracted for each step and written to --out-dir as
+<camera>_<UTC time>.<ext>, which makes a timelapse folder. Consecutive timestamps that fall in
+the same segment reuse the downloaded segment.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras footage frame --camera CAM123 --at 2026-02-15T14:03:27Z --out frame.jpg
+  verkcli cameras footage frame --camera "Front Door" --at "2026-02-15 06:00:00" --tz America/Los_Angeles --format png
+  verkcli cameras footage frame --camera CAM123 --at 2026-02-15T14:00:00Z --every 10s --until 2026-02-15T14:10:00Z --out-dir ./timelapse
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.CameraID) == "" {
+				return errors.New("--camera is required")
+			}
+			if strings.TrimSpace(f.At) == "" {
+				return errors.New("--at is required")
+			}
+			cameraID, err := resolveCameraArg(*rf, cfg, f.CameraID)
+			if err != nil {
+				return err
+			}
+			client := &http.Client{Timeout: f.Timeout}
+			tz, err := resolveTimezoneFlag(client, &cfg, rf, f.Timezone, cameraID)
+			if err != nil {
+				return err
+			}
+			atUnix, err := parseThumbnailTimestamp(f.At, tz)
+			if err != nil {
+				return fmt.Errorf("invalid --at: %w", err)
+			}
+			if isCameraTimezone(f.Timezone) {
+				noteCameraTimezone(cmd.ErrOrStderr(), cameraID, tz, atUnix)
+			}
+			at := time.Unix(atUnix, 0).UTC()
+			until := at
+			if strings.TrimSpace(f.Until) != "" {
+				u, err := parseThumbnailTimestamp(f.Until, tz)
+				if err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
+				until = time.Unix(u, 0).UTC()
+			}
+			times, err := frameTimestamps(at, until, f.Every, f.MaxFrames)
+			if err != nil {
+				return err
+			}
+			format, err := frameFormat(f.Format, f.OutPath)
+			if err != nil {
+				return err
+			}
+			if len(times) > 1 && strings.TrimSpace(f.OutPath) != "" {
+				return errors.New("--out writes a single frame; use --out-dir with --every/--until")
+			}
+			if f.Window < 2*time.Second {
+				return errors.New("--window must be at least 2s")
+			}
+			if _, err := exec.LookPath("ffmpeg"); err != nil {
+				return errors.New("ffmpeg not found in PATH; install ffmpeg to decode frames (or use `verkcli cameras thumbnail` for cached thumbnails)")
+			}
+			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
+				return err
+			}
+			if strings.TrimSpace(cfg.OrgID) == "" {
+				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
+			}
+			outDir := f.OutDir
+			if outDir == "" {
+				outDir = "."
+			}
+			if err := os.MkdirAll(outDir, 0o755); err != nil {
+				return err
+			}
+			tmpDir, err := os.MkdirTemp("", "verkcli_frame_*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tmpDir)
+
+			ex := &frameExtractor{
+				client:     client,
+				cfg:        cfg,
+				rf:         rf,
+				jwt:        newStreamingJWTSource(client, cfg, rf),
+				cameraID:   cameraID,
+				resolution: f.Resolution,
+				codec:      f.Codec,
+				window:     f.Window,
+				tmpDir:     tmpDir,
+			}
+
+			var frames []extractedFrame
+			failed := 0
+			for _, t := range times {
+				path := f.OutPath
+				if path == "" {
+					path = filepath.Join(outDir, sanitizePathComponent(cameraID)+"_"+t.Format("20060102T150405Z")+"."+format)
+				}
+				fr := extractedFrame{At: t.Format(time.RFC3339)}
+				if !f.Force {
+					if _, err := os.Stat(path); err == nil {
+						fr.Error = "output exists (use --force to overwrite)"
+						frames = append(frames, fr)
+						failed++
+						fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s: %s\n", fr.At, path, fr.Error)
+						continue
+					}
+				}
+				if err := ex.Extract(cmd.Context(), t, path, &fr); err != nil {
+					if errors.Is(err, errHLSUnauthorized) {
+						return err
+					}
+					fr.Error = err.Error()
+					failed++
+					fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", fr.At, err)
+				} else {
+					fr.Path = path
+					if fr.DeltaSeconds != 0 {
+						fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (nearest footage is %.1fs from requested time)\n", path, fr.DeltaSeconds)
+					} else {
+						fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s\n", path)
+					}
+				}
+				frames = append(frames, fr)
+			}
+
+			if rf.Output == "json" {
+				blob, err := json.MarshalIndent(map[string]any{
+					"camera_id": cameraID,
+					"frames":    frames,
+				}, "", "  ")
+				if err != nil {
+					return err
+				}
+				blob = append(blob, '\n')
+				_, _ = cmd.OutOrStdout().Write(blob)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d frames failed", failed, len(times))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (required)")
+	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (alias of --camera)")
+	cmd.Flags().StringVar(&f.At, "at", "", "Timestamp of the frame: "+timeExprHelp)
+	cmd.Flags().DurationVar(&f.Every, "every", 0, "Extract a frame every interval from --at to --until")
+	cmd.Flags().StringVar(&f.Until, "until", "", "Last timestamp for --every (inclusive; same formats as --at)")
+	cmd.Flags().StringVar(&f.Timezone, "tz", "local", "Timezone used for naive --at/--until values (\"camera\" uses the camera's own timezone).")
+	cmd.Flags().StringVarP(&f.OutPath, "out", "o", "", "Output file for a single frame (default: <out-dir>/<camera>_<time>.<format>)")
+	cmd.Flags().StringVar(&f.OutDir, "out-dir", "", "Directory for extracted frames (default: current directory)")
+	cmd.Flags().StringVar(&f.Format, "format", "", "Image format: jpg|png (default: from --out extension, else jpg)")
+	cmd.Flags().DurationVar(&f.Window, "window", 30*time.Second, "Footage window fetched around each timestamp")
+	cmd.Flags().IntVar(&f.MaxFrames, "max-frames", 1000, "Refuse sequences longer than this")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "high_res", "Resolution: low_res|high_res")
+	cmd.Flags().StringVar(&f.Codec, "codec", "hevc", "Codec: hevc|h264 (depending on camera/availability)")
+	cmd.Flags().BoolVar(&f.Force, "force", false, "Overwrite output files that exist")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+// frameTimestamps expands --at/--every/--until into the list of requested times.
+func frameTimestamps(at, until time.Time, every time.Duration, maxFrames int) ([]time.Time, error) {
+	if until.Equal(at) {
+		if every > 0 {
+			// Without a later --until, --every would quietly yield a single frame.
+			return nil, errors.New("--every requires an --until after --at")
+		}
+		return []time.Time{at}, nil
+	}
+	if until.Before(at) {
+		return nil, errors.New("--until must not be before --at")
+	}
+	if every <= 0 {
+		return nil, errors.New("--every is required with --until")
+	}
+	n := int(until.Sub(at)/every) + 1
+	if maxFrames > 0 && n > maxFrames {
+		return nil, fmt.Errorf("%d frames requested; raise --max-frames or --every", n)
+	}
+	out := make([]time.Time, 0, n)
+	for t := at; !t.After(until); t = t.Add(every) {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func frameFormat(format, outPath string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(outPath)) {
+		case ".png":
+			format = "png"
+		default:
+			format = "jpg"
+		}
+	}
+	switch format {
+	case "jpg", "jpeg":
+		return "jpg", nil
+	case "png":
+		return "png", nil
+	default:
+		return "", fmt.Errorf("invalid --format %q (expected jpg|png)", format)
+	}
+}
+
+// locateFrameSegment picks the segment that contains at, or the nearest one when at falls in
+// a gap, and returns the decode offset within it. Segments without a program date are assumed
+// to start at windowStart, laid out back to back. delta is the distance between at and the
+// position that will be decoded.
+func locateFrameSegment(pl hlsPlaylist, windowStart, at time.Time) (seg hlsSegment, segStart time.Time, offset, delta time.Duration, ok bool) {
+	cursor := windowStart
+	best := time.Duration(-1)
+	for _, s := range pl.Segments {
+		d := time.Duration(s.Duration * float64(time.Second))
+		st := cursor
+		if !s.ProgramDateTime.IsZero() {
+			st = s.ProgramDateTime
+		}
+		en := st.Add(d)
+		cursor = en
+
+		var dist time.Duration
+		var off time.Duration
+		switch {
+		case at.Before(st):
+			dist = st.Sub(at)
+		case !at.Before(en):
+			dist = at.Sub(en)
+			off = d
+			// Decode the last frame rather than seeking past the end.
+			if off > 100*time.Millisecond {
+				off -= 100 * time.Millisecond
+			}
+		default:
+			off = at.Sub(st)
+		}
+		if best < 0 || dist < best {
+			best = dist
+			seg, segStart, offset, delta, ok = s, st, off, dist, true
+			if dist == 0 {
+				return
+			}
+		}
+	}
+	return
+}
+
+// frameExtractor downloads the segment covering a timestamp and decodes one frame with ffmpeg.
+// The last segment is kept on disk so timelapse steps inside it skip the download.
+type frameExtractor struct {
+	client     *http.Client
+	cfg        Config
+	rf         *rootFlags
+	jwt        *streamingJWTSource
+	cameraID   string
+	resolution string
+	codec      string
+	window     time.Duration
+	tmpDir     string
+
+	cachedURI  string
+	cachedPath string
+}
+
+func (e *frameExtractor) Extract(ctx context.Context, at time.Time, outPath string, fr *extractedFrame) error {
+	half := e.window / 2
+	ws, we := at.Add(-half).Truncate(time.Second), at.Add(half).Truncate(time.Second)
+
+	var pl hlsPlaylist
+	var plURL *url.URL
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var token, u string
+		if token, err = e.jwt.JWT(); err != nil {
+			return err
+		}
+		if u, err = buildFootageStreamM3U8URL(e.cfg.BaseURL, e.cfg.OrgID, e.cameraID, token, ws.Unix(), we.Unix(), e.resolution, e.codec); err != nil {
+			return err
+		}
+		pl, plURL, err = fetchMediaPlaylist(ctx, e.client, u, e.cfg, e.rf)
+		if !errors.Is(err, errHLSUnauthorized) {
+			break
+		}
+		e.jwt.Invalidate()
+	}
+	if err != nil {
+		return err
+	}
+
+	seg, segStart, offset, delta, ok := locateFrameSegment(pl, ws, at)
+	if !ok {
+		return errors.New("no footage in the window around the requested time")
+	}
+	fr.SegmentStart = segStart.UTC().Format(time.RFC3339Nano)
+	fr.OffsetSeconds = offset.Seconds()
+	fr.DeltaSeconds = delta.Seconds()
+
+	segPath, err := e.segmentFile(ctx, pl, seg, plURL)
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(outPath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	c := exec.CommandContext(ctx, "ffmpeg", frameFFmpegArgs(segPath, offset, outPath)...)
+	if out, err := c.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg != "" {
+			return fmt.Errorf("ffmpeg failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("ffmpeg failed: %w", err)
+	}
+	if st, err := os.Stat(outPath); err != nil || st.Size() == 0 {
+		return errors.New("ffmpeg produced no frame (segment may be shorter than the requested offset)")
+	}
+	return nil
+}
+
+// segmentFile writes init+segment to a temp file, reusing the previous download when the
+// segment is the same. Segment URIs are compared without the jwt, which changes on refresh.
+func (e *frameExtractor) segmentFile(ctx context.Context, pl hlsPlaylist, seg hlsSegment, plURL *url.URL) (string, error) {
+	key := redactURLSecrets(seg.URI)
+	if key == e.cachedURI && e.cachedPath != "" {
+		return e.cachedPath, nil
+	}
+	fetch := func(raw string) ([]byte, error) {
+		u, err := signHLSURL(raw, plURL.Query())
+		if err != nil {
+			return nil, err
+		}
+		return fetchHLSResource(ctx, e.client, u, e.cfg, e.rf)
+	}
+
+	var data []byte
+	ext := ".ts"
+	if pl.MapURI != "" {
+		init, err := fetch(pl.MapURI)
+		if err != nil {
+			return "", err
+		}
+		data = append(data, init...)
+		ext = ".mp4"
+	}
+	b, err := fetch(seg.URI)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, b...)
+
+	path := filepath.Join(e.tmpDir, "segment"+ext)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	e.cachedURI, e.cachedPath = key, path
+	return path, nil
+}
+
+// frameFFmpegArgs seeks after -i so the decode is frame-accurate rather than snapping to the
+// previous keyframe.
+func frameFFmpegArgs(segPath string, offset time.Duration, outPath string) []string {
+	return []string{
+		"-hide_banner",
+		"-loglevel", "error",
+		"-y",
+		"-i", segPath,
+		"-ss", strconv.FormatFloat(offset.Seconds(), 'f', 3, 64),
+		"-frames:v", "1",
+		"-q:v", "2",
+		outPath,
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrameTimestamps(t *testing.T) {
+	at := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+
+	got, err := frameTimestamps(at, at, 0, 10)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("single: %v %v", got, err)
+	}
+	got, err = frameTimestamps(at, at.Add(time.Minute), 10*time.Second, 10)
+	if err != nil || len(got) != 7 || !got[6].Equal(at.Add(time.Minute)) {
+		t.Fatalf("sequence: %v %v", got, err)
+	}
+	if _, err := frameTimestamps(at, at.Add(time.Hour), time.Second, 100); err == nil || !strings.Contains(err.Error(), "--max-frames") {
+		t.Fatalf("expected max-frames error, got %v", err)
+	}
+	if _, err := frameTimestamps(at, at.Add(time.Minute), 0, 10); err == nil {
+		t.Fatalf("expected error for --until without --every")
+	}
+	if _, err := frameTimestamps(at, at, 10*time.Second, 10); err == nil || !strings.Contains(err.Error(), "--until") {
+		t.Fatalf("expected error for --every without --until, got %v", err)
+	}
+	if _, err := frameTimestamps(at, at.Add(-time.Minute), time.Second, 10); err == nil {
+		t.Fatalf("expected error for --until before --at")
+	}
+}
+
+func TestFrameFormat(t *testing.T) {
+	cases := []struct{ format, out, want string }{
+		{"", "", "jpg"},
+		{"", "x.PNG", "png"},
+		{"jpeg", "x.png", "jpg"},
+		{"png", "", "png"},
+	}
+	for _, c := range cases {
+		got, err := frameFormat(c.format, c.out)
+		if err != nil || got != c.want {
+			t.Fatalf("frameFormat(%q,%q)=%q,%v want %q", c.format, c.out, got, err, c.want)
+		}
+	}
+	if _, err := frameFormat("gif", ""); err == nil {
+		t.Fatalf("expected error for gif")
+	}
+}
+
+func TestLocateFrameSegment(t *testing.T) {
+	ws := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+	pdt := ws.Add(5 * time.Second)
+	pl := hlsPlaylist{Segments: []hlsSegment{
+		{URI: "a", Duration: 4, ProgramDateTime: pdt},
+		{URI: "b", Duration: 4, ProgramDateTime: pdt.Add(4 * time.Second)},
+		{URI: "c", Duration: 4, ProgramDateTime: pdt.Add(20 * time.Second)},
+	}}
+
+	seg, _, off, delta, ok := locateFrameSegment(pl, ws, pdt.Add(5500*time.Millisecond))
+	if !ok || seg.URI != "b" || off != 1500*time.Millisecond || delta != 0 {
+		t.Fatalf("inside: %s off=%s delta=%s ok=%v", seg.URI, off, delta, ok)
+	}
+
+	// In the gap between b (ends +8s) and c (starts +20s), nearer to c.
+	seg, _, off, delta, _ = locateFrameSegment(pl, ws, pdt.Add(17*time.Second))
+	if seg.URI != "c" || off != 0 || delta != 3*time.Second {
+		t.Fatalf("gap: %s off=%s delta=%s", seg.URI, off, delta)
+	}
+
+	// Without program dates, segments are laid out from the window start.
+	plain := hlsPlaylist{Segments: []hlsSegment{{URI: "x", Duration: 6}, {URI: "y", Duration: 6}}}
+	seg, start, off, _, _ := locateFrameSegment(plain, ws, ws.Add(7*time.Second))
+	if seg.URI != "y" || !start.Equal(ws.Add(6*time.Second)) || off != time.Second {
+		t.Fatalf("undated: %s start=%s off=%s", seg.URI, start, off)
+	}
+
+	if _, _, _, _, ok := locateFrameSegment(hlsPlaylist{}, ws, ws); ok {
+		t.Fatalf("expected no segment for empty playlist")
+	}
+}
@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type camerasFootageRecordFlags struct {
+	CameraID   string
+	Duration   time.Duration
+	Segment    time.Duration
+	OutDir     string
+	Resolution string
+	Codec      string
+	Timeout    time.Duration
+}
+
+func newCamerasFootageRecordCmd(rf *rootFlags) *cobra.Command {
+	var f camerasFootageRecordFlags
+
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record a live stream to disk in rolling segment files",
+		Long: strings.TrimSpace(`
+Records live footage by polling the live HLS playlist and appending new segments to disk as they
+appear. Output rotates to a new file every --segment of media time, and the streaming JWT is
+refreshed automatically for long recordings.
+
+Files are written as <camera>_<UTC start>.mp4 (fragmented MP4 streams) or .ts (MPEG-TS streams).
+Recording stops after --duration, when the stream ends, or on Ctrl-C; the file being written is
+always finalized before exit. No ffmpeg is required.
+`),
+		Example: strings.TrimSpace(`
+  verkcli cameras footage record --camera CAM123 --duration 2h --segment 10m --out-dir ./rec
+  verkcli cameras footage record --camera "Front Door" --out-dir ./rec   # until Ctrl-C
+`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := effectiveConfig(*rf)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(f.CameraID) == "" {
+				return errors.New("--camera is required")
+			}
+			if strings.TrimSpace(f.OutDir) == "" {
+				return errors.New("--out-dir is required")
+			}
+			if f.Segment <= 0 {
+				return errors.New("--segment must be positive")
+			}
+			if f.Duration < 0 {
+				return errors.New("--duration must not be negative")
+			}
+			cameraID, err := resolveCameraArg(*rf, cfg, f.CameraID)
+			if err != nil {
+				return err
+			}
+
+			client := &http.Client{Timeout: f.Timeout}
+			if _, err := ensureOrgID(client, &cfg, rf); err != nil {
+				return err
+			}
+			if strings.TrimSpace(cfg.OrgID) == "" {
+				return errors.New("org id is empty (set in config, VERKCLI_ORG_ID / VERKADA_ORG_ID, or --org-id)")
+			}
+			if err := os.MkdirAll(f.OutDir, 0o755); err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if f.Duration > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, f.Duration)
+				defer cancel()
+			}
+
+			w := newRollingSegmentWriter(f.OutDir, cameraID, f.Segment)
+			rec := &liveRecorder{
+				client:     client,
+				cfg:        cfg,
+				rf:         rf,
+				jwt:        newStreamingJWTSource(client, cfg, rf),
+				cameraID:   cameraID,
+				resolution: f.Resolution,
+				codec:      f.Codec,
+				w:          w,
+				log:        cmd.ErrOrStderr(),
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "recording %s to %s (Ctrl-C to stop)\n", cameraID, f.OutDir)
+			recErr := rec.Run(ctx)
+			closeErr := w.Close()
+
+			for _, rfile := range w.Files() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "wrote %s (%d segments, %.0fs, %d bytes)\n", rfile.Path, rfile.Segments, rfile.DurationSeconds, rfile.Bytes)
+			}
+			if rf.Output == "json" {
+				blob, err := json.MarshalIndent(map[string]any{
+					"camera_id": cameraID,
+					"out_dir":   f.OutDir,
+					"files":     w.Files(),
+				}, "", "  ")
+				if err != nil {
+					return err
+				}
+				blob = append(blob, '\n')
+				_, _ = cmd.OutOrStdout().Write(blob)
+			}
+
+			if recErr != nil {
+				return recErr
+			}
+			return closeErr
+		},
+	}
+
+	cmd.Flags().StringVar(&f.CameraID, "camera", "", "Camera reference: camera_id or local label (required)")
+	cmd.Flags().StringVar(&f.CameraID, "camera-id", "", "Camera ID (alias of --camera)")
+	cmd.Flags().DurationVar(&f.Duration, "duration", 0, "Stop after this long (0 records until Ctrl-C or the stream ends)")
+	cmd.Flags().DurationVar(&f.Segment, "segment", 10*time.Minute, "Rotate output files after this much media time")
+	cmd.Flags().StringVar(&f.OutDir, "out-dir", "", "Directory for recorded files (required)")
+	cmd.Flags().StringVar(&f.Resolution, "resolution", "low_res", "Resolution: low_res|high_res")
+	cmd.Flags().StringVar(&f.Codec, "codec", "hevc", "Codec: hevc|h264 (depending on camera/availability)")
+	cmd.Flags().DurationVar(&f.Timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	return cmd
+}
+
+// liveRecorder polls a live playlist and hands every new segment to a rollingSegmentWriter.
+type liveRecorder struct {
+	client     *http.Client
+	cfg        Config
+	rf         *rootFlags
+	jwt        *streamingJWTSource
+	cameraID   string
+	resolution string
+	codec      string
+	w          *rollingSegmentWriter
+	log        io.Writer
+}
+
+// maxRecordFailures bounds consecutive polling failures before giving up. Transient errors
+// (network blips, a single 5xx) are retried; a dead stream should not spin forever.
+const maxRecordFailures = 10
+
+// Run records until ctx is done or the playlist ends. Context cancellation is a normal stop.
+func (r *liveRecorder) Run(ctx context.Context) error {
+	lastSeq := int64(-1)
+	var initURI string
+	failures := 0
+	poll := 2 * time.Second
+
+	for {
+		pl, plURL, err := r.fetchPlaylist(ctx)
+		if err == nil {
+			failures = 0
+			if d := time.Duration(pl.TargetDuration / 2 * float64(time.Second)); d > 0 {
+				poll = min(max(d, time.Second), 10*time.Second)
+			}
+			if pl.MapURI != "" && pl.MapURI != initURI {
+				init, ferr := r.fetchSigned(ctx, pl.MapURI, plURL)
+				if ferr != nil {
+					err = ferr
+				} else {
+					initURI = pl.MapURI
+					r.w.SetInit(init)
+				}
+			}
+			if err == nil {
+				if n := len(pl.Segments); n > 0 && pl.Segments[n-1].Sequence < lastSeq {
+					// The sequence went backwards (stream restarted); start over from this playlist.
+					lastSeq = -1
+				}
+				for _, seg := range pl.Segments {
+					if seg.Sequence <= lastSeq {
+						continue
+					}
+					data, ferr := r.fetchSigned(ctx, seg.URI, plURL)
+					if ferr != nil {
+						err = ferr
+						break
+					}
+					if werr := r.w.Write(seg, data); werr != nil {
+						return werr
+					}
+					lastSeq = seg.Sequence
+				}
+			}
+			if err == nil && pl.EndList {
+				return nil
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, errHLSUnauthorized) {
+				r.jwt.Invalidate()
+			}
+			failures++
+			if failures >= maxRecordFailures {
+				return fmt.Errorf("recording stopped after %d consecutive failures: %w", failures, err)
+			}
+			fmt.Fprintf(r.log, "retrying after error: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(poll):
+		}
+	}
+}
+
+func (r *liveRecorder) fetchPlaylist(ctx context.Context) (hlsPlaylist, *url.URL, error) {
+	jwt, err := r.jwt.JWT()
+	if err != nil {
+		return hlsPlaylist{}, nil, err
+	}
+	u, err := buildFootageStreamM3U8URL(r.cfg.BaseURL, r.cfg.OrgID, r.cameraID, jwt, 0, 0, r.resolution, r.codec)
+	if err != nil {
+		return hlsPlaylist{}, nil, err
+	}
+	return fetchMediaPlaylist(ctx, r.client, u, r.cfg, r.rf)
+}
+
+func (r *liveRecorder) fetchSigned(ctx context.Context, raw string, playlistURL *url.URL) ([]byte, error) {
+	u, err := signHLSURL(raw, playlistURL.Query())
+	if err != nil {
+		return nil, err
+	}
+	return fetchHLSResource(ctx, r.client, u, r.cfg, r.rf)
+}
+
+type recordedFile struct {
+	Path            string  `json:"path"`
+	StartedAt       string  `json:"started_at"`
+	Segments        int     `json:"segments"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Bytes           int64   `json:"bytes"`
+}
+
+// rollingSegmentWriter concatenates HLS segments into files, rotating after interval of media
+// time. Concatenated MPEG-TS segments, and an fMP4 init segment followed by its media
+// segments, are both playable files without remuxing.
+type rollingSegmentWriter struct {
+	dir      string
+	prefix   string
+	interval time.Duration
+	now      func() time.Time
+
+	init    []byte
+	cur     *os.File
+	curFile recordedFile
+	files   []recordedFile
+}
+
+func newRollingSegmentWriter(dir, cameraID string, interval time.Duration) *rollingSegmentWriter {
+	return &rollingSegmentWriter{
+		dir:      dir,
+		prefix:   sanitizePathComponent(cameraID),
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// SetInit records the fMP4 initialization segment. A changed init segment forces a new file,
+// since media segments are only decodable with the init they were encoded against.
+func (w *rollingSegmentWriter) SetInit(init []byte) {
+	if w.cur != nil && w.init != nil && string(w.init) != string(init) {
+		_ = w.finalize()
+	}
+	w.init = init
+}
+
+func (w *rollingSegmentWriter) Write(seg hlsSegment, data []byte) error {
+	if w.cur != nil && w.curFile.DurationSeconds >= w.interval.Seconds() {
+		if err := w.finalize(); err != nil {
+			return err
+		}
+	}
+	if w.cur == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	n, err := w.cur.Write(data)
+	w.curFile.Bytes += int64(n)
+	if err != nil {
+		return err
+	}
+	w.curFile.Segments++
+	w.curFile.DurationSeconds += seg.Duration
+	return nil
+}
+
+func (w *rollingSegmentWriter) open() error {
+	ext := ".ts"
+	if w.init != nil {
+		ext = ".mp4"
+	}
+	started := w.now().UTC()
+	base := w.prefix + "_" + started.Format("20060102T150405Z")
+	path := filepath.Join(w.dir, base+ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(w.dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+
+	fh, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	w.cur = fh
+	w.curFile = recordedFile{Path: path, StartedAt: started.Format(time.RFC3339)}
+	if w.init != nil {
+		n, err := fh.Write(w.init)
+		w.curFile.Bytes += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *rollingSegmentWriter) finalize() error {
+	if w.cur == nil {
+		return nil
+	}
+	syncErr := w.cur.Sync()
+	closeErr := w.cur.Close()
+	w.files = append(w.files, w.curFile)
+	w.cur = nil
+	w.curFile = recordedFile{}
+	if syncErr != nil {
+		return syncErr
+	}
+	return closeErr
+}
+
+// Close finalizes the file currently being written, if any.
+func (w *rollingSegmentWriter) Close() error {
+	return w.finalize()
+}
+
+// Files lists the finalized files in the order they were written.
+func (w *rollingSegmentWriter) Files() []recordedFile {
+	return w.files
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRollingSegmentWriter_RotatesOnMediaTime(t *testing.T) {
+	dir := t.TempDir()
+	w := newRollingSegmentWriter(dir, "CAM-1", 10*time.Second)
+	clock := time.Date(2026, 2, 15, 14, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+	w.SetInit([]byte("INIT"))
+
+	for i := 0; i < 5; i++ {
+		if err := w.Write(hlsSegment{Duration: 4}, []byte{byte('a' + i)}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	files := w.Files()
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %+v", files)
+	}
+	if files[0].Segments != 3 || files[1].Segments != 2 {
+		t.Fatalf("unexpected segment split: %+v", files)
+	}
+	b, err := os.ReadFile(files[0].Path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(b) != "INITabc" {
+		t.Fatalf("first file = %q", b)
+	}
+	b, _ = os.ReadFile(files[1].Path)
+	if string(b) != "INITde" {
+		t.Fatalf("second file = %q", b)
+	}
+}
+
+func TestLiveRecorder_FetchesNewSegmentsAndRefreshesJWT(t *testing.T) {
+	polls := 0
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/footage/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		fmt.Fprintf(w, `{"jwt":"jwt-%d","expiration":1800}`, tokens)
+	})
+	mux.HandleFunc("/stream/cameras/v1/footage/stream/stream.m3u8", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		// The first token is rejected once to exercise the refresh path.
+		if r.URL.Query().Get("jwt") == "jwt-1" {
+			w.WriteHeader(401)
+			return
+		}
+		switch polls {
+		case 2:
+			fmt.Fprint(w, "#EXTM3U\n#EXT-X-TARGETDURATION:1\n#EXT-X-MEDIA-SEQUENCE:1\n#EXTINF:1,\ns1.ts\n#EXTINF:1,\ns2.ts\n")
+		default:
+			fmt.Fprint(w, "#EXTM3U\n#EXT-X-TARGETDURATION:1\n#EXT-X-MEDIA-SEQUENCE:2\n#EXTINF:1,\ns2.ts\n#EXTINF:1,\ns3.ts\n#EXT-X-ENDLIST\n")
+		}
+	})
+	mux.HandleFunc("/stream/cameras/v1/footage/stream/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("camera_id") != "CAM" {
+			w.WriteHeader(400)
+			return
+		}
+		_, _ = io.WriteString(w, r.URL.Path[len(r.URL.Path)-6:len(r.URL.Path)-3])
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfg := Config{BaseURL: srv.URL, OrgID: "ORG", Headers: map[string]string{}}
+	rf := &rootFlags{}
+	w := newRollingSegmentWriter(t.TempDir(), "CAM", time.Hour)
+	rec := &liveRecorder{
+		client:     srv.Client(),
+		cfg:        cfg,
+		rf:         rf,
+		jwt:        newStreamingJWTSource(srv.Client(), cfg, rf),
+		cameraID:   "CAM",
+		resolution: "low_res",
+		codec:      "h264",
+		w:          w,
+		log:        io.Discard,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if err := rec.Run(ctx); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	files := w.Files()
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %+v", files)
+	}
+	b, _ := os.ReadFile(files[0].Path)
+	if !bytes.Equal(b, []byte("/s1/s2/s3")) {
+		t.Fatalf("recorded bytes = %q", b)
+	}
+	if tokens != 2 {
+		t.Fatalf("expected a jwt refresh, got %d token calls", tokens)
+	}
+}
@@ -0,0 +1,316 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// hlsPlaylist is the subset of an HLS playlist the footage commands need. Master playlists
+// only populate Variants; media playlists populate the segment fields.
+type hlsPlaylist struct {
+	TargetDuration float64
+	MediaSequence  int64
+	EndList        bool
+	MapURI         string
+	Variants       []string
+	Segments       []hlsSegment
+}
+
+type hlsSegment struct {
+	URI             string
+	Duration        float64
+	Sequence        int64
+	ProgramDateTime time.Time // zero when the playlist has no EXT-X-PROGRAM-DATE-TIME
+	Discontinuity   bool
+}
+
+// End returns the wall-clock end of the segment, or the zero time when it has no date.
+func (s hlsSegment) End() time.Time {
+	if s.ProgramDateTime.IsZero() {
+		return time.Time{}
+	}
+	return s.ProgramDateTime.Add(time.Duration(s.Duration * float64(time.Second)))
+}
+
+// parseM3U8 parses a master or media playlist. URIs are resolved against base.
+//
+// EXT-X-PROGRAM-DATE-TIME applies to the next segment; later segments without their own tag
+// are dated by accumulating EXTINF durations, as the HLS spec describes.
+func parseM3U8(b []byte, base *url.URL) (hlsPlaylist, error) {
+	var pl hlsPlaylist
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	resolve := func(raw string) (string, error) {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return "", err
+		}
+		if base != nil && !u.IsAbs() {
+			u = base.ResolveReference(u)
+		}
+		return u.String(), nil
+	}
+
+	sawHeader := false
+	var pending hlsSegment
+	var pendingDur bool
+	var nextPDT time.Time
+	var streamInf bool
+	seq := int64(-1)
+	line := 0
+	for sc.Scan() {
+		line++
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		if !sawHeader {
+			if s != "#EXTM3U" {
+				return pl, errors.New("not an m3u8 playlist (missing #EXTM3U)")
+			}
+			sawHeader = true
+			continue
+		}
+		if strings.HasPrefix(s, "#") {
+			tag, val, _ := strings.Cut(s, ":")
+			switch tag {
+			case "#EXT-X-TARGETDURATION":
+				pl.TargetDuration, _ = strconv.ParseFloat(val, 64)
+			case "#EXT-X-MEDIA-SEQUENCE":
+				n, err := strconv.ParseInt(val, 10, 64)
+				if err != nil {
+					return pl, fmt.Errorf("invalid EXT-X-MEDIA-SEQUENCE on line %d", line)
+				}
+				pl.MediaSequence = n
+			case "#EXT-X-ENDLIST":
+				pl.EndList = true
+			case "#EXT-X-MAP":
+				if uri := m3u8Attr(val, "URI"); uri != "" {
+					r, err := resolve(uri)
+					if err != nil {
+						return pl, fmt.Errorf("invalid EXT-X-MAP uri on line %d: %w", line, err)
+					}
+					pl.MapURI = r
+				}
+			case "#EXT-X-STREAM-INF":
+				streamInf = true
+			case "#EXTINF":
+				d, _, _ := strings.Cut(val, ",")
+				f, err := strconv.ParseFloat(strings.TrimSpace(d), 64)
+				if err != nil {
+					return pl, fmt.Errorf("invalid EXTINF duration on line %d", line)
+				}
+				pending.Duration = f
+				pendingDur = true
+			case "#EXT-X-DISCONTINUITY":
+				pending.Discontinuity = true
+			case "#EXT-X-PROGRAM-DATE-TIME":
+				t, err := parseHLSDateTime(val)
+				if err != nil {
+					return pl, fmt.Errorf("invalid EXT-X-PROGRAM-DATE-TIME on line %d: %w", line, err)
+				}
+				nextPDT = t
+			}
+			continue
+		}
+
+		uri, err := resolve(s)
+		if err != nil {
+			return pl, fmt.Errorf("invalid m3u8 uri on line %d: %w", line, err)
+		}
+		if streamInf {
+			pl.Variants = append(pl.Variants, uri)
+			streamInf = false
+			continue
+		}
+		if !pendingDur {
+			// Tolerate segments without EXTINF; treat them as zero-length.
+			pending.Duration = 0
+		}
+		if seq < 0 {
+			seq = pl.MediaSequence
+		}
+		pending.URI = uri
+		pending.Sequence = seq
+		switch {
+		case !nextPDT.IsZero():
+			pending.ProgramDateTime = nextPDT
+		case len(pl.Segments) > 0 && !pending.Discontinuity:
+			pending.ProgramDateTime = pl.Segments[len(pl.Segments)-1].End()
+		}
+		pl.Segments = append(pl.Segments, pending)
+		seq++
+		pending = hlsSegment{}
+		pendingDur = false
+		nextPDT = time.Time{}
+	}
+	if err := sc.Err(); err != nil {
+		return pl, err
+	}
+	if !sawHeader {
+		return pl, errors.New("not an m3u8 playlist (missing #EXTM3U)")
+	}
+	return pl, nil
+}
+
+func parseHLSDateTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", s)
+}
+
+// m3u8Attr extracts an attribute value from a tag attribute list (KEY=VALUE,KEY="VALUE").
+func m3u8Attr(list, key string) string {
+	for len(list) > 0 {
+		var k string
+		k, list, _ = strings.Cut(list, "=")
+		var v string
+		if strings.HasPrefix(list, `"`) {
+			end := strings.Index(list[1:], `"`)
+			if end == -1 {
+				return ""
+			}
+			v = list[1 : end+1]
+			list = strings.TrimPrefix(list[end+2:], ",")
+		} else {
+			v, list, _ = strings.Cut(list, ",")
+		}
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// signHLSURL adds the stream's required query params (org_id, camera_id, ...) to a segment URI
+// and always replaces jwt so refreshed tokens take effect on already-parsed playlists.
+func signHLSURL(raw string, required url.Values) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for k, vals := range required {
+		if k != "jwt" && q.Has(k) {
+			continue
+		}
+		q.Del(k)
+		for _, v := range vals {
+			q.Add(k, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// redactURLSecrets replaces credentials carried in query parameters (the streaming jwt, tokens,
+// API keys) with REDACTED, for URLs that end up in logs or manifests. A URL that does not parse
+// is replaced as a whole.
+func redactURLSecrets(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "REDACTED"
+	}
+	q := u.Query()
+	changed := false
+	for k := range q {
+		switch strings.ToLower(k) {
+		case "jwt", "token", "api_key", "apikey", "x-api-key", "x-verkada-auth", "signature":
+			q.Set(k, "REDACTED")
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// errHLSUnauthorized signals that the stream rejected the jwt and the caller should refresh it.
+var errHLSUnauthorized = errors.New("stream request unauthorized (jwt expired or lacks permission)")
+
+// fetchHLSResource GETs a playlist or segment. Unlike fetchText it returns the raw body for
+// binary segments and maps 401/403 to errHLSUnauthorized so callers can refresh the jwt.
+func fetchHLSResource(ctx context.Context, client *http.Client, reqURL string, cfg Config, rf *rootFlags) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	applyDefaultHeaders(req, cfg)
+	if err := applyHeaderFlags(req, rf.Headers); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if rf.Debug {
+		fmt.Fprintf(os.Stderr, "HTTP %s %s -> %d (%s)\n", req.Method, redactURLSecrets(req.URL.String()), resp.StatusCode, time.Since(start))
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errHLSUnauthorized
+	}
+	if resp.StatusCode >= 400 {
+		if msg, ok := apiErrorMessage(bytes.TrimSpace(b)); ok {
+			return nil, fmt.Errorf("stream request failed with status %d: %s", resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("stream request failed with status %d", resp.StatusCode)
+	}
+	if looksLikeHTML(resp.Header.Get("Content-Type"), b) {
+		return nil, errors.New("received HTML instead of HLS content (check org_id/camera_id and base URL)")
+	}
+	return b, nil
+}
+
+// fetchMediaPlaylist fetches a playlist and, when it is a master playlist, follows the first
+// variant. It returns the media playlist and the URL it was loaded from.
+func fetchMediaPlaylist(ctx context.Context, client *http.Client, playlistURL string, cfg Config, rf *rootFlags) (hlsPlaylist, *url.URL, error) {
+	u, err := url.Parse(playlistURL)
+	if err != nil {
+		return hlsPlaylist{}, nil, err
+	}
+	required := u.Query()
+	for depth := 0; depth < 3; depth++ {
+		b, err := fetchHLSResource(ctx, client, u.String(), cfg, rf)
+		if err != nil {
+			return hlsPlaylist{}, nil, err
+		}
+		pl, err := parseM3U8(b, u)
+		if err != nil {
+			return hlsPlaylist{}, nil, err
+		}
+		if len(pl.Variants) == 0 {
+			return pl, u, nil
+		}
+		next, err := signHLSURL(pl.Variants[0], required)
+		if err != nil {
+			return hlsPlaylist{}, nil, err
+		}
+		if u, err = url.Parse(next); err != nil {
+			return hlsPlaylist{}, nil, err
+		}
+	}
+	return hlsPlaylist{}, nil, errors.New("too many nested HLS playlists")
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseM3U8_MediaPlaylistWithProgramDateTime(t *testing.T) {
+	base, _ := url.Parse("https://api.verkada.com/stream/cameras/v1/footage/stream/stream.m3u8?jwt=J")
+	in := strings.Join([]string{
+		"#EXTM3U",
+		"#EXT-X-TARGETDURATION:4",
+		"#EXT-X-MEDIA-SEQUENCE:7",
+		`#EXT-X-MAP:URI="init.mp4"`,
+		"#EXT-X-PROGRAM-DATE-TIME:2026-02-15T14:00:00.000Z",
+		"#EXTINF:4.0,",
+		"seg7.m4s",
+		"#EXTINF:2.5,",
+		"seg8.m4s",
+		"#EXT-X-DISCONTINUITY",
+		"#EXT-X-PROGRAM-DATE-TIME:2026-02-15T14:05:00Z",
+		"#EXTINF:4,",
+		"seg9.m4s",
+		"#EXT-X-ENDLIST",
+	}, "\n")
+
+	pl, err := parseM3U8([]byte(in), base)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if pl.TargetDuration != 4 || !pl.EndList || pl.MediaSequence != 7 {
+		t.Fatalf("unexpected header fields: %+v", pl)
+	}
+	if pl.MapURI != "https://api.verkada.com/stream/cameras/v1/footage/stream/init.mp4" {
+		t.Fatalf("map uri = %s", pl.MapURI)
+	}
+	if len(pl.Segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(pl.Segments))
+	}
+	s1 := pl.Segments[1]
+	if s1.Sequence != 8 || s1.Duration != 2.5 {
+		t.Fatalf("segment 1 = %+v", s1)
+	}
+	want := time.Date(2026, 2, 15, 14, 0, 4, 0, time.UTC)
+	if !s1.ProgramDateTime.Equal(want) {
+		t.Fatalf("segment 1 pdt = %s, want %s", s1.ProgramDateTime, want)
+	}
+	s2 := pl.Segments[2]
+	if !s2.Discontinuity || !s2.ProgramDateTime.Equal(time.Date(2026, 2, 15, 14, 5, 0, 0, time.UTC)) {
+		t.Fatalf("segment 2 = %+v", s2)
+	}
+}
+
+func TestParseM3U8_MasterPlaylist(t *testing.T) {
+	base, _ := url.Parse("https://example.com/a/master.m3u8")
+	pl, err := parseM3U8([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1000\nlow/index.m3u8\n"), base)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(pl.Variants) != 1 || pl.Variants[0] != "https://example.com/a/low/index.m3u8" || len(pl.Segments) != 0 {
+		t.Fatalf("unexpected playlist: %+v", pl)
+	}
+}
+
+func TestParseM3U8_RejectsNonPlaylist(t *testing.T) {
+	if _, err := parseM3U8([]byte(`{"message":"nope"}`), nil); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestSignHLSURL_ReplacesJWT(t *testing.T) {
+	required := url.Values{"jwt": {"new"}, "camera_id": {"CAM"}}
+	got, err := signHLSURL("https://example.com/seg.ts?jwt=old&camera_id=OTHER", required)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(got, "jwt=new") || strings.Contains(got, "jwt=old") {
+		t.Fatalf("jwt not replaced: %s", got)
+	}
+	if !strings.Contains(got, "camera_id=OTHER") {
+		t.Fatalf("existing params should be preserved: %s", got)
+	}
+}
+
+func TestRedactURLSecrets(t *testing.T) {
+	got := redactURLSecrets("https://api.verkada.com/stream/cameras/v1/footage/stream/stream.m3u8?camera_id=CAM&jwt=eyJsecret&org_id=ORG")
+	if strings.Contains(got, "eyJsecret") || !strings.Contains(got, "jwt=REDACTED") || !strings.Contains(got, "camera_id=CAM") {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+	if plain := "https://api.verkada.com/cameras/v1/devices"; redactURLSecrets(plain) != plain {
+		t.Fatalf("URL without secrets should be unchanged")
+	}
+	// A URL that does not parse could still carry the jwt; nothing of it is kept.
+	if got := redactURLSecrets("https://api.verkada.com/%zz?jwt=eyJsecret"); got != "REDACTED" {
+		t.Fatalf("unparsable URL: %s", got)
+	}
+}
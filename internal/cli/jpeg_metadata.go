@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	xmpAPP1Header  = "http://ns.adobe.com/xap/1.0/\x00"
+	exifAPP1Header = "Exif\x00\x00"
+	// verkcliXMPNamespace holds the camera fields that have no standard XMP property.
+	verkcliXMPNamespace = "https://github.com/ChrisVo/verkcli/ns/thumbnail/1.0/"
+	xmpBasicNamespace   = "http://ns.adobe.com/xap/1.0/"
+	rdfNamespace        = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	jpegMaxSegmentData  = 65533
+)
+
+// thumbnailMetadata is the provenance written into a thumbnail by --embed-metadata.
+type thumbnailMetadata struct {
+	CameraID    string
+	Name        string
+	Site        string
+	Label       string
+	RequestedAt time.Time
+	// ServerTime is the Last-Modified time of the thumbnail response, when the API sent one.
+	ServerTime time.Time
+	Resolution string
+	Tool       string
+}
+
+// embedJPEGMetadata returns jpegData with an XMP packet and, unless the image already carries
+// EXIF, an EXIF segment (DateTimeOriginal, ImageDescription, Software). Existing XMP packets are
+// replaced; everything else is kept as is.
+func embedJPEGMetadata(jpegData []byte, m thumbnailMetadata) ([]byte, error) {
+	if len(jpegData) < 4 || jpegData[0] != 0xFF || jpegData[1] != 0xD8 {
+		return nil, errors.New("not a JPEG image")
+	}
+	xmp, err := jpegAPP1Segment(xmpAPP1Header, buildThumbnailXMP(m))
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the leading APPn segments: a JFIF APP0 stays first, old XMP packets are dropped.
+	var jfif, rest []byte
+	hasExif := false
+	pos := 2
+	for pos+4 <= len(jpegData) && jpegData[pos] == 0xFF && jpegData[pos+1] >= 0xE0 && jpegData[pos+1] <= 0xEF {
+		marker := jpegData[pos+1]
+		n := int(binary.BigEndian.Uint16(jpegData[pos+2:]))
+		end := pos + 2 + n
+		if n < 2 || end > len(jpegData) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+		seg, payload := jpegData[pos:end], jpegData[pos+4:end]
+		switch {
+		case marker == 0xE0 && pos == 2:
+			jfif = seg
+		case marker == 0xE1 && bytes.HasPrefix(payload, []byte(xmpAPP1Header)):
+		case marker == 0xE1 && bytes.HasPrefix(payload, []byte(exifAPP1Header)):
+			hasExif = true
+			rest = append(rest, seg...)
+		default:
+			rest = append(rest, seg...)
+		}
+		pos = end
+	}
+
+	var out bytes.Buffer
+	out.Write(jpegData[:2])
+	out.Write(jfif)
+	if !hasExif {
+		exif, err := jpegAPP1Segment(exifAPP1Header, buildThumbnailEXIF(m))
+		if err != nil {
+			return nil, err
+		}
+		out.Write(exif)
+	}
+	out.Write(xmp)
+	out.Write(rest)
+	out.Write(jpegData[pos:])
+	return out.Bytes(), nil
+}
+
+func jpegAPP1Segment(header string, data []byte) ([]byte, error) {
+	n := len(header) + len(data)
+	if n > jpegMaxSegmentData {
+		return nil, fmt.Errorf("metadata segment too large (%d bytes)", n)
+	}
+	seg := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(n+2))
+	seg = append(seg, header...)
+	return append(seg, data...), nil
+}
+
+func buildThumbnailXMP(m thumbnailMetadata) []byte {
+	var b bytes.Buffer
+	attr := func(name, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString("\n   " + name + `="`)
+		_ = xml.EscapeText(&b, []byte(value))
+		b.WriteString(`"`)
+	}
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	b.WriteString(` <rdf:RDF xmlns:rdf="` + rdfNamespace + `">` + "\n")
+	b.WriteString(`  <rdf:Description rdf:about=""`)
+	attr("xmlns:xmp", xmpBasicNamespace)
+	attr("xmlns:verkcli", verkcliXMPNamespace)
+	attr("xmp:CreatorTool", m.Tool)
+	if !m.RequestedAt.IsZero() {
+		attr("xmp:CreateDate", m.RequestedAt.Format(time.RFC3339))
+		attr("verkcli:RequestedAt", m.RequestedAt.Format(time.RFC3339))
+	}
+	if !m.ServerTime.IsZero() {
+		attr("verkcli:ServerTime", m.ServerTime.Format(time.RFC3339))
+	}
+	attr("verkcli:CameraID", m.CameraID)
+	attr("verkcli:CameraName", m.Name)
+	attr("verkcli:Site", m.Site)
+	attr("verkcli:Label", m.Label)
+	attr("verkcli:Resolution", m.Resolution)
+	b.WriteString("/>\n </rdf:RDF>\n</x:xmpmeta>\n<?xpacket end=\"w\"?>")
+	return b.Bytes()
+}
+
+// readThumbnailXMP extracts the metadata written by embedJPEGMetadata. ok is false when the
+// image has no XMP packet.
+func readThumbnailXMP(jpegData []byte) (m thumbnailMetadata, ok bool, err error) {
+	var packet []byte
+	for pos := 2; pos+4 <= len(jpegData) && jpegData[pos] == 0xFF; {
+		marker := jpegData[pos+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(jpegData[pos+2:]))
+		if end > len(jpegData) {
+			return m, false, errors.New("truncated JPEG segment")
+		}
+		if payload := jpegData[pos+4 : end]; marker == 0xE1 && bytes.HasPrefix(payload, []byte(xmpAPP1Header)) {
+			packet = payload[len(xmpAPP1Header):]
+			break
+		}
+		pos = end
+	}
+	if packet == nil {
+		return m, false, nil
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(packet))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return m, false, fmt.Errorf("parse XMP: %w", err)
+		}
+		se, isStart := tok.(xml.StartElement)
+		if !isStart || se.Name.Space != rdfNamespace || se.Name.Local != "Description" {
+			continue
+		}
+		parseTime := func(v string) time.Time {
+			t, _ := time.Parse(time.RFC3339, v)
+			return t
+		}
+		for _, a := range se.Attr {
+			switch a.Name.Space + a.Name.Local {
+			case xmpBasicNamespace + "CreatorTool":
+				m.Tool = a.Value
+			case verkcliXMPNamespace + "RequestedAt":
+				m.RequestedAt = parseTime(a.Value)
+			case verkcliXMPNamespace + "ServerTime":
+				m.ServerTime = parseTime(a.Value)
+			case verkcliXMPNamespace + "CameraID":
+				m.CameraID = a.Value
+			case verkcliXMPNamespace + "CameraName":
+				m.Name = a.Value
+			case verkcliXMPNamespace + "Site":
+				m.Site = a.Value
+			case verkcliXMPNamespace + "Label":
+				m.Label = a.Value
+			case verkcliXMPNamespace + "Resolution":
+				m.Resolution = a.Value
+			}
+		}
+		return m, true, nil
+	}
+}
+
+type exifEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func exifASCII(tag uint16, s string) exifEntry {
+	data := append([]byte(asciiOnly(s)), 0)
+	return exifEntry{tag: tag, typ: 2, count: uint32(len(data)), data: data}
+}
+
+// buildThumbnailEXIF returns a big-endian TIFF block with IFD0 (ImageDescription, Software,
+// DateTime) and an Exif IFD (DateTimeOriginal, OffsetTimeOriginal). EXIF has no field for the
+// camera details beyond the description, so the full set lives in the XMP packet.
+func buildThumbnailEXIF(m thumbnailMetadata) []byte {
+	t := m.RequestedAt.UTC()
+	stamp := t.Format("2006:01:02 15:04:05")
+	desc := strings.Join(nonEmptyStrings(m.Label, m.Name, m.Site, "camera "+m.CameraID), " - ")
+
+	ifd0 := []exifEntry{
+		exifASCII(0x010E, desc),
+		exifASCII(0x0131, m.Tool),
+		exifASCII(0x0132, stamp),
+		{tag: 0x8769, typ: 4, count: 1, data: make([]byte, 4)},
+	}
+	exifIFD := []exifEntry{
+		exifASCII(0x9003, stamp),
+		exifASCII(0x9011, "+00:00"),
+	}
+
+	const ifd0Offset = 8
+	ifd0Bytes := encodeEXIFIFD(ifd0, ifd0Offset)
+	binary.BigEndian.PutUint32(ifd0[3].data, uint32(ifd0Offset+len(ifd0Bytes)))
+	ifd0Bytes = encodeEXIFIFD(ifd0, ifd0Offset)
+
+	out := []byte{'M', 'M', 0, 42, 0, 0, 0, ifd0Offset}
+	out = append(out, ifd0Bytes...)
+	return append(out, encodeEXIFIFD(exifIFD, uint32(len(out)))...)
+}
+
+// encodeEXIFIFD encodes one IFD at offset start (from the TIFF header), followed by the values
+// that do not fit in an entry.
+func encodeEXIFIFD(entries []exifEntry, start uint32) []byte {
+	head := make([]byte, 2, 2+12*len(entries)+4)
+	binary.BigEndian.PutUint16(head, uint16(len(entries)))
+	dataOff := start + uint32(cap(head))
+	var extra []byte
+	for _, e := range entries {
+		var ent [12]byte
+		binary.BigEndian.PutUint16(ent[0:], e.tag)
+		binary.BigEndian.PutUint16(ent[2:], e.typ)
+		binary.BigEndian.PutUint32(ent[4:], e.count)
+		if len(e.data) <= 4 {
+			copy(ent[8:], e.data)
+		} else {
+			binary.BigEndian.PutUint32(ent[8:], dataOff+uint32(len(extra)))
+			extra = append(extra, e.data...)
+			if len(extra)%2 == 1 {
+				extra = append(extra, 0)
+			}
+		}
+		head = append(head, ent[:]...)
+	}
+	head = append(head, 0, 0, 0, 0) // no next IFD
+	return append(head, extra...)
+}
+
+// thumbnailMetadataFor gathers the --embed-metadata fields. Camera details come from the index or
+// the API; when they cannot be loaded the camera_id and local label are still recorded.
+func thumbnailMetadataFor(client *http.Client, cfg *Config, rf *rootFlags, cameraID string, ts int64, resolution string, hdr http.Header, warn io.Writer) thumbnailMetadata {
+	info := cameraInfo{CameraID: cameraID}
+	if c, err := lookupCamera(client, cfg, rf, cameraID); err == nil {
+		info = newCameraInfo(c, cfg.Labels)
+	} else {
+		fmt.Fprintf(warn, "warning: camera details unavailable for metadata: %v\n", err)
+		if cfg.Labels != nil {
+			info.Label = cfg.Labels.Cameras[cameraID]
+		}
+	}
+	m := thumbnailMetadata{
+		CameraID:    cameraID,
+		Name:        info.Name,
+		Site:        info.Site,
+		Label:       info.Label,
+		RequestedAt: time.Unix(ts, 0).UTC(),
+		Resolution:  resolution,
+		Tool:        "verkcli " + version,
+	}
+	if lm := hdr.Get("Last-Modified"); lm != "" {
+		if t, err := http.ParseTime(lm); err == nil {
+			m.ServerTime = t.UTC()
+		}
+	}
+	return m
+}
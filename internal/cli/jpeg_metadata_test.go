@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+func TestEmbedJPEGMetadata_RoundTrip(t *testing.T) {
+	src := testJPEG(t, 16, 16, color.RGBA{0, 128, 255, 255})
+	// Put a JFIF APP0 in front, as camera JPEGs usually have one.
+	app0 := []byte{0xFF, 0xE0, 0, 16, 'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0}
+	src = append(append(append([]byte{}, src[:2]...), app0...), src[2:]...)
+
+	want := thumbnailMetadata{
+		CameraID:    "CAM-1",
+		Name:        `Dock "North" & <Gate>`,
+		Site:        "HQ",
+		Label:       "Loading dock",
+		RequestedAt: time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC),
+		ServerTime:  time.Date(2026, 2, 15, 14, 27, 12, 0, time.UTC),
+		Resolution:  "hi-res",
+		Tool:        "verkcli 1.2.3",
+	}
+	out, err := embedJPEGMetadata(src, want)
+	if err != nil {
+		t.Fatalf("embed: %v", err)
+	}
+	if !bytes.Equal(out[2:2+len(app0)], app0) {
+		t.Fatalf("JFIF APP0 should stay the first segment")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatalf("embedded JPEG no longer decodes: %v", err)
+	}
+	got, ok, err := readThumbnailXMP(out)
+	if err != nil || !ok {
+		t.Fatalf("read XMP: ok=%v err=%v", ok, err)
+	}
+	if got != want {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+	}
+	if !bytes.Contains(out, []byte("2026:02:15 14:30:00\x00")) || !bytes.Contains(out, []byte("Loading dock - Dock \"North\" & <Gate> - HQ - camera CAM-1\x00")) {
+		t.Fatalf("EXIF DateTimeOriginal/ImageDescription missing")
+	}
+
+	// Embedding again replaces the XMP packet and keeps the existing EXIF segment.
+	want.Resolution = "low-res"
+	again, err := embedJPEGMetadata(out, want)
+	if err != nil {
+		t.Fatalf("re-embed: %v", err)
+	}
+	if n := bytes.Count(again, []byte(xmpAPP1Header)); n != 1 {
+		t.Fatalf("expected one XMP packet, got %d", n)
+	}
+	if n := bytes.Count(again, []byte(exifAPP1Header)); n != 1 {
+		t.Fatalf("expected one EXIF segment, got %d", n)
+	}
+	if got, _, _ := readThumbnailXMP(again); got.Resolution != "low-res" {
+		t.Fatalf("re-embedded resolution: %q", got.Resolution)
+	}
+}
+
+func TestBuildThumbnailEXIF_IFDLayout(t *testing.T) {
+	tiff := buildThumbnailEXIF(thumbnailMetadata{CameraID: "CAM-1", RequestedAt: time.Unix(1771165800, 0), Tool: "verkcli dev"})
+	be := binary.BigEndian
+	if string(tiff[:4]) != "MM\x00*" || be.Uint32(tiff[4:]) != 8 {
+		t.Fatalf("bad TIFF header: % x", tiff[:8])
+	}
+	entry := func(ifd uint32, tag uint16) (typ uint16, count, value uint32) {
+		n := be.Uint16(tiff[ifd:])
+		for i := uint32(0); i < uint32(n); i++ {
+			e := tiff[ifd+2+12*i:]
+			if be.Uint16(e) == tag {
+				return be.Uint16(e[2:]), be.Uint32(e[4:]), be.Uint32(e[8:])
+			}
+		}
+		t.Fatalf("tag %#x not found in IFD at %d", tag, ifd)
+		return
+	}
+	_, _, exifIFD := entry(8, 0x8769)
+	typ, count, off := entry(exifIFD, 0x9003)
+	if typ != 2 || count != 20 || string(tiff[off:off+19]) != "2026:02:15 14:30:00" {
+		t.Fatalf("DateTimeOriginal: type %d count %d value %q", typ, count, tiff[off:off+count])
+	}
+	_, count, off = entry(8, 0x0131)
+	if string(tiff[off:off+count-1]) != "verkcli dev" {
+		t.Fatalf("Software: %q", tiff[off:off+count])
+	}
+}
+
+func TestEmbedJPEGMetadata_RejectsNonJPEG(t *testing.T) {
+	if _, err := embedJPEGMetadata([]byte(`{"message":"nope"}`), thumbnailMetadata{}); err == nil {
+		t.Fatal("expected an error for non-JPEG input")
+	}
+}
@@ -0,0 +1,478 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpLabelStoreEmpty is the httpLabelStore version of a store that does not exist yet (GET
+// returned 404). Save then only creates it: If-None-Match: * fails if a teammate pushed first.
+const httpLabelStoreEmpty = "*"
+
+// labelStoreTokenEnv, when set, is sent as a bearer token to HTTP label stores.
+const labelStoreTokenEnv = "VERKCLI_LABEL_STORE_TOKEN"
+
+// errLabelStoreChanged is returned by labelStore.Save when the store changed after Load.
+var errLabelStoreChanged = errors.New("label store changed since it was read; pull again")
+
+// labelStore is a shared place camera labels are synced with by `cameras label pull/push`. The
+// config profile itself is the local side of every sync.
+type labelStore interface {
+	// Load returns the stored labels and an opaque version for Save.
+	Load() (labels map[string]string, version string, err error)
+	// Save replaces the stored labels, failing with errLabelStoreChanged if the store is no
+	// longer at version.
+	Save(labels map[string]string, version string) error
+}
+
+// parseLabelStore turns a label_store spec into a store: "profile:NAME" for the labels of another
+// profile in the config file at configPath, an http(s) URL, or a file path (optionally prefixed
+// with "file:") ending in .yaml, .yml, .json or .csv.
+func parseLabelStore(spec, configPath string, client *http.Client) (labelStore, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return nil, errors.New("no label store (set one with: verkcli profiles set label_store PATH|URL, or pass --store)")
+	case strings.HasPrefix(spec, "profile:"):
+		name := strings.TrimSpace(strings.TrimPrefix(spec, "profile:"))
+		if name == "" {
+			return nil, fmt.Errorf("label store %q names no profile", spec)
+		}
+		return &configLabelStore{path: configPath, profile: name}, nil
+	case strings.HasPrefix(spec, "https://") || strings.HasPrefix(spec, "http://"):
+		return &httpLabelStore{url: spec, client: client}, nil
+	}
+	path := strings.TrimPrefix(spec, "file:")
+	if labelFormatFor(path) == "" {
+		return nil, fmt.Errorf("unsupported label store %q (expected an http(s) URL or a .yaml, .yml, .json or .csv file)", spec)
+	}
+	return &fileLabelStore{path: path}, nil
+}
+
+// configLabelStore is the labels of a profile in the config file, the storage labels have always
+// used. As a store it lets profiles share labels, e.g. "eu-ops" pulling from "eu".
+type configLabelStore struct {
+	path    string
+	profile string
+}
+
+func (s *configLabelStore) Load() (map[string]string, string, error) {
+	cf, err := loadConfig(s.path)
+	if err != nil {
+		return nil, "", err
+	}
+	prof, ok := cf.Profiles[s.profile]
+	if !ok {
+		return nil, "", fmt.Errorf("label store: profile %q not found in %s", s.profile, s.path)
+	}
+	labels := map[string]string{}
+	for id, label := range profileLabels(prof) {
+		labels[id] = label
+	}
+	return labels, labelsVersion(labels), nil
+}
+
+func (s *configLabelStore) Save(labels map[string]string, version string) error {
+	return updateConfig(s.path, func(cf *ConfigFile) error {
+		prof, ok := cf.Profiles[s.profile]
+		if !ok {
+			return fmt.Errorf("label store: profile %q not found in %s", s.profile, s.path)
+		}
+		if labelsVersion(profileLabels(prof)) != version {
+			return errLabelStoreChanged
+		}
+		setProfileCameraLabels(&prof, labels)
+		cf.Profiles[s.profile] = prof
+		return nil
+	})
+}
+
+func labelsVersion(labels map[string]string) string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	b, _ := json.Marshal(labels) // map keys are sorted
+	return fileVersion(b)
+}
+
+// fileLabelStore keeps labels in a file meant to be committed to git. YAML and JSON files hold
+// {"cameras": {camera_id: label}} like the config; CSV files have a camera_id,label header.
+type fileLabelStore struct {
+	path string
+}
+
+func (s *fileLabelStore) Load() (map[string]string, string, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	labels, err := decodeLabels(b, labelFormatFor(s.path), s.path)
+	if err != nil {
+		return nil, "", err
+	}
+	return labels, fileVersion(b), nil
+}
+
+func (s *fileLabelStore) Save(labels map[string]string, version string) error {
+	// Hold the lock across the version check and the write, or two pushes could both pass it.
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	cur, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		cur = nil
+	case err != nil:
+		return err
+	}
+	if v := fileVersion(cur); cur != nil && v != version || cur == nil && version != "" {
+		return errLabelStoreChanged
+	}
+	b, err := encodeLabels(labels, labelFormatFor(s.path))
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, b, 0o644)
+}
+
+// lock locks the store file. The lock file lives in the user cache directory, keyed by the
+// file's absolute path, so no stray .lock file shows up next to a label file kept in git.
+func (s *fileLabelStore) lock() (func(), error) {
+	path, err := filepath.Abs(resolveSymlinks(s.path))
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(path))
+	return lockFileAt(filepath.Join(cacheDir, "verkcli", "locks", hex.EncodeToString(sum[:16])+".lock"), path)
+}
+
+func fileVersion(b []byte) string {
+	if b == nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Label files are CSV with a camera_id,label header, or JSON/YAML holding {"cameras": {...}}.
+const labelFormatCSV = "csv"
+
+// labelFormatFor returns the label file format for path's extension, or "" if it has none.
+func labelFormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return labelFormatCSV
+	case ".yaml", ".yml":
+		return configFormatYAML
+	case ".json":
+		return configFormatJSON
+	default:
+		return ""
+	}
+}
+
+// decodeLabels parses a label file in format; name is only used in errors.
+func decodeLabels(b []byte, format, name string) (map[string]string, error) {
+	switch format {
+	case labelFormatCSV:
+		return decodeLabelCSV(bytes.NewReader(b))
+	case configFormatYAML, "yml":
+		var v any
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		if v == nil {
+			return map[string]string{}, nil
+		}
+		j, err := json.Marshal(stringMapKeys(v))
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		b = j
+	case configFormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported label format %q (expected csv, json or yaml)", format)
+	}
+
+	var doc LocalLabels
+	if len(bytes.TrimSpace(b)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse %s: %w (expected {\"cameras\": {camera_id: label}})", name, err)
+		}
+	}
+	if doc.Cameras == nil {
+		doc.Cameras = map[string]string{}
+	}
+	return doc.Cameras, nil
+}
+
+// encodeLabels renders labels in format with camera IDs sorted, so files diff well in git.
+func encodeLabels(labels map[string]string, format string) ([]byte, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	switch format {
+	case labelFormatCSV:
+		var buf bytes.Buffer
+		if err := encodeLabelCSV(&buf, labels); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case configFormatYAML, "yml":
+		// yaml.v3 sorts map keys.
+		return yaml.Marshal(map[string]map[string]string{"cameras": labels})
+	case configFormatJSON:
+		b, err := json.MarshalIndent(LocalLabels{Cameras: labels}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported label format %q (expected csv, json or yaml)", format)
+	}
+}
+
+func decodeLabelCSV(r io.Reader) (map[string]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse labels csv: %w", err)
+	}
+	out := map[string]string{}
+	for i, row := range rows {
+		if i == 0 && len(row) > 0 && strings.EqualFold(strings.TrimSpace(row[0]), "camera_id") {
+			continue
+		}
+		if len(row) < 2 {
+			return nil, fmt.Errorf("labels csv line %d: expected camera_id,label", i+1)
+		}
+		id, label := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+		if id == "" {
+			continue
+		}
+		out[id] = label
+	}
+	return out, nil
+}
+
+func encodeLabelCSV(w io.Writer, labels map[string]string) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"camera_id", "label"})
+	for _, id := range sortedKeys(labels) {
+		_ = cw.Write([]string{id, labels[id]})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// httpLabelStore talks to a small key/value endpoint: GET returns {"cameras": {...}} with an
+// ETag (or 404 while empty), PUT replaces it and honors If-Match and If-None-Match: * (412 when
+// the labels changed in between). Every PUT is conditional, so a server without ETags is refused
+// rather than overwritten blindly.
+type httpLabelStore struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpLabelStore) do(method string, body io.Reader, version string) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch version {
+	case "":
+	case httpLabelStoreEmpty:
+		req.Header.Set("If-None-Match", "*")
+	default:
+		req.Header.Set("If-Match", version)
+	}
+	if tok := os.Getenv(labelStoreTokenEnv); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	return s.client.Do(req)
+}
+
+func (s *httpLabelStore) Load() (map[string]string, string, error) {
+	resp, err := s.do(http.MethodGet, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	b, err := ioReadAllLimit(resp.Body, 16<<20)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string]string{}, httpLabelStoreEmpty, nil
+	}
+	if resp.StatusCode >= 400 {
+		return nil, "", fmt.Errorf("label store GET %s: status %d", s.url, resp.StatusCode)
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return nil, "", fmt.Errorf("label store GET %s: response has no ETag, so pushes could overwrite concurrent changes; the server must send one", s.url)
+	}
+	var doc LocalLabels
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, "", fmt.Errorf("label store GET %s: %w", s.url, err)
+	}
+	if doc.Cameras == nil {
+		doc.Cameras = map[string]string{}
+	}
+	return doc.Cameras, etag, nil
+}
+
+func (s *httpLabelStore) Save(labels map[string]string, version string) error {
+	if version == "" {
+		return errors.New("label store: no version to push against; pull first")
+	}
+	b, err := json.Marshal(LocalLabels{Cameras: labels})
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, bytes.NewReader(b), version)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusPreconditionFailed:
+		return errLabelStoreChanged
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("label store PUT %s: status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// labelChange is one camera whose label differs between the local profile and the store.
+// Empty Local or Remote means the camera has no label on that side.
+type labelChange struct {
+	CameraID string `json:"camera_id"`
+	Local    string `json:"local"`
+	Remote   string `json:"remote"`
+	// Side is who changed the label since the last sync: local, remote, or both (a conflict).
+	Side string `json:"side"`
+}
+
+// diffLabels compares local and remote labels against base, the labels both sides had after the
+// last sync (nil when there was none). Without a base every difference is a conflict, except a
+// label that only one side has, which counts as added there.
+func diffLabels(base, local, remote map[string]string) []labelChange {
+	ids := map[string]bool{}
+	for id := range local {
+		ids[id] = true
+	}
+	for id := range remote {
+		ids[id] = true
+	}
+	var out []labelChange
+	for id := range ids {
+		l, lok := local[id]
+		r, rok := remote[id]
+		if l == r && lok == rok {
+			continue
+		}
+		c := labelChange{CameraID: id, Local: l, Remote: r, Side: "both"}
+		b, bok := base[id]
+		switch {
+		case base != nil && lok == bok && l == b:
+			c.Side = "remote"
+		case base != nil && rok == bok && r == b:
+			c.Side = "local"
+		case base == nil && !rok:
+			c.Side = "local"
+		case base == nil && !lok:
+			c.Side = "remote"
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CameraID < out[j].CameraID })
+	return out
+}
+
+// labelSyncState is the last synced labels for a profile and store; it is the base of the
+// three-way comparison in diffLabels. It lives next to the cameras index and is only a cache:
+// losing it turns edits made on both sides into conflicts.
+type labelSyncState struct {
+	Store  string            `json:"store"`
+	Labels map[string]string `json:"labels"`
+}
+
+func labelSyncStatePath(rf *rootFlags, profile string, cfg Config) (string, error) {
+	dir, err := profileIndexDir(rf, profile, cfg)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "labels-sync.json"), nil
+}
+
+func loadLabelSyncBase(path, store string) map[string]string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var st labelSyncState
+	if json.Unmarshal(b, &st) != nil || st.Store != store {
+		return nil
+	}
+	if st.Labels == nil {
+		st.Labels = map[string]string{}
+	}
+	return st.Labels
+}
+
+func saveLabelSyncBase(path, store string, labels map[string]string) error {
+	b, err := json.MarshalIndent(labelSyncState{Store: store, Labels: labels}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(b, '\n'), 0o600)
+}
+
+// projectLabelStore resolves a relative file store in a project config against the directory
+// of that file, so the spec works from any subdirectory of the repository. URLs and profile:
+// stores are not paths and pass through.
+func projectLabelStore(spec, projectPath string) string {
+	if isHTTPLabelStore(spec) || strings.HasPrefix(spec, "profile:") {
+		return spec
+	}
+	path := strings.TrimPrefix(spec, "file:")
+	if filepath.IsAbs(path) {
+		return spec
+	}
+	return filepath.Join(filepath.Dir(projectPath), path)
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileLabelStoreFormats(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	labels := map[string]string{"CAM2": "Dock, east", "0042": "Lobby"}
+	for _, name := range []string{"labels.yaml", "labels.csv", "labels.json"} {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), name)
+			s, err := parseLabelStore("file:"+p, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, version, err := s.Load()
+			if err != nil || len(got) != 0 || version != "" {
+				t.Fatalf("missing file: %v %v %q", got, err, version)
+			}
+			if err := s.Save(labels, version); err != nil {
+				t.Fatalf("save: %v", err)
+			}
+			got, version, err = s.Load()
+			if err != nil || !reflect.DeepEqual(got, labels) {
+				t.Fatalf("load = %v, %v", got, err)
+			}
+			if err := os.WriteFile(p, append(mustReadFile(t, p), '\n'), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Save(labels, version); err != errLabelStoreChanged {
+				t.Fatalf("save over a changed file: %v", err)
+			}
+		})
+	}
+
+	p := filepath.Join(t.TempDir(), "labels.csv")
+	if err := os.WriteFile(p, []byte("camera_id,label\nCAM1, Front Door\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := (&fileLabelStore{path: p}).Load()
+	if err != nil || got["CAM1"] != "Front Door" {
+		t.Fatalf("hand-written csv = %v, %v", got, err)
+	}
+}
+
+func TestFileLabelStoreConcurrentSaves(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	p := filepath.Join(t.TempDir(), "labels.yaml")
+	s := &fileLabelStore{path: p}
+	if err := s.Save(map[string]string{"CAM1": "Front"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	_, version, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.Save(map[string]string{"CAM1": fmt.Sprintf("Front %d", i)}, version)
+		}()
+	}
+	wg.Wait()
+	saved := 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+			saved++
+		case errLabelStoreChanged:
+		default:
+			t.Fatalf("save: %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Fatalf("%d saves from the same version succeeded, want 1", saved)
+	}
+	// The store file is meant for git; its lock must not sit next to it.
+	entries, err := os.ReadDir(filepath.Dir(p))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("store directory holds %v (%v), want only labels.yaml", entries, err)
+	}
+}
+
+func TestProjectLabelStore(t *testing.T) {
+	proj := filepath.Join("/repo", ".verkcli.yaml")
+	for spec, want := range map[string]string{
+		"labels.yaml":                   filepath.Join("/repo", "labels.yaml"),
+		"file:ops/labels.csv":           filepath.Join("/repo", "ops", "labels.csv"),
+		"/etc/verkcli/labels.yaml":      "/etc/verkcli/labels.yaml",
+		"https://labels.example.com/eu": "https://labels.example.com/eu",
+		"profile:eu":                    "profile:eu",
+	} {
+		if got := projectLabelStore(spec, proj); got != want {
+			t.Errorf("projectLabelStore(%q) = %q, want %q", spec, got, want)
+		}
+	}
+}
+
+func TestDiffLabels(t *testing.T) {
+	base := map[string]string{"A": "a", "B": "b", "C": "c", "D": "d"}
+	local := map[string]string{"A": "a2", "B": "b", "C": "c3", "E": "e"}
+	remote := map[string]string{"A": "a", "B": "b2", "C": "c4", "D": "d"}
+	got := map[string]string{}
+	for _, c := range diffLabels(base, local, remote) {
+		got[c.CameraID] = c.Side
+	}
+	want := map[string]string{"A": "local", "B": "remote", "C": "both", "D": "local", "E": "local"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sides = %v, want %v", got, want)
+	}
+
+	got = map[string]string{}
+	for _, c := range diffLabels(nil, local, remote) {
+		got[c.CameraID] = c.Side
+	}
+	want = map[string]string{"A": "both", "B": "both", "C": "both", "D": "remote", "E": "local"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sides without base = %v, want %v", got, want)
+	}
+}
+
+func TestCamerasLabelPullPushFileStore(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	store := filepath.Join(t.TempDir(), "labels.yaml")
+	if err := os.WriteFile(store, []byte("cameras:\n  CAM1: Lobby\n  CAM2: Dock\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cfg := Config{
+		BaseURL:    "https://api.verkada.com",
+		OrgID:      "ORG1",
+		LabelStore: store,
+		Labels:     &LocalLabels{Cameras: map[string]string{"CAM1": "Front", "CAM3": "Yard"}},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": cfg}}); err != nil {
+		t.Fatal(err)
+	}
+	idxPath := mustBuildLabelTestIndex(t, Config{BaseURL: cfg.BaseURL, OrgID: cfg.OrgID}, []map[string]any{{"camera_id": "CAM2", "name": "Cam 2", "site": "HQ"}})
+
+	// First sync: CAM1 differs on both sides with no history, so it is a conflict.
+	out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "pull")
+	if err == nil || !strings.Contains(err.Error(), "1 label conflict") || !strings.Contains(out, "conflict  CAM1") {
+		t.Fatalf("pull: %v\n%s", err, out)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); got["CAM2"] != "" {
+		t.Fatalf("pull with conflicts wrote labels: %v", got)
+	}
+
+	out, err = runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "pull", "--force")
+	if err != nil {
+		t.Fatalf("pull --force: %v\n%s", err, out)
+	}
+	want := map[string]string{"CAM1": "Lobby", "CAM2": "Dock", "CAM3": "Yard"}
+	if got := mustLoadLabels(t, cfgPath, "prod"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("labels after pull = %v", got)
+	}
+	res, err := searchCamerasIndex(idxPath, "dock", 10)
+	if err != nil || len(res.Results) != 1 || res.Results[0].CameraID != "CAM2" {
+		t.Fatalf("index after pull = %+v, %v", res, err)
+	}
+
+	// CAM3 is only local: push adds it to the file, while a teammate's edit of CAM2 stays pending.
+	if err := os.WriteFile(store, []byte("cameras:\n  CAM1: Lobby\n  CAM2: Loading dock\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runProfilesCLI(t, "--config", cfgPath, "--output", "json", "cameras", "label", "push")
+	if err != nil {
+		t.Fatalf("push: %v\n%s", err, out)
+	}
+	var pushed labelSyncResult
+	if err := json.Unmarshal([]byte(out), &pushed); err != nil {
+		t.Fatal(err)
+	}
+	if len(pushed.Applied) != 1 || pushed.Applied[0].CameraID != "CAM3" || len(pushed.Pending) != 1 || pushed.Pending[0].CameraID != "CAM2" {
+		t.Fatalf("push result = %+v", pushed)
+	}
+	if b := string(mustReadFile(t, store)); !strings.Contains(b, "CAM3: Yard") || !strings.Contains(b, "CAM2: Loading dock") {
+		t.Fatalf("store after push:\n%s", b)
+	}
+
+	out, err = runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "pull")
+	if err != nil {
+		t.Fatalf("second pull: %v\n%s", err, out)
+	}
+	if got := mustLoadLabels(t, cfgPath, "prod"); got["CAM2"] != "Loading dock" {
+		t.Fatalf("labels after second pull = %v", got)
+	}
+}
+
+func TestCamerasLabelPushProfileStoreUpdatesIndex(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	eu := Config{BaseURL: "https://api.eu.verkada.com", OrgID: "ORG-EU"}
+	prod := Config{
+		BaseURL:    "https://api.verkada.com",
+		OrgID:      "ORG1",
+		LabelStore: "profile:eu",
+		Labels:     &LocalLabels{Cameras: map[string]string{"CAM2": "Dock"}},
+	}
+	if err := writeConfig(cfgPath, ConfigFile{CurrentProfile: "prod", Profiles: map[string]Config{"prod": prod, "eu": eu}}); err != nil {
+		t.Fatal(err)
+	}
+	rf := rootFlags{Profile: "eu"}
+	idxPath, err := camerasIndexPath(rf, eu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(idxPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := rebuildCamerasIndex(idxPath, rf, eu, []map[string]any{{"camera_id": "CAM2", "name": "Cam 2"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := runProfilesCLI(t, "--config", cfgPath, "cameras", "label", "push"); err != nil {
+		t.Fatalf("push: %v\n%s", err, out)
+	}
+	if got := mustLoadLabels(t, cfgPath, "eu"); got["CAM2"] != "Dock" {
+		t.Fatalf("eu labels after push = %v", got)
+	}
+	res, err := searchCamerasIndex(idxPath, "dock", 10)
+	if err != nil || len(res.Results) != 1 || res.Results[0].CameraID != "CAM2" {
+		t.Fatalf("eu index after push = %+v, %v", res, err)
+	}
+}
+
+func TestHTTPLabelStoreConditionalWrites(t *testing.T) {
+	var mu sync.Mutex
+	labels := map[string]string{"CAM1": "Lobby"}
+	rev := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer team-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		etag := fmt.Sprintf(`"%d"`, rev)
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", etag)
+			_ = json.NewEncoder(w).Encode(LocalLabels{Cameras: labels})
+		case http.MethodPut:
+			if r.Header.Get("If-Match") != etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			var doc LocalLabels
+			if err := json.Unmarshal(b, &doc); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			labels = doc.Cameras
+			rev++
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(labelStoreTokenEnv, "team-token")
+
+	s, err := parseLabelStore(srv.URL+"/labels", "", srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, version, err := s.Load()
+	if err != nil || got["CAM1"] != "Lobby" || version != `"1"` {
+		t.Fatalf("load = %v, %q, %v", got, version, err)
+	}
+	if err := s.Save(map[string]string{"CAM1": "Front"}, version); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := s.Save(map[string]string{"CAM1": "Stale"}, version); err != errLabelStoreChanged {
+		t.Fatalf("stale save: %v", err)
+	}
+	if labels["CAM1"] != "Front" {
+		t.Fatalf("server labels = %v", labels)
+	}
+}
+
+func TestHTTPLabelStoreRequiresPreconditions(t *testing.T) {
+	var mu sync.Mutex
+	var stored []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(stored)
+		case http.MethodPut:
+			if r.Header.Get("If-None-Match") == "*" && stored != nil || r.Header.Get("If-None-Match") == "" && r.Header.Get("If-Match") == "" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			stored, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	s := &httpLabelStore{url: srv.URL, client: srv.Client()}
+	_, version, err := s.Load()
+	if err != nil || version != httpLabelStoreEmpty {
+		t.Fatalf("empty store: version %q, %v", version, err)
+	}
+	if err := s.Save(map[string]string{"CAM1": "Lobby"}, version); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// A teammate loaded the empty store too; their create must not overwrite the first one.
+	if err := s.Save(map[string]string{"CAM1": "Dock"}, version); err != errLabelStoreChanged {
+		t.Fatalf("second create: %v", err)
+	}
+	// The server has no ETags: refuse to load rather than push without a precondition.
+	if _, _, err := s.Load(); err == nil || !strings.Contains(err.Error(), "no ETag") {
+		t.Fatalf("load without etag: %v", err)
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func mustLoadLabels(t *testing.T, cfgPath, profile string) map[string]string {
+	t.Helper()
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profileLabels(cf.Profiles[profile])
+}
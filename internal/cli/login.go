@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -58,7 +59,10 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 	}
 	normalizeConfigFile(&cf)
 
-	profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
+	profileName, err := selectedProfileName(*rf, cf)
+	if err != nil {
+		return err
+	}
 	if !noPrompt && rf.Profile == "" && envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE") == "" {
 		for {
 			s, err := promptString(cmd, "Profile", profileName, false /* secret */)
@@ -79,7 +83,12 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 		}
 	}
 
-	profile := cf.Profiles[profileName] // ok if missing; zero value is fine
+	profile, exists := cf.Profiles[profileName] // ok if missing; zero value is fine
+	if !exists {
+		if err := validateNewProfileName(cf, profileName, ""); err != nil {
+			return err
+		}
+	}
 	if profile.Headers == nil {
 		profile.Headers = map[string]string{}
 	}
@@ -180,7 +189,9 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 		}
 	}
 
-	// Verify the provided (or discovered) config works before persisting it.
+	// Verify the provided (or discovered) config works before persisting it. Only a failed
+	// required check (or a wrong value) stops the login; optional capabilities just warn.
+	var caps *Capabilities
 	if !noVerify {
 		client := &http.Client{Timeout: verifyTimeout}
 		tmpCfg := profile
@@ -188,9 +199,12 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 		tmpCfg.OrgID = orgID
 		tmpCfg.Auth.APIKey = apiKey
 		tmpCfg.Auth.Token = token
-		if err := verifyLoginPreflight(client, &tmpCfg, rf); err != nil {
+		rep := runLoginPreflight(client, &tmpCfg, rf)
+		writePreflightReport(cmd.ErrOrStderr(), rep)
+		if err := rep.Err(); err != nil {
 			return err
 		}
+		caps = rep.Capabilities(time.Now())
 		// Carry any discovered values (e.g., token refresh) into the persisted profile.
 		if strings.TrimSpace(tmpCfg.OrgID) != "" {
 			orgID = strings.TrimSpace(tmpCfg.OrgID)
@@ -203,6 +217,29 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 		}
 	}
 
+	// Re-read under the lock so labels or tokens written by other processes while this login was
+	// prompting or verifying are kept.
+	unlock, err := lockConfig(p)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if latest, err := loadConfig(p); err == nil {
+		cf = latest
+		if cur, ok := cf.Profiles[profileName]; ok {
+			cur.Auth.TokenAcquiredAt = profile.Auth.TokenAcquiredAt
+			profile = cur
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if caps != nil {
+		profile.Capabilities = caps
+	} else if profile.Auth.APIKey != apiKey {
+		// Capabilities describe the API key they were checked with.
+		profile.Capabilities = nil
+	}
 	profile.BaseURL = baseURL
 	profile.Auth.APIKey = apiKey
 	// Keep org ID if present (used for footage streaming endpoints).
@@ -224,6 +261,28 @@ func runLogin(cmd *cobra.Command, rf *rootFlags, noPrompt, noVerify bool, verify
 	return nil
 }
 
+// writePreflightReport prints one line per login preflight check, as warnings for the optional
+// capabilities that are unavailable.
+func writePreflightReport(w io.Writer, rep preflightReport) {
+	for _, c := range rep.Checks {
+		switch c.Status {
+		case preflightPass:
+			fmt.Fprintf(w, "preflight %s: ok", c.Name)
+			if c.Reason != "" {
+				fmt.Fprintf(w, " (%s)", c.Reason)
+			}
+			fmt.Fprintln(w)
+		case preflightSkip:
+			fmt.Fprintf(w, "warning: preflight %s skipped: %s\n", c.Name, c.Reason)
+		default:
+			fmt.Fprintf(w, "warning: preflight %s failed: %s\n", c.Name, c.Reason)
+		}
+	}
+	if len(rep.Permissions) > 0 {
+		fmt.Fprintf(w, "footage permissions: %s\n", strings.Join(rep.Permissions, ", "))
+	}
+}
+
 func sanitizeBaseURLDefault(s string) string {
 	u, err := url.Parse(strings.TrimSpace(s))
 	if err != nil {
@@ -264,6 +323,12 @@ func firstNonEmpty(vs ...string) string {
 }
 
 func promptString(cmd *cobra.Command, label, def string, secret bool) (string, error) {
+	return promptStringFrom(cmd, bufio.NewReader(cmd.InOrStdin()), label, def, secret)
+}
+
+// promptStringFrom is promptString reading from r, for callers that prompt several times: a
+// fresh bufio.Reader per prompt would swallow the lines buffered for the next one.
+func promptStringFrom(cmd *cobra.Command, r *bufio.Reader, label, def string, secret bool) (string, error) {
 	out := cmd.ErrOrStderr() // prompts go to stderr
 	if def != "" {
 		fmt.Fprintf(out, "%s [%s]: ", label, def)
@@ -285,7 +350,6 @@ func promptString(cmd *cobra.Command, label, def string, secret bool) (string, e
 		return s, nil
 	}
 
-	r := bufio.NewReader(cmd.InOrStdin())
 	line, err := r.ReadString('\n')
 	if err != nil && !errors.Is(err, os.ErrClosed) {
 		// If stdin has no newline, ReadString can return data with err==io.EOF; keep the data.
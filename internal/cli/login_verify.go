@@ -8,68 +8,234 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 )
 
-// verifyLoginPreflight checks that the provided login values actually work:
-// - API key can list cameras (and obtains a camera_id to test streaming)
-// - org_id is present (required for streaming endpoints)
-// - streaming token endpoint works
-// - live m3u8 for a known camera_id returns an HLS playlist (200 + #EXTM3U)
+// Login preflight checks, in the order they run.
+const (
+	checkListCameras      = "list_cameras"
+	checkCoreOrganization = "core_organization"
+	checkFootageToken     = "footage_token"
+	checkLiveM3U8         = "live_m3u8"
+	checkThumbnail        = "thumbnail"
+)
+
+const (
+	preflightPass = "pass"
+	preflightSkip = "skip"
+	preflightFail = "fail"
+)
+
+// preflightCheck is the outcome of one login preflight check. Only list_cameras is required;
+// the others probe optional capabilities (streaming, thumbnails) and just warn when they fail,
+// unless the failure shows that a login value itself is wrong.
+type preflightCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	// Permissions lists the footage token permissions the check relies on or discovered.
+	Permissions []string `json:"permissions,omitempty"`
+
+	badValue bool
+}
+
+// blocking reports whether the check's failure should stop a login.
+func (c preflightCheck) blocking() bool {
+	return c.Status == preflightFail && (c.Required || c.badValue)
+}
+
+type preflightReport struct {
+	Checks            []preflightCheck `json:"checks"`
+	Permissions       []string         `json:"permissions,omitempty"`
+	AccessibleCameras []string         `json:"accessible_cameras,omitempty"`
+	AccessibleSites   []string         `json:"accessible_sites,omitempty"`
+}
+
+// loginValueError is a preflight failure caused by a wrong login value (such as an org_id that
+// does not own the camera) rather than a missing permission.
+type loginValueError struct{ msg string }
+
+func (e loginValueError) Error() string { return e.msg }
+
+// Err returns the first failure that should stop a login: a failed required check, or any check
+// that failed because of a wrong login value.
+func (r preflightReport) Err() error {
+	for _, c := range r.Checks {
+		if c.blocking() {
+			return fmt.Errorf("%s", c.Reason)
+		}
+	}
+	return nil
+}
+
+// Capabilities is the report in the form stored in the profile.
+func (r preflightReport) Capabilities(now time.Time) *Capabilities {
+	c := &Capabilities{
+		CheckedAt:         now.UTC().Format(time.RFC3339),
+		Checks:            map[string]string{},
+		Permissions:       r.Permissions,
+		AccessibleCameras: r.AccessibleCameras,
+		AccessibleSites:   r.AccessibleSites,
+	}
+	for _, ch := range r.Checks {
+		c.Checks[ch.Name] = ch.Status
+	}
+	return c
+}
+
+// runLoginPreflight checks what the provided login values can do:
+// - list_cameras: the API key can list cameras (and yields a camera_id for the checks below)
+// - core_organization: the key can read the organization, whose id must match org_id
+// - footage_token: a streaming token can be issued; its permissions are recorded
+// - live_m3u8: the live playlist for a camera returns HLS (needs org_id and the live permission)
+// - thumbnail: a thumbnail can be fetched for a camera
 //
-// It updates cfg in-place if token refresh is required by some endpoints.
-func verifyLoginPreflight(client *http.Client, cfg *Config, rf *rootFlags) error {
+// It updates cfg in-place if token refresh is required by some endpoints, and fills an empty
+// org_id from the organization endpoint.
+func runLoginPreflight(client *http.Client, cfg *Config, rf *rootFlags) preflightReport {
 	if client == nil {
 		client = &http.Client{Timeout: 20 * time.Second}
 	}
+	var rep preflightReport
+	add := func(c preflightCheck) preflightCheck {
+		rep.Checks = append(rep.Checks, c)
+		return c
+	}
 
-	// 1) Verify camera listing works (also gives us a camera_id).
+	// 1) Camera listing is the one capability every command needs.
+	list := preflightCheck{Name: checkListCameras, Required: true, Status: preflightPass}
 	cameraID, err := preflightFetchAnyCameraID(client, cfg, rf)
+	switch {
+	case err != nil:
+		list.Status, list.Reason = preflightFail, err.Error()
+	case cameraID == "":
+		list.Reason = "the org has no cameras; camera checks skipped"
+	}
+	if add(list).Status == preflightFail {
+		for _, name := range []string{checkCoreOrganization, checkFootageToken, checkLiveM3U8, checkThumbnail} {
+			add(preflightCheck{Name: name, Status: preflightSkip, Reason: "cameras list failed"})
+		}
+		return rep
+	}
+
+	// 2) The organization endpoint confirms (or discovers) org_id.
+	add(preflightCoreOrganization(client, cfg, rf))
+
+	// 3) A footage token is needed for streaming and tells us what the key may access.
+	token := preflightCheck{Name: checkFootageToken, Status: preflightPass}
+	tok, err := fetchStreamingToken(client, *cfg, rf)
 	if err != nil {
-		return err
+		token.Status, token.Reason = preflightFail, fmt.Sprintf("could not fetch streaming jwt: %v", err)
+	} else {
+		rep.Permissions, rep.AccessibleCameras, rep.AccessibleSites = tok.Permission, tok.AccessibleCameras, tok.AccessibleSites
+		token.Permissions = tok.Permission
+		if len(tok.Permission) == 0 {
+			token.Reason = "token reports no permissions"
+		}
+		// A key scoped to some cameras can only stream those.
+		if len(tok.AccessibleCameras) > 0 && cameraID != "" && !slices.Contains(tok.AccessibleCameras, cameraID) {
+			cameraID = tok.AccessibleCameras[0]
+		}
 	}
+	add(token)
 
-	// 2) org_id is required for streaming endpoints.
-	if strings.TrimSpace(cfg.OrgID) == "" {
-		return errors.New("login preflight failed: org id is empty (set --org-id or VERKADA_ORG_ID); required for footage streaming")
+	// 4) The live playlist for a known camera_id looks like HLS.
+	live := preflightCheck{Name: checkLiveM3U8, Permissions: []string{"live"}}
+	switch {
+	case cameraID == "":
+		live.Status, live.Reason = preflightSkip, "no camera to test"
+	case strings.TrimSpace(cfg.OrgID) == "":
+		live.Status, live.Reason = preflightSkip, "org id is empty (set --org-id or VERKADA_ORG_ID); required for footage streaming"
+	case token.Status != preflightPass:
+		live.Status, live.Reason = preflightSkip, "no footage token"
+	case len(tok.Permission) > 0 && !hasPermission(tok.Permission, "live"):
+		live.Status, live.Reason = preflightSkip, fmt.Sprintf("footage token lacks the live permission (has: %s)", strings.Join(tok.Permission, ", "))
+	default:
+		live.Status = preflightPass
+		streamURL, err := buildFootageStreamM3U8URL(cfg.BaseURL, cfg.OrgID, cameraID, tok.JWT, 0, 0, "low_res", "h264")
+		if err == nil {
+			err = preflightCheckM3U8(client, *cfg, rf, streamURL, cameraID)
+		} else {
+			err = fmt.Errorf("could not build stream url: %w", err)
+		}
+		if err != nil {
+			live.Status, live.Reason = preflightFail, err.Error()
+			live.badValue = errors.As(err, new(loginValueError))
+		}
 	}
+	add(live)
 
-	// 3) Verify we can get a streaming JWT.
-	jwt, err := fetchStreamingJWT(client, *cfg, rf)
-	if err != nil {
-		return fmt.Errorf("login preflight failed: could not fetch streaming jwt: %w", err)
+	// 5) Thumbnails use the regular API key rather than the footage token.
+	thumb := preflightCheck{Name: checkThumbnail, Status: preflightPass}
+	if cameraID == "" {
+		thumb.Status, thumb.Reason = preflightSkip, "no camera to test"
+	} else if _, _, err := fetchThumbnailJPEG(client, cfg, rf, cameraID, 0, "low-res"); err != nil {
+		thumb.Status, thumb.Reason = preflightFail, err.Error()
 	}
+	add(thumb)
+	return rep
+}
 
-	// 4) Verify the live playlist responds and looks like HLS.
-	streamURL, err := buildFootageStreamM3U8URL(cfg.BaseURL, cfg.OrgID, cameraID, jwt, 0, 0, "low_res", "h264")
+func preflightCoreOrganization(client *http.Client, cfg *Config, rf *rootFlags) preflightCheck {
+	c := preflightCheck{Name: checkCoreOrganization, Status: preflightFail}
+	b, status, err := doCoreOrganizationRequest(client, cfg, rf)
 	if err != nil {
-		return fmt.Errorf("login preflight failed: could not build stream url: %w", err)
+		c.Reason = err.Error()
+		return c
 	}
-	if err := preflightCheckM3U8(client, *cfg, rf, streamURL, cameraID); err != nil {
-		return err
+	if status >= 400 {
+		c.Reason = fmt.Sprintf("organization request failed with status %d", status)
+		if msg, ok := apiErrorMessage(b); ok {
+			c.Reason += ": " + msg
+		}
+		return c
+	}
+	orgID, ok := parseOrgIDFromBody(b)
+	switch {
+	case !ok:
+		c.Reason = "organization response has no org id"
+	case strings.TrimSpace(cfg.OrgID) == "":
+		cfg.OrgID = orgID
+		c.Status, c.Reason = preflightPass, "org id discovered: "+orgID
+	case strings.TrimSpace(cfg.OrgID) != orgID:
+		c.Reason = fmt.Sprintf("org_id %s does not match the API key's organization %s", strings.TrimSpace(cfg.OrgID), orgID)
+		c.badValue = true
+	default:
+		c.Status = preflightPass
 	}
+	return c
+}
 
-	return nil
+func hasPermission(perms []string, want string) bool {
+	for _, p := range perms {
+		if strings.EqualFold(strings.TrimSpace(p), want) {
+			return true
+		}
+	}
+	return false
 }
 
+// preflightFetchAnyCameraID returns the first camera_id in the org, or "" when it has none.
 func preflightFetchAnyCameraID(client *http.Client, cfg *Config, rf *rootFlags) (string, error) {
 	// Page size 1 is enough for validation and avoids pulling huge orgs.
 	b, ct, status, err := doCamerasDevicesRequest(client, cfg, rf, "" /* pageToken */, 1 /* pageSize */)
 	if err != nil {
-		return "", fmt.Errorf("login preflight failed: could not list cameras: %w", err)
+		return "", fmt.Errorf("could not list cameras: %w", err)
 	}
 	if looksLikeHTML(ct, b) {
-		return "", errors.New("login preflight failed: received HTML from cameras list endpoint (check --base-url is https://api(.eu|.au).verkada.com and auth headers)")
+		return "", errors.New("received HTML from cameras list endpoint (check --base-url is https://api(.eu|.au).verkada.com and auth headers)")
 	}
 	if status >= 400 {
 		if pretty, ok := tryPrettyJSON(bytes.TrimSpace(b)); ok {
-			return "", fmt.Errorf("login preflight failed: cameras list request failed with status %d: %s", status, strings.TrimSpace(string(pretty)))
+			return "", fmt.Errorf("cameras list request failed with status %d: %s", status, strings.TrimSpace(string(pretty)))
 		}
 		if msg, ok := apiErrorMessage(b); ok {
-			return "", fmt.Errorf("login preflight failed: cameras list request failed with status %d: %s", status, msg)
+			return "", fmt.Errorf("cameras list request failed with status %d: %s", status, msg)
 		}
-		return "", fmt.Errorf("login preflight failed: cameras list request failed with status %d", status)
+		return "", fmt.Errorf("cameras list request failed with status %d", status)
 	}
 
 	var out struct {
@@ -78,10 +244,10 @@ func preflightFetchAnyCameraID(client *http.Client, cfg *Config, rf *rootFlags)
 		} `json:"cameras"`
 	}
 	if err := json.Unmarshal(b, &out); err != nil {
-		return "", fmt.Errorf("login preflight failed: cameras list returned non-JSON (check --base-url and auth): %w", err)
+		return "", fmt.Errorf("cameras list returned non-JSON (check --base-url and auth): %w", err)
 	}
-	if len(out.Cameras) == 0 || strings.TrimSpace(out.Cameras[0].CameraID) == "" {
-		return "", errors.New("login preflight failed: cameras list succeeded but returned 0 cameras; cannot validate streaming")
+	if len(out.Cameras) == 0 {
+		return "", nil
 	}
 	return strings.TrimSpace(out.Cameras[0].CameraID), nil
 }
@@ -89,21 +255,21 @@ func preflightFetchAnyCameraID(client *http.Client, cfg *Config, rf *rootFlags)
 func preflightCheckM3U8(client *http.Client, cfg Config, rf *rootFlags, streamURL, cameraID string) error {
 	req, err := http.NewRequest("GET", streamURL, nil)
 	if err != nil {
-		return fmt.Errorf("login preflight failed: invalid stream url: %w", err)
+		return fmt.Errorf("invalid stream url: %w", err)
 	}
 	applyDefaultHeaders(req, cfg)
 	if err := applyHeaderFlags(req, rf.Headers); err != nil {
-		return fmt.Errorf("login preflight failed: invalid headers: %w", err)
+		return fmt.Errorf("invalid headers: %w", err)
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("login preflight failed: stream playlist request failed: %w", err)
+		return fmt.Errorf("stream playlist request failed: %w", err)
 	}
 	defer resp.Body.Close()
 	b, err := ioReadAllLimit(resp.Body, 64*1024)
 	if err != nil {
-		return fmt.Errorf("login preflight failed: stream playlist read failed: %w", err)
+		return fmt.Errorf("stream playlist read failed: %w", err)
 	}
 
 	// Helpful hint for common org mismatch.
@@ -111,20 +277,20 @@ func preflightCheckM3U8(client *http.Client, cfg Config, rf *rootFlags, streamUR
 		if msg, ok := apiErrorMessage(bytes.TrimSpace(b)); ok {
 			lm := strings.ToLower(msg)
 			if strings.Contains(lm, "camera not found") {
-				return fmt.Errorf("login preflight failed: streaming endpoint could not find camera %s under org_id %s (org_id likely incorrect)", cameraID, strings.TrimSpace(cfg.OrgID))
+				return loginValueError{fmt.Sprintf("streaming endpoint could not find camera %s under org_id %s (org_id likely incorrect)", cameraID, strings.TrimSpace(cfg.OrgID))}
 			}
-			return fmt.Errorf("login preflight failed: streaming endpoint returned 404: %s", msg)
+			return fmt.Errorf("streaming endpoint returned 404: %s", msg)
 		}
-		return fmt.Errorf("login preflight failed: streaming endpoint returned 404 (org_id/camera_id mismatch likely)")
+		return fmt.Errorf("streaming endpoint returned 404 (org_id/camera_id mismatch likely)")
 	}
 	if resp.StatusCode >= 400 {
 		if pretty, ok := tryPrettyJSON(bytes.TrimSpace(b)); ok {
-			return fmt.Errorf("login preflight failed: streaming endpoint failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(pretty)))
+			return fmt.Errorf("streaming endpoint failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(pretty)))
 		}
 		if msg, ok := apiErrorMessage(bytes.TrimSpace(b)); ok {
-			return fmt.Errorf("login preflight failed: streaming endpoint failed with status %d: %s", resp.StatusCode, msg)
+			return fmt.Errorf("streaming endpoint failed with status %d: %s", resp.StatusCode, msg)
 		}
-		return fmt.Errorf("login preflight failed: streaming endpoint failed with status %d", resp.StatusCode)
+		return fmt.Errorf("streaming endpoint failed with status %d", resp.StatusCode)
 	}
 
 	// Basic HLS sniff.
@@ -135,11 +301,11 @@ func preflightCheckM3U8(client *http.Client, cfg Config, rf *rootFlags, streamUR
 			loc := resp.Header.Get("Location")
 			if strings.TrimSpace(loc) != "" {
 				if _, err := url.Parse(loc); err == nil {
-					return fmt.Errorf("login preflight failed: streaming endpoint returned redirect to %q (unexpected)", loc)
+					return fmt.Errorf("streaming endpoint returned redirect to %q (unexpected)", loc)
 				}
 			}
 		}
-		return errors.New("login preflight failed: streaming endpoint returned non-m3u8 content (check org_id/camera permissions)")
+		return errors.New("streaming endpoint returned non-m3u8 content (check org_id/camera permissions)")
 	}
 	return nil
 }
@@ -153,3 +319,22 @@ func ioReadAllLimit(r io.Reader, limit int64) ([]byte, error) {
 	}
 	return b, nil
 }
+
+// formatCapabilities is the one-line form of c used by profiles show and config explain, e.g.
+// "list_cameras, thumbnail (checked 2026-03-01T10:00:00Z; unavailable: live_m3u8)".
+func formatCapabilities(c *Capabilities) string {
+	var ok, missing []string
+	for _, name := range []string{checkListCameras, checkCoreOrganization, checkFootageToken, checkLiveM3U8, checkThumbnail} {
+		switch c.Checks[name] {
+		case preflightPass:
+			ok = append(ok, name)
+		case preflightSkip, preflightFail:
+			missing = append(missing, name)
+		}
+	}
+	s := fmt.Sprintf("%s (checked %s", firstNonEmpty(strings.Join(ok, ", "), "none"), c.CheckedAt)
+	if len(missing) > 0 {
+		s += "; unavailable: " + strings.Join(missing, ", ")
+	}
+	return s + ")"
+}
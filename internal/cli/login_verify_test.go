@@ -1,11 +1,15 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestVerifyLoginPreflight_Success(t *testing.T) {
@@ -37,8 +41,8 @@ func TestVerifyLoginPreflight_Success(t *testing.T) {
 		Headers: map[string]string{},
 	}
 	rf := &rootFlags{}
-	if err := verifyLoginPreflight(srv.Client(), cfg, rf); err != nil {
-		t.Fatalf("verifyLoginPreflight err = %v", err)
+	if err := runLoginPreflight(srv.Client(), cfg, rf).Err(); err != nil {
+		t.Fatalf("runLoginPreflight err = %v", err)
 	}
 }
 
@@ -72,7 +76,7 @@ func TestVerifyLoginPreflight_StreamOrgMismatch(t *testing.T) {
 		Headers: map[string]string{},
 	}
 	rf := &rootFlags{}
-	err := verifyLoginPreflight(srv.Client(), cfg, rf)
+	err := runLoginPreflight(srv.Client(), cfg, rf).Err()
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
@@ -80,3 +84,112 @@ func TestVerifyLoginPreflight_StreamOrgMismatch(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRunLoginPreflight_OptionalChecksDoNotFail(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/devices", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"cameras":[]}`)
+	})
+	mux.HandleFunc("/core/v1/organization", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(403)
+		fmt.Fprint(w, `{"message":"Insufficient permissions"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfg := &Config{BaseURL: srv.URL, Auth: AuthConfig{APIKey: "k"}}
+	rep := runLoginPreflight(srv.Client(), cfg, &rootFlags{})
+	if err := rep.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	got := rep.Capabilities(time.Unix(0, 0)).Checks
+	want := map[string]string{
+		checkListCameras:      preflightPass,
+		checkCoreOrganization: preflightFail,
+		checkFootageToken:     preflightFail,
+		checkLiveM3U8:         preflightSkip,
+		checkThumbnail:        preflightSkip,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("checks = %v, want %v", got, want)
+	}
+}
+
+func TestRunLoginPreflight_OrgIDMismatch(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/devices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"cameras":[{"camera_id":"cam-123"}]}`)
+	})
+	mux.HandleFunc("/core/v1/organization", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"organization_id":"org-2"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfg := &Config{BaseURL: srv.URL, OrgID: "org-1", Auth: AuthConfig{APIKey: "k"}}
+	err := runLoginPreflight(srv.Client(), cfg, &rootFlags{}).Err()
+	if err == nil || !strings.Contains(err.Error(), "does not match the API key's organization org-2") {
+		t.Fatalf("Err() = %v", err)
+	}
+}
+
+func TestLoginStoresCapabilities(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cameras/v1/devices", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"cameras":[{"camera_id":"cam-1"}]}`)
+	})
+	mux.HandleFunc("/core/v1/organization", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"organization_id":"org-1"}`)
+	})
+	mux.HandleFunc("/cameras/v1/footage/token", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jwt":"jwt-abc","expiration":1800,"permission":["historical"],"accessibleCameras":["cam-2"],"accessibleSites":["site-1"]}`)
+	})
+	mux.HandleFunc("/cameras/v1/footage/thumbnails", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("camera_id") != "cam-2" {
+			w.WriteHeader(404)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write([]byte{0xff, 0xd8, 0xff, 0xd9})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	cmd := NewRootCmd()
+	var out, errBuf bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errBuf)
+	cmd.SetArgs([]string{"login", "--no-prompt", "--config", cfgPath, "--base-url", srv.URL, "--api-key", "abc123"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v (stderr=%q)", err, errBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "warning: preflight live_m3u8 skipped: footage token lacks the live permission") {
+		t.Fatalf("stderr = %q", errBuf.String())
+	}
+
+	cf, err := loadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := cf.Profiles["default"]
+	if p.OrgID != "org-1" || p.Capabilities == nil {
+		t.Fatalf("profile = %+v", p)
+	}
+	want := map[string]string{
+		checkListCameras:      preflightPass,
+		checkCoreOrganization: preflightPass,
+		checkFootageToken:     preflightPass,
+		checkLiveM3U8:         preflightSkip,
+		checkThumbnail:        preflightPass,
+	}
+	if !reflect.DeepEqual(p.Capabilities.Checks, want) || !reflect.DeepEqual(p.Capabilities.Permissions, []string{"historical"}) {
+		t.Fatalf("capabilities = %+v", p.Capabilities)
+	}
+}
@@ -75,11 +75,43 @@ func ensureOrgID(client *http.Client, cfg *Config, rf *rootFlags) (bool, error)
 		return false, nil
 	}
 
-	u, err := buildCoreOrganizationURL(cfg.BaseURL)
+	b, status, err := doCoreOrganizationRequest(client, cfg, rf)
 	if err != nil {
 		return false, err
 	}
 
+	if status >= 400 {
+		// Provide a helpful error for common cases, but keep this best-effort.
+		if msg, ok := apiErrorMessage(b); ok {
+			lm := strings.ToLower(msg)
+			if status == 403 && strings.Contains(lm, "insufficient permissions") {
+				return false, errors.New("cannot auto-discover org id via /core/v1/organization: insufficient permissions for this API key (set --org-id or VERKADA_ORG_ID manually)")
+			}
+			if status == 401 {
+				return false, fmt.Errorf("cannot auto-discover org id via /core/v1/organization: authentication failed (%s)", msg)
+			}
+		}
+		return false, nil
+	}
+
+	orgID, ok := parseOrgIDFromBody(b)
+	if !ok {
+		return false, nil
+	}
+
+	cfg.OrgID = orgID
+	_ = persistProfileOrgID(*rf, orgID) // best-effort
+	return true, nil
+}
+
+// doCoreOrganizationRequest calls /core/v1/organization, retrying once with a fresh API token
+// when required.
+func doCoreOrganizationRequest(client *http.Client, cfg *Config, rf *rootFlags) ([]byte, int, error) {
+	u, err := buildCoreOrganizationURL(cfg.BaseURL)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	doOnce := func() (int, []byte, error) {
 		req, err := http.NewRequest("GET", u, nil)
 		if err != nil {
@@ -109,42 +141,20 @@ func ensureOrgID(client *http.Client, cfg *Config, rf *rootFlags) (bool, error)
 
 	status, b, err := doOnce()
 	if err != nil {
-		return false, err
+		return nil, 0, err
 	}
 	if looksLikeHTML("", b) {
-		return false, errors.New("received HTML from /core/v1/organization (check --base-url is https://api(.eu|.au).verkada.com and auth headers)")
+		return nil, 0, errors.New("received HTML from /core/v1/organization (check --base-url is https://api(.eu|.au).verkada.com and auth headers)")
 	}
 
 	// Auto-fetch API token if required/expired and retry once.
 	if refreshed, err := maybeRefreshTokenOnAuthError(client, cfg, rf, status, b); err != nil {
-		return false, err
+		return nil, 0, err
 	} else if refreshed {
 		status, b, err = doOnce()
 		if err != nil {
-			return false, err
+			return nil, 0, err
 		}
 	}
-
-	if status >= 400 {
-		// Provide a helpful error for common cases, but keep this best-effort.
-		if msg, ok := apiErrorMessage(b); ok {
-			lm := strings.ToLower(msg)
-			if status == 403 && strings.Contains(lm, "insufficient permissions") {
-				return false, errors.New("cannot auto-discover org id via /core/v1/organization: insufficient permissions for this API key (set --org-id or VERKADA_ORG_ID manually)")
-			}
-			if status == 401 {
-				return false, fmt.Errorf("cannot auto-discover org id via /core/v1/organization: authentication failed (%s)", msg)
-			}
-		}
-		return false, nil
-	}
-
-	orgID, ok := parseOrgIDFromBody(b)
-	if !ok {
-		return false, nil
-	}
-
-	cfg.OrgID = orgID
-	_ = persistProfileOrgID(*rf, orgID) // best-effort
-	return true, nil
+	return b, status, nil
 }
@@ -23,6 +23,7 @@ func NewProfilesCmd(rf *rootFlags) *cobra.Command {
 	cmd.AddCommand(newProfilesListCmd(rf))
 	cmd.AddCommand(newProfilesAddCmd(rf))
 	cmd.AddCommand(newProfilesPathCmd(rf))
+	addProfileManageCmds(cmd, rf)
 	return cmd
 }
 
@@ -0,0 +1,583 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// addProfileManageCmds registers the lifecycle commands shared by `profiles` and `config profiles`.
+func addProfileManageCmds(cmd *cobra.Command, rf *rootFlags) {
+	cmd.AddCommand(newProfilesShowCmd(rf))
+	cmd.AddCommand(newProfilesRmCmd(rf))
+	cmd.AddCommand(newProfilesRenameCmd(rf))
+	cmd.AddCommand(newProfilesCopyCmd(rf))
+	cmd.AddCommand(newProfilesSetCmd(rf))
+	cmd.AddCommand(newProfilesUnsetCmd(rf))
+}
+
+func validateProfileName(name string) error {
+	if name == "" {
+		return errors.New("profile name is empty")
+	}
+	if strings.ContainsAny(name, " \t") {
+		return errors.New("profile name must not contain spaces")
+	}
+	return nil
+}
+
+// validateNewProfileName is validateProfileName for a profile about to be added to cf (replacing
+// the profile named replacing, if any). Cameras index directories are named after the profile
+// lower-cased with punctuation folded to "_", so "Prod" and "prod" would share one.
+func validateNewProfileName(cf ConfigFile, name, replacing string) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	dir := sanitizePathComponent(name)
+	for _, n := range sortedKeys(cf.Profiles) {
+		if n != name && n != replacing && sanitizePathComponent(n) == dir {
+			return fmt.Errorf("profile name %q is too close to existing profile %q (both would use cameras index directory %q)", name, n, dir)
+		}
+	}
+	return nil
+}
+
+// sharedIndexDir returns another profile of cf whose cameras index lives in dir, if any.
+func sharedIndexDir(rf *rootFlags, cf ConfigFile, name, dir string) string {
+	for _, n := range sortedKeys(cf.Profiles) {
+		if n == name {
+			continue
+		}
+		if d, err := profileIndexDir(rf, n, resolvedProfileOrRaw(cf, n)); err == nil && d == dir {
+			return n
+		}
+	}
+	return ""
+}
+
+// loadProfilesConfig loads the config file for commands that edit existing profiles.
+func loadProfilesConfig(rf *rootFlags) (string, ConfigFile, error) {
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return "", ConfigFile{}, err
+	}
+	cf, err := loadConfig(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", ConfigFile{}, fmt.Errorf("config file does not exist at %s (run: verkcli login or verkcli config init)", p)
+		}
+		return "", ConfigFile{}, err
+	}
+	return p, cf, nil
+}
+
+// lockProfilesConfig is loadProfilesConfig for commands that write the file back: it holds the
+// config lock until the returned func is called.
+func lockProfilesConfig(rf *rootFlags) (string, ConfigFile, func(), error) {
+	p, err := resolveConfigPath(rf.ConfigPath)
+	if err != nil {
+		return "", ConfigFile{}, nil, err
+	}
+	unlock, err := lockConfig(p)
+	if err != nil {
+		return "", ConfigFile{}, nil, err
+	}
+	p, cf, err := loadProfilesConfig(rf)
+	if err != nil {
+		unlock()
+		return "", ConfigFile{}, nil, err
+	}
+	return p, cf, unlock, nil
+}
+
+// targetProfileName is the profile edited by set/unset/show: the argument, --profile, the
+// environment, then the current profile.
+func targetProfileName(rf *rootFlags, cf ConfigFile, args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.TrimSpace(args[0]), nil
+	}
+	return selectedProfileName(*rf, cf)
+}
+
+func writeProfileResult(cmd *cobra.Command, rf *rootFlags, text string, fields map[string]any) error {
+	if rf.Output == "json" {
+		blob, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = cmd.OutOrStdout().Write(append(blob, '\n'))
+		return nil
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), text)
+	return nil
+}
+
+// profileIndexDir is the cameras index directory of a profile (see camerasIndexPath).
+func profileIndexDir(rf *rootFlags, name string, p Config) (string, error) {
+	r := *rf
+	r.Profile = name
+	path, err := camerasIndexPath(r, p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(path), nil
+}
+
+func newProfilesShowCmd(rf *rootFlags) *cobra.Command {
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "show [PROFILE]",
+		Short: "Print a stored profile (secrets masked)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cf, err := loadProfilesConfig(rf)
+			if err != nil {
+				return err
+			}
+			name, err := targetProfileName(rf, cf, args)
+			if err != nil {
+				return err
+			}
+			prof, ok := cf.Profiles[name]
+			if !ok {
+				return fmt.Errorf("profile %q not found in %s", name, p)
+			}
+			if !showSecrets {
+				prof.Auth.APIKey = maskSecret(prof.Auth.APIKey)
+				prof.Auth.Token = maskSecret(prof.Auth.Token)
+			}
+
+			if rf.Output == "json" {
+				view := struct {
+					Profile string `json:"profile"`
+					Current bool   `json:"current"`
+					Config
+				}{Profile: name, Current: name == cf.CurrentProfile, Config: prof}
+				blob, err := json.MarshalIndent(view, "", "  ")
+				if err != nil {
+					return err
+				}
+				_, _ = cmd.OutOrStdout().Write(append(blob, '\n'))
+				return nil
+			}
+			formatProfileText(cmd.OutOrStdout(), name, name == cf.CurrentProfile, prof)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print the API key and token in full")
+	return cmd
+}
+
+func formatProfileText(w io.Writer, name string, current bool, p Config) {
+	fmt.Fprintf(w, "profile: %s\n", name)
+	fmt.Fprintf(w, "current: %v\n", current)
+	if p.Extends != "" {
+		fmt.Fprintf(w, "extends: %s\n", p.Extends)
+	}
+	fmt.Fprintf(w, "base_url: %s\n", p.BaseURL)
+	fmt.Fprintf(w, "org_id: %s\n", p.OrgID)
+	if p.LabelStore != "" {
+		fmt.Fprintf(w, "label_store: %s\n", p.LabelStore)
+	}
+	fmt.Fprintf(w, "api_key: %s\n", p.Auth.APIKey)
+	fmt.Fprintf(w, "token: %s\n", p.Auth.Token)
+	if p.Capabilities != nil {
+		fmt.Fprintf(w, "capabilities: %s\n", formatCapabilities(p.Capabilities))
+	}
+	keys := make([]string, 0, len(p.Headers))
+	for k := range p.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "headers.%s: %s\n", k, p.Headers[k])
+	}
+	n := 0
+	if p.Labels != nil {
+		n = len(p.Labels.Cameras)
+	}
+	fmt.Fprintf(w, "camera_labels: %d\n", n)
+}
+
+// maskSecret keeps the first four characters of longer secrets so keys can still be told apart.
+func maskSecret(s string) string {
+	switch {
+	case s == "":
+		return ""
+	case len(s) <= 8:
+		return "****"
+	default:
+		return s[:4] + "****"
+	}
+}
+
+func newProfilesRmCmd(rf *rootFlags) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:     "rm PROFILE",
+		Aliases: []string{"remove"},
+		Short:   "Remove a profile and its cameras index",
+		Long: strings.TrimSpace(`
+Removes a profile from the config file and deletes its local cameras index.
+
+Removing the current profile needs --force; "default" (or the first remaining profile by name)
+becomes current.
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cf, unlock, err := lockProfilesConfig(rf)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			name := strings.TrimSpace(args[0])
+			_, ok := cf.Profiles[name]
+			if !ok {
+				return fmt.Errorf("profile %q not found in %s", name, p)
+			}
+			if name == cf.CurrentProfile && !force {
+				return fmt.Errorf("%q is the current profile (use --force to remove it anyway)", name)
+			}
+			if children := profileChildren(cf, name); len(children) > 0 {
+				return fmt.Errorf("profile %q is extended by %s; change their extends first", name, strings.Join(children, ", "))
+			}
+			idxDir, idxErr := profileIndexDir(rf, name, resolvedProfileOrRaw(cf, name))
+
+			delete(cf.Profiles, name)
+			if idxErr == nil {
+				// Profiles named before names were checked may share an index directory.
+				if other := sharedIndexDir(rf, cf, name, idxDir); other != "" {
+					idxErr = fmt.Errorf("cameras index %s is also used by profile %q", idxDir, other)
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: kept cameras index %s, profile %s uses it too\n", idxDir, other)
+				}
+			}
+			if name == cf.CurrentProfile {
+				cf.CurrentProfile = ""
+				if _, ok := cf.Profiles["default"]; ok {
+					cf.CurrentProfile = "default"
+				} else {
+					names := make([]string, 0, len(cf.Profiles))
+					for n := range cf.Profiles {
+						names = append(names, n)
+					}
+					sort.Strings(names)
+					if len(names) > 0 {
+						cf.CurrentProfile = names[0]
+					}
+				}
+			}
+			if err := writeConfig(p, cf); err != nil {
+				return err
+			}
+			if idxErr == nil {
+				if err := os.RemoveAll(idxDir); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: could not remove cameras index %s: %v\n", idxDir, err)
+				}
+			}
+
+			text := "removed profile " + name
+			if cf.CurrentProfile != "" {
+				text += "\ncurrent profile: " + cf.CurrentProfile
+			}
+			return writeProfileResult(cmd, rf, text, map[string]any{
+				"removed":         name,
+				"current_profile": cf.CurrentProfile,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Allow removing the current profile")
+	return cmd
+}
+
+func newProfilesRenameCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename a profile (and move its cameras index)",
+		Long: strings.TrimSpace(`
+Renames a profile, moves its cameras index, and updates profiles that extend it.
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cf, unlock, err := lockProfilesConfig(rf)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			oldName, newName := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+			if err := validateNewProfileName(cf, newName, oldName); err != nil {
+				return err
+			}
+			prof, ok := cf.Profiles[oldName]
+			if !ok {
+				return fmt.Errorf("profile %q not found in %s", oldName, p)
+			}
+			if _, exists := cf.Profiles[newName]; exists {
+				return fmt.Errorf("profile %q already exists", newName)
+			}
+
+			resolved := resolvedProfileOrRaw(cf, oldName)
+			for _, child := range profileChildren(cf, oldName) {
+				c := cf.Profiles[child]
+				c.Extends = newName
+				cf.Profiles[child] = c
+			}
+			delete(cf.Profiles, oldName)
+			cf.Profiles[newName] = prof
+			if cf.CurrentProfile == oldName {
+				cf.CurrentProfile = newName
+			}
+			if err := writeConfig(p, cf); err != nil {
+				return err
+			}
+			moveProfileIndex(cmd.ErrOrStderr(), rf, oldName, newName, resolved)
+
+			return writeProfileResult(cmd, rf, fmt.Sprintf("renamed profile %s to %s", oldName, newName), map[string]any{
+				"from":            oldName,
+				"to":              newName,
+				"current_profile": cf.CurrentProfile,
+			})
+		},
+	}
+	return cmd
+}
+
+// moveProfileIndex renames the cameras index directory so it keeps matching the profile name.
+// Failures only warn: the index is a cache and can be rebuilt.
+func moveProfileIndex(warn io.Writer, rf *rootFlags, oldName, newName string, prof Config) {
+	from, err := profileIndexDir(rf, oldName, prof)
+	if err != nil {
+		return
+	}
+	to, err := profileIndexDir(rf, newName, prof)
+	if err != nil || from == to {
+		return
+	}
+	if _, err := os.Stat(from); err != nil {
+		return
+	}
+	if _, err := os.Stat(to); err == nil {
+		fmt.Fprintf(warn, "warning: cameras index %s already exists; left %s in place\n", to, from)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err == nil {
+		err = os.Rename(from, to)
+	}
+	if err != nil {
+		fmt.Fprintf(warn, "warning: could not move cameras index to %s: %v (rebuild with: verkcli cameras index build)\n", to, err)
+	}
+}
+
+func newProfilesCopyCmd(rf *rootFlags) *cobra.Command {
+	var noLabels bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:     "copy SRC DST",
+		Aliases: []string{"cp"},
+		Short:   "Copy a profile under a new name (including camera labels)",
+		Example: strings.TrimSpace(`
+  verkcli profiles copy prod staging
+  verkcli profiles copy prod eu --no-labels && verkcli --profile eu profiles set base_url https://api.eu.verkada.com
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, cf, unlock, err := lockProfilesConfig(rf)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			src, dst := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+			if err := validateNewProfileName(cf, dst, ""); err != nil {
+				return err
+			}
+			prof, ok := cf.Profiles[src]
+			if !ok {
+				return fmt.Errorf("profile %q not found in %s", src, p)
+			}
+			if _, exists := cf.Profiles[dst]; exists && !force {
+				return fmt.Errorf("profile %q already exists (use --force to overwrite it)", dst)
+			}
+
+			cp := cloneProfile(prof)
+			if noLabels {
+				cp.Labels = &LocalLabels{Cameras: map[string]string{}}
+			}
+			cf.Profiles[dst] = cp
+			if err := writeConfig(p, cf); err != nil {
+				return err
+			}
+
+			labels := len(cp.Labels.Cameras)
+			return writeProfileResult(cmd, rf, fmt.Sprintf("copied profile %s to %s (%d camera labels)", src, dst, labels), map[string]any{
+				"from":          src,
+				"to":            dst,
+				"camera_labels": labels,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&noLabels, "no-labels", false, "Do not copy local camera labels")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite DST if it exists")
+	return cmd
+}
+
+// cloneProfile deep-copies the maps so edits to the copy do not leak into the source.
+func cloneProfile(p Config) Config {
+	cp := p
+	cp.Headers = make(map[string]string, len(p.Headers))
+	for k, v := range p.Headers {
+		cp.Headers[k] = v
+	}
+	cp.Labels = &LocalLabels{Cameras: map[string]string{}}
+	if p.Labels != nil {
+		for k, v := range p.Labels.Cameras {
+			cp.Labels.Cameras[k] = v
+		}
+		if len(p.Labels.Tags) > 0 {
+			cp.Labels.Tags = make(map[string][]string, len(p.Labels.Tags))
+			for k, v := range p.Labels.Tags {
+				cp.Labels.Tags[k] = append([]string(nil), v...)
+			}
+		}
+		if len(p.Labels.Notes) > 0 {
+			cp.Labels.Notes = make(map[string][]CameraNote, len(p.Labels.Notes))
+			for k, v := range p.Labels.Notes {
+				cp.Labels.Notes[k] = append([]CameraNote(nil), v...)
+			}
+		}
+	}
+	return cp
+}
+
+const profileKeysHelp = "extends, base_url, org_id, label_store, api_key, token, headers.NAME"
+
+func newProfilesSetCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a field of the selected profile (" + profileKeysHelp + ")",
+		Long: strings.TrimSpace(`
+Sets one field of a stored profile. The profile is --profile, VERKCLI_PROFILE, or the current
+profile.
+
+Keys: ` + profileKeysHelp + `
+`),
+		Example: strings.TrimSpace(`
+  verkcli profiles set org_id ORG123
+  verkcli --profile eu-west profiles set extends base
+  verkcli --profile staging profiles set base_url https://api.eu.verkada.com
+  verkcli profiles set headers.X-Request-Source verkcli
+`),
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesSet(cmd, rf, args[0], args[1], false)
+		},
+	}
+	return cmd
+}
+
+func newProfilesUnsetCmd(rf *rootFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Clear a field of the selected profile (" + profileKeysHelp + ")",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesSet(cmd, rf, args[0], "", true)
+		},
+	}
+	return cmd
+}
+
+func runProfilesSet(cmd *cobra.Command, rf *rootFlags, key, value string, unset bool) error {
+	p, cf, unlock, err := lockProfilesConfig(rf)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	name, err := targetProfileName(rf, cf, nil)
+	if err != nil {
+		return err
+	}
+	prof, ok := cf.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found in %s", name, p)
+	}
+	key = strings.TrimSpace(key)
+	if err := setProfileField(&prof, key, value, unset); err != nil {
+		return err
+	}
+	cf.Profiles[name] = prof
+	if _, err := profileChain(cf, name); err != nil {
+		return err
+	}
+	if err := writeConfig(p, cf); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("%s: set %s", name, key)
+	if unset {
+		text = fmt.Sprintf("%s: unset %s", name, key)
+	}
+	return writeProfileResult(cmd, rf, text, map[string]any{
+		"profile": name,
+		"key":     key,
+		"unset":   unset,
+	})
+}
+
+func setProfileField(p *Config, key, value string, unset bool) error {
+	if unset {
+		value = ""
+	}
+	switch strings.ToLower(key) {
+	case "extends":
+		p.Extends = strings.TrimSpace(value)
+	case "base_url":
+		if !unset {
+			if _, err := validateBaseURL(value); err != nil {
+				return err
+			}
+		}
+		p.BaseURL = value
+	case "org_id":
+		p.OrgID = value
+	case "label_store":
+		if !unset {
+			if _, err := parseLabelStore(value, "", nil); err != nil {
+				return err
+			}
+		}
+		p.LabelStore = strings.TrimSpace(value)
+	case "api_key", "auth.api_key":
+		p.Auth.APIKey = value
+		// A session token and the recorded capabilities belong to the API key they came from.
+		p.Auth.Token, p.Auth.TokenAcquiredAt = "", 0
+		p.Capabilities = nil
+	case "token", "auth.token":
+		p.Auth.Token, p.Auth.TokenAcquiredAt = value, 0
+	default:
+		header, ok := strings.CutPrefix(key, "headers.")
+		if !ok || strings.TrimSpace(header) == "" {
+			return fmt.Errorf("unknown key %q (expected %s)", key, profileKeysHelp)
+		}
+		if p.Headers == nil {
+			p.Headers = map[string]string{}
+		}
+		if unset {
+			delete(p.Headers, header)
+		} else {
+			p.Headers[header] = value
+		}
+	}
+	return nil
+}
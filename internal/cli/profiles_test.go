@@ -2,7 +2,9 @@ package cli
 
 import (
 	"bytes"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -111,3 +113,193 @@ func TestProfilesPath(t *testing.T) {
 		t.Fatalf("got=%q want=%q", got, cfgPath+"\n")
 	}
 }
+
+func runProfilesCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewRootCmd()
+	var out, errBuf bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errBuf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func writeLifecycleConfig(t *testing.T) string {
+	t.Helper()
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	orig := ConfigFile{
+		CurrentProfile: "prod",
+		Profiles: map[string]Config{
+			"prod": {
+				BaseURL: "https://api.example.com",
+				OrgID:   "ORG1",
+				Auth:    AuthConfig{APIKey: "key-1234567890", Token: "tok-abcdefghij", TokenAcquiredAt: 100},
+				Headers: map[string]string{"X-Team": "ops"},
+				Labels:  &LocalLabels{Cameras: map[string]string{"CAM1": "Front Door"}},
+			},
+			"lab": {BaseURL: "https://api.lab.example.com", Headers: map[string]string{}},
+		},
+	}
+	if err := writeConfig(cfgPath, orig); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return cfgPath
+}
+
+func TestProfilesCopyRenameRemove(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("VERKCLI_PROFILE", "")
+	cfgPath := writeLifecycleConfig(t)
+
+	if _, err := runProfilesCLI(t, "profiles", "copy", "prod", "staging", "--config", cfgPath); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if _, err := runProfilesCLI(t, "profiles", "copy", "prod", "bare", "--no-labels", "--config", cfgPath); err != nil {
+		t.Fatalf("copy --no-labels: %v", err)
+	}
+	if _, err := runProfilesCLI(t, "profiles", "copy", "prod", "lab", "--config", cfgPath); err == nil {
+		t.Fatal("copy onto an existing profile should need --force")
+	}
+	cf, _ := loadConfig(cfgPath)
+	if cf.Profiles["staging"].Labels.Cameras["CAM1"] != "Front Door" || cf.Profiles["staging"].Auth.APIKey != "key-1234567890" {
+		t.Fatalf("staging copy: %+v", cf.Profiles["staging"])
+	}
+	if len(cf.Profiles["bare"].Labels.Cameras) != 0 {
+		t.Fatalf("--no-labels copy kept labels: %+v", cf.Profiles["bare"].Labels)
+	}
+
+	// Rename the current profile and carry its cameras index along.
+	prod := cf.Profiles["prod"]
+	oldIdx, _ := profileIndexDir(&rootFlags{}, "prod", prod)
+	if err := os.MkdirAll(oldIdx, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(oldIdx, "cameras.sqlite"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runProfilesCLI(t, "profiles", "rename", "prod", "production", "--config", cfgPath, "--output", "json")
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if !bytes.Contains([]byte(out), []byte(`"current_profile": "production"`)) {
+		t.Fatalf("rename output: %s", out)
+	}
+	newIdx, _ := profileIndexDir(&rootFlags{}, "production", prod)
+	if _, err := os.Stat(filepath.Join(newIdx, "cameras.sqlite")); err != nil {
+		t.Fatalf("index should have moved to %s: %v", newIdx, err)
+	}
+	if _, err := os.Stat(oldIdx); !os.IsNotExist(err) {
+		t.Fatalf("old index dir should be gone: %v", err)
+	}
+	if _, err := runProfilesCLI(t, "profiles", "rename", "lab", "staging", "--config", cfgPath); err == nil {
+		t.Fatal("rename onto an existing profile should fail")
+	}
+
+	// Removing the current profile needs --force and then falls back to the first name.
+	if _, err := runProfilesCLI(t, "profiles", "rm", "production", "--config", cfgPath); err == nil {
+		t.Fatal("removing the current profile should need --force")
+	}
+	if _, err := runProfilesCLI(t, "config", "profiles", "rm", "production", "--force", "--config", cfgPath); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	cf, _ = loadConfig(cfgPath)
+	if _, ok := cf.Profiles["production"]; ok || cf.CurrentProfile != "bare" {
+		t.Fatalf("after rm: current=%q profiles=%v", cf.CurrentProfile, cf.Profiles)
+	}
+	if _, err := os.Stat(newIdx); !os.IsNotExist(err) {
+		t.Fatalf("index dir should be removed with the profile: %v", err)
+	}
+}
+
+func TestProfilesNamesSharingIndexDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("VERKCLI_PROFILE", "")
+	cfgPath := writeLifecycleConfig(t)
+
+	for _, args := range [][]string{
+		{"profiles", "copy", "prod", "Prod"},
+		{"profiles", "rename", "lab", "PROD"},
+		{"profiles", "add", "prod!", "--no-prompt", "--no-verify", "--api-key", "k"},
+	} {
+		_, err := runProfilesCLI(t, append(args, "--config", cfgPath)...)
+		if err == nil || !strings.Contains(err.Error(), `existing profile "prod"`) {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	if _, err := runProfilesCLI(t, "profiles", "rename", "lab", "Lab", "--config", cfgPath); err != nil {
+		t.Fatalf("rename to a different case of itself: %v", err)
+	}
+
+	// Profiles that already share a directory: rm keeps the index for the other one.
+	cf, _ := loadConfig(cfgPath)
+	cf.Profiles["PROD"] = cf.Profiles["prod"]
+	if err := writeConfig(cfgPath, cf); err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := profileIndexDir(&rootFlags{}, "prod", cf.Profiles["prod"])
+	if err := os.MkdirAll(idx, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runProfilesCLI(t, "profiles", "rm", "PROD", "--config", cfgPath)
+	if err != nil {
+		t.Fatalf("rm: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(idx); err != nil {
+		t.Fatalf("rm deleted the index of the remaining profile: %v", err)
+	}
+}
+
+func TestProfilesSetUnsetShow(t *testing.T) {
+	t.Setenv("VERKCLI_PROFILE", "")
+	cfgPath := writeLifecycleConfig(t)
+
+	for _, args := range [][]string{
+		{"profiles", "set", "org_id", "ORG2"},
+		{"profiles", "set", "headers.X-Source", "verkcli"},
+		{"profiles", "unset", "headers.X-Team"},
+		{"--profile", "lab", "profiles", "set", "base_url", "https://api.eu.example.com"},
+	} {
+		if _, err := runProfilesCLI(t, append(args, "--config", cfgPath)...); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	if _, err := runProfilesCLI(t, "profiles", "set", "colour", "blue", "--config", cfgPath); err == nil {
+		t.Fatal("unknown key should fail")
+	}
+	for _, bad := range []string{"api.example.com", "https://", "https://acme.command.verkada.com"} {
+		if _, err := runProfilesCLI(t, "profiles", "set", "base_url", bad, "--config", cfgPath); err == nil {
+			t.Fatalf("base_url %q should fail", bad)
+		}
+	}
+	// Changing the API key drops the session token issued for the old one.
+	if _, err := runProfilesCLI(t, "profiles", "set", "api_key", "key-new-0987654321", "--config", cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cf, _ := loadConfig(cfgPath)
+	prod := cf.Profiles["prod"]
+	if prod.OrgID != "ORG2" || prod.Headers["X-Source"] != "verkcli" || prod.Headers["X-Team"] != "" || prod.Auth.Token != "" {
+		t.Fatalf("prod after set/unset: %+v", prod)
+	}
+	if cf.Profiles["lab"].BaseURL != "https://api.eu.example.com" {
+		t.Fatalf("lab base_url: %q", cf.Profiles["lab"].BaseURL)
+	}
+
+	out, err := runProfilesCLI(t, "profiles", "show", "--config", cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"profile: prod\n", "current: true\n", "api_key: key-****\n", "headers.X-Source: verkcli\n", "camera_labels: 1\n"} {
+		if !bytes.Contains([]byte(out), []byte(want)) {
+			t.Fatalf("show output missing %q:\n%s", want, out)
+		}
+	}
+	out, err = runProfilesCLI(t, "profiles", "show", "prod", "--show-secrets", "--output", "json", "--config", cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains([]byte(out), []byte(`"api_key": "key-new-0987654321"`)) || !bytes.Contains([]byte(out), []byte(`"profile": "prod"`)) {
+		t.Fatalf("show --show-secrets json: %s", out)
+	}
+}
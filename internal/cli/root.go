@@ -16,6 +16,12 @@ type rootFlags struct {
 	Debug      bool
 	Output     string
 	Headers    []string
+	// TrustProject lets a project config point base_url or label_store at other hosts.
+	TrustProject bool
+
+	// noPersist keeps refreshed tokens and discovered org ids in memory only (config doctor
+	// checks profiles other than the selected one and must not write to any of them).
+	noPersist bool
 }
 
 // NewRootCmd builds the root command and wires subcommands.
@@ -27,9 +33,12 @@ func NewRootCmd() *cobra.Command {
 		Short:         "CLI for Verkada APIs",
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			applyProjectOutput(cmd, &rf)
+		},
 	}
 
-	cmd.PersistentFlags().StringVar(&rf.ConfigPath, "config", "", "Config file path (default: $XDG_CONFIG_HOME/verkcli/config.json)")
+	cmd.PersistentFlags().StringVar(&rf.ConfigPath, "config", "", "Config file path, .json, .yaml or .toml (default: $XDG_CONFIG_HOME/verkcli/config.json)")
 	cmd.PersistentFlags().StringVar(&rf.Profile, "profile", "", "Config profile to use (or set VERKCLI_PROFILE / VERKADA_PROFILE)")
 	cmd.PersistentFlags().StringVar(&rf.BaseURL, "base-url", "", "Base URL (or set VERKCLI_BASE_URL / VERKADA_BASE_URL)")
 	cmd.PersistentFlags().StringVar(&rf.OrgID, "org-id", "", "Organization ID (or set VERKCLI_ORG_ID / VERKADA_ORG_ID)")
@@ -39,6 +48,8 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(&rf.Debug, "debug", false, "Enable debug logging")
 	cmd.PersistentFlags().StringArrayVarP(&rf.Headers, "header", "H", nil, "Extra header (repeatable), e.g. -H 'X-Foo: bar'")
 
+	cmd.PersistentFlags().BoolVar(&rf.TrustProject, "trust-project", false, "Let the project config (.verkcli.yaml) change base_url or use an http label_store (or set VERKCLI_TRUST_PROJECT=1)")
+
 	_ = cmd.PersistentFlags().MarkHidden("token") // keep surface area small; headers cover most auth modes
 
 	cmd.SetOut(os.Stdout)
@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/term"
+)
+
+// termGraphicsBackend turns a JPEG into the terminal output that draws it.
+type termGraphicsBackend interface {
+	// Encode returns the sequences to write, in order. Under tmux each sequence is wrapped in
+	// a passthrough envelope separately, so protocols that chunk their payload return one
+	// element per chunk.
+	Encode(jpegData []byte, name string, cols int) ([]string, error)
+	// Passthrough reports whether the output is a terminal escape sequence that tmux must
+	// forward to the outer terminal (false for plain ANSI text).
+	Passthrough() bool
+}
+
+var termGraphicsBackends = map[string]termGraphicsBackend{
+	"iterm2": iterm2Graphics{},
+	"kitty":  kittyGraphics{},
+	"sixel":  sixelGraphics{},
+	"blocks": blockGraphics{},
+}
+
+func viewProtocolNames() string {
+	names := make([]string, 0, len(termGraphicsBackends))
+	for n := range termGraphicsBackends {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return "auto|" + strings.Join(names, "|")
+}
+
+// detectTermGraphics returns the image protocol the terminal is known to support, or "" when
+// nothing better than plain text is available. Under tmux, TERM/TERM_PROGRAM describe tmux
+// itself, so the outer terminal is recognized by the variables it exports into the session.
+func detectTermGraphics(getenv func(string) string) string {
+	termEnv := getenv("TERM")
+	prog := getenv("TERM_PROGRAM")
+	switch {
+	case getenv("ITERM_SESSION_ID") != "", getenv("WEZTERM_PANE") != "",
+		prog == "iTerm.app", prog == "WezTerm", getenv("LC_TERMINAL") == "iTerm2":
+		return "iterm2"
+	case getenv("KITTY_WINDOW_ID") != "", termEnv == "xterm-kitty",
+		getenv("GHOSTTY_RESOURCES_DIR") != "", prog == "ghostty", termEnv == "xterm-ghostty":
+		return "kitty"
+	case strings.HasPrefix(termEnv, "foot"), strings.Contains(termEnv, "sixel"),
+		prog == "mlterm", getenv("WT_SESSION") != "":
+		return "sixel"
+	}
+	return ""
+}
+
+// resolveViewProtocol maps a --view-protocol value to a backend name. "auto" falls back to
+// half blocks, which work in any terminal with 24-bit color.
+func resolveViewProtocol(flag string, getenv func(string) string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(flag))
+	if p == "" || p == "auto" {
+		if d := detectTermGraphics(getenv); d != "" {
+			return d, nil
+		}
+		return "blocks", nil
+	}
+	if _, ok := termGraphicsBackends[p]; !ok {
+		return "", fmt.Errorf("invalid --view-protocol %q (expected %s)", flag, viewProtocolNames())
+	}
+	return p, nil
+}
+
+// viewProtocolExplicit reports whether --view-protocol names a backend, which implies --view.
+func viewProtocolExplicit(flag string) bool {
+	p := strings.ToLower(strings.TrimSpace(flag))
+	return p != "" && p != "auto"
+}
+
+// renderInlineImage draws jpegData on w with the selected protocol (see resolveViewProtocol).
+func renderInlineImage(w io.Writer, protocol string, jpegData []byte, name string) error {
+	return renderInlineImageCols(w, protocol, jpegData, name, terminalColumns(w))
+}
+
+// renderInlineImageCols is renderInlineImage for a terminal cols cells wide, for callers that
+// render into a buffer and write it to the terminal later.
+func renderInlineImageCols(w io.Writer, protocol string, jpegData []byte, name string, cols int) error {
+	if len(jpegData) == 0 {
+		return errors.New("empty image")
+	}
+	p, err := resolveViewProtocol(protocol, os.Getenv)
+	if err != nil {
+		return err
+	}
+	backend := termGraphicsBackends[p]
+	seqs, err := backend.Encode(jpegData, name, cols)
+	if err != nil {
+		return err
+	}
+	tmux := backend.Passthrough() && os.Getenv("TMUX") != ""
+	var buf bytes.Buffer
+	for _, s := range seqs {
+		if tmux {
+			s = wrapTmuxPassthrough(s)
+		}
+		buf.WriteString(s)
+	}
+	buf.WriteByte('\n')
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// wrapTmuxPassthrough wraps an escape sequence so tmux forwards it to the outer terminal
+// (requires `set -g allow-passthrough on` in tmux 3.3+).
+func wrapTmuxPassthrough(seq string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// termClearScreen clears the screen and scrollback and homes the cursor. Kitty keeps image
+// placements across a text clear, so they are deleted explicitly.
+func termClearScreen(protocol string) string {
+	s := "\x1b[H\x1b[2J\x1b[3J"
+	if protocol == "kitty" {
+		del := "\x1b_Ga=d,q=2\x1b\\"
+		if os.Getenv("TMUX") != "" {
+			del = wrapTmuxPassthrough(del)
+		}
+		s = del + s
+	}
+	return s
+}
+
+func terminalColumns(w io.Writer) int {
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if cols, _, err := term.GetSize(int(f.Fd())); err == nil && cols > 0 {
+			return cols
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// iterm2Graphics speaks the iTerm2 inline images protocol (OSC 1337), also used by WezTerm:
+// https://iterm2.com/documentation-images.html
+type iterm2Graphics struct{}
+
+func (iterm2Graphics) Passthrough() bool { return true }
+
+func (iterm2Graphics) Encode(jpegData []byte, name string, cols int) ([]string, error) {
+	return []string{fmt.Sprintf("\033]1337;File=name=%s;inline=1;size=%d;preserveAspectRatio=1:%s\a",
+		base64.StdEncoding.EncodeToString([]byte(name)),
+		len(jpegData),
+		base64.StdEncoding.EncodeToString(jpegData),
+	)}, nil
+}
+
+// kittyGraphics speaks the kitty graphics protocol (kitty, Ghostty). The protocol has no JPEG
+// format, so the image is re-encoded as PNG and sent in 4096-byte base64 chunks:
+// https://sw.kovidgoyal.net/kitty/graphics-protocol/
+type kittyGraphics struct{}
+
+const kittyChunkSize = 4096
+
+func (kittyGraphics) Passthrough() bool { return true }
+
+func (kittyGraphics) Encode(jpegData []byte, name string, cols int) ([]string, error) {
+	img, err := jpeg.Decode(bytes.NewReader(jpegData))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	payload := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var seqs []string
+	for i := 0; i < len(payload); i += kittyChunkSize {
+		end := min(i+kittyChunkSize, len(payload))
+		more := 1
+		if end == len(payload) {
+			more = 0
+		}
+		// a=T transmits and displays; q=2 suppresses the terminal's OK/error replies, which
+		// would otherwise show up as typed input.
+		ctrl := fmt.Sprintf("m=%d", more)
+		if i == 0 {
+			ctrl = "a=T,f=100,q=2," + ctrl
+			if cols > 0 && img.Bounds().Dx() > cols*10 {
+				// Very wide images would be clipped; scale to the terminal width in cells.
+				ctrl += fmt.Sprintf(",c=%d", cols-1)
+			}
+		}
+		seqs = append(seqs, "\x1b_G"+ctrl+";"+payload[i:end]+"\x1b\\")
+	}
+	return seqs, nil
+}
+
+// sixelGraphics encodes DEC Sixel (foot, mlterm, Windows Terminal, xterm -ti vt340). Images are
+// scaled down to a reasonable size and dithered to a fixed 216-color palette.
+type sixelGraphics struct{}
+
+const sixelMaxWidth, sixelMaxHeight = 800, 600
+
+func (sixelGraphics) Passthrough() bool { return true }
+
+func (sixelGraphics) Encode(jpegData []byte, name string, cols int) ([]string, error) {
+	img, err := jpeg.Decode(bytes.NewReader(jpegData))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	b := img.Bounds()
+	r := fitRect(b, image.Rect(0, 0, min(b.Dx(), sixelMaxWidth), min(b.Dy(), sixelMaxHeight)))
+	pal := image.NewPaletted(image.Rect(0, 0, r.Dx(), r.Dy()), palette.WebSafe)
+	if r.Dx() == b.Dx() && r.Dy() == b.Dy() {
+		draw.FloydSteinberg.Draw(pal, pal.Bounds(), img, b.Min)
+	} else {
+		scaled := image.NewRGBA(pal.Bounds())
+		draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, b, draw.Src, nil)
+		draw.FloydSteinberg.Draw(pal, pal.Bounds(), scaled, image.Point{})
+	}
+	return []string{encodeSixel(pal)}, nil
+}
+
+// encodeSixel writes a paletted image as a sixel sequence. Each band of six rows is emitted
+// once per color present in it, with run-length encoding of repeated sixel characters.
+func encodeSixel(img *image.Paletted) string {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	var sb strings.Builder
+	sb.WriteString("\x1bP0;1;0q")
+	fmt.Fprintf(&sb, "\"1;1;%d;%d", w, h)
+
+	used := make([]bool, len(img.Palette))
+	for _, idx := range img.Pix {
+		used[idx] = true
+	}
+	for i, c := range img.Palette {
+		if !used[i] {
+			continue
+		}
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+
+	rows := map[uint8][]byte{}
+	for y0 := 0; y0 < h; y0 += 6 {
+		clear(rows)
+		var order []uint8
+		for k := 0; k < 6 && y0+k < h; k++ {
+			off := (y0 + k) * img.Stride
+			for x := 0; x < w; x++ {
+				idx := img.Pix[off+x]
+				row, ok := rows[idx]
+				if !ok {
+					row = make([]byte, w)
+					rows[idx] = row
+					order = append(order, idx)
+				}
+				row[x] |= 1 << k
+			}
+		}
+		for n, idx := range order {
+			if n > 0 {
+				sb.WriteByte('$')
+			}
+			fmt.Fprintf(&sb, "#%d", idx)
+			row := rows[idx]
+			for x := 0; x < w; {
+				run := 1
+				for x+run < w && row[x+run] == row[x] {
+					run++
+				}
+				ch := byte(63 + row[x])
+				if run > 3 {
+					fmt.Fprintf(&sb, "!%d%c", run, ch)
+				} else {
+					for i := 0; i < run; i++ {
+						sb.WriteByte(ch)
+					}
+				}
+				x += run
+			}
+		}
+		sb.WriteByte('-')
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
+
+// blockGraphics draws with Unicode upper-half blocks and 24-bit ANSI colors: each character
+// cell shows two vertically stacked pixels (foreground on top, background below). It works in
+// any truecolor terminal and through tmux without passthrough.
+type blockGraphics struct{}
+
+func (blockGraphics) Passthrough() bool { return false }
+
+func (blockGraphics) Encode(jpegData []byte, name string, cols int) ([]string, error) {
+	img, err := jpeg.Decode(bytes.NewReader(jpegData))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	return []string{renderHalfBlocks(img, max(cols-1, 1))}, nil
+}
+
+func renderHalfBlocks(img image.Image, width int) string {
+	b := img.Bounds()
+	if b.Dx() <= 0 || b.Dy() <= 0 {
+		return ""
+	}
+	w := min(width, b.Dx())
+	h := max(b.Dy()*w/b.Dx(), 1)
+	h += h % 2
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, b, draw.Src, nil)
+
+	var sb strings.Builder
+	for y := 0; y < h; y += 2 {
+		var lastTop, lastBot color.RGBA
+		for x := 0; x < w; x++ {
+			top := scaled.RGBAAt(x, y)
+			bot := scaled.RGBAAt(x, y+1)
+			if x == 0 || top != lastTop {
+				fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+			}
+			if x == 0 || bot != lastBot {
+				fmt.Fprintf(&sb, "\x1b[48;2;%d;%d;%dm", bot.R, bot.G, bot.B)
+			}
+			sb.WriteString("▀")
+			lastTop, lastBot = top, bot
+		}
+		sb.WriteString("\x1b[0m")
+		if y+2 < h {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
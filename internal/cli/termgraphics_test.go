@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/jpeg"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func envMap(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func testJPEG(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectTermGraphics(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, "iterm2"},
+		{map[string]string{"WEZTERM_PANE": "1"}, "iterm2"},
+		{map[string]string{"TERM": "tmux-256color", "TERM_PROGRAM": "tmux", "LC_TERMINAL": "iTerm2"}, "iterm2"},
+		{map[string]string{"TERM": "xterm-kitty"}, "kitty"},
+		{map[string]string{"TERM": "tmux-256color", "KITTY_WINDOW_ID": "3"}, "kitty"},
+		{map[string]string{"TERM_PROGRAM": "ghostty"}, "kitty"},
+		{map[string]string{"TERM": "foot"}, "sixel"},
+		{map[string]string{"TERM": "foot-extra"}, "sixel"},
+		{map[string]string{"TERM": "xterm-256color"}, ""},
+		{map[string]string{}, ""},
+	}
+	for _, c := range cases {
+		if got := detectTermGraphics(envMap(c.env)); got != c.want {
+			t.Fatalf("detectTermGraphics(%v)=%q want %q", c.env, got, c.want)
+		}
+	}
+}
+
+func TestResolveViewProtocol(t *testing.T) {
+	plain := envMap(map[string]string{"TERM": "xterm-256color"})
+	if p, _ := resolveViewProtocol("auto", plain); p != "blocks" {
+		t.Fatalf("auto on plain terminal = %q", p)
+	}
+	if p, _ := resolveViewProtocol("", envMap(map[string]string{"TERM": "xterm-kitty"})); p != "kitty" {
+		t.Fatalf("auto on kitty = %q", p)
+	}
+	if p, _ := resolveViewProtocol("SIXEL", plain); p != "sixel" {
+		t.Fatalf("explicit sixel = %q", p)
+	}
+	if _, err := resolveViewProtocol("png", plain); err == nil || !strings.Contains(err.Error(), "auto|blocks|iterm2|kitty|sixel") {
+		t.Fatalf("expected invalid protocol error, got %v", err)
+	}
+	if viewProtocolExplicit("auto") || !viewProtocolExplicit("blocks") {
+		t.Fatalf("viewProtocolExplicit mismatch")
+	}
+}
+
+func TestITerm2Graphics(t *testing.T) {
+	data := testJPEG(t, 4, 4, color.White)
+	seqs, err := iterm2Graphics{}.Encode(data, "thumb.jpg", 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := seqs[0]
+	if !strings.HasPrefix(s, "\x1b]1337;File=name="+base64.StdEncoding.EncodeToString([]byte("thumb.jpg"))) || !strings.HasSuffix(s, "\a") {
+		t.Fatalf("unexpected sequence prefix/suffix: %q", s[:40])
+	}
+	if !strings.Contains(s, ":"+base64.StdEncoding.EncodeToString(data)) {
+		t.Fatalf("payload missing")
+	}
+}
+
+func TestKittyGraphics_Chunks(t *testing.T) {
+	// Noise-free but large enough to need several chunks once PNG-encoded.
+	img := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i * 7)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	seqs, err := kittyGraphics{}.Encode(buf.Bytes(), "x.jpg", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seqs) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(seqs))
+	}
+	if !strings.HasPrefix(seqs[0], "\x1b_Ga=T,f=100,q=2,m=1;") {
+		t.Fatalf("first chunk: %q", seqs[0][:30])
+	}
+	if !strings.HasPrefix(seqs[len(seqs)-1], "\x1b_Gm=0;") {
+		t.Fatalf("last chunk: %q", seqs[len(seqs)-1][:10])
+	}
+	var payload strings.Builder
+	for _, s := range seqs {
+		if !strings.HasSuffix(s, "\x1b\\") {
+			t.Fatalf("chunk not terminated")
+		}
+		_, data, _ := strings.Cut(strings.TrimSuffix(s, "\x1b\\"), ";")
+		if len(data) > kittyChunkSize {
+			t.Fatalf("chunk too large: %d", len(data))
+		}
+		payload.WriteString(data)
+	}
+	png, err := base64.StdEncoding.DecodeString(payload.String())
+	if err != nil || !bytes.HasPrefix(png, []byte("\x89PNG")) {
+		t.Fatalf("payload is not a PNG: %v", err)
+	}
+}
+
+func TestEncodeSixel(t *testing.T) {
+	// 3x7 image: two bands; top band has two colors, bottom band one.
+	img := image.NewPaletted(image.Rect(0, 0, 3, 7), palette.WebSafe)
+	red := uint8(img.Palette.Index(color.RGBA{0xff, 0, 0, 0xff}))
+	blue := uint8(img.Palette.Index(color.RGBA{0, 0, 0xff, 0xff}))
+	for i := range img.Pix {
+		img.Pix[i] = red
+	}
+	img.SetColorIndex(1, 0, blue)
+
+	s := encodeSixel(img)
+	if !strings.HasPrefix(s, "\x1bP0;1;0q\"1;1;3;7") || !strings.HasSuffix(s, "\x1b\\") {
+		t.Fatalf("bad framing: %q", s)
+	}
+	if !strings.Contains(s, "#"+strconv.Itoa(int(red))+";2;100;0;0") || !strings.Contains(s, "#"+strconv.Itoa(int(blue))+";2;0;0;100") {
+		t.Fatalf("palette registers missing: %q", s)
+	}
+	// Band 1: red has all six bits except pixel (1,0); blue only bit 0 at x=1.
+	wantBand1 := "#" + strconv.Itoa(int(red)) + "~}~$#" + strconv.Itoa(int(blue)) + "?@?-"
+	if !strings.Contains(s, wantBand1) {
+		t.Fatalf("band 1 missing %q in %q", wantBand1, s)
+	}
+	// Band 2: only row 6 (bit 0) for red.
+	if !strings.Contains(s, "#"+strconv.Itoa(int(red))+"@@@-") {
+		t.Fatalf("band 2 wrong: %q", s)
+	}
+}
+
+func TestEncodeSixel_RunLength(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, 10, 6), palette.WebSafe)
+	s := encodeSixel(img)
+	if !strings.Contains(s, "#0!10~-") {
+		t.Fatalf("expected run-length encoding, got %q", s)
+	}
+}
+
+func TestRenderHalfBlocks(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	img.Set(1, 1, color.RGBA{0, 0, 255, 255})
+
+	got := renderHalfBlocks(img, 80)
+	want := "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀▀\x1b[0m"
+	if got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+
+	wide := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	out := renderHalfBlocks(wide, 20)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 5 || strings.Count(lines[0], "▀") != 20 {
+		t.Fatalf("scaled output: %d lines, %d cells", len(lines), strings.Count(lines[0], "▀"))
+	}
+}
+
+func TestWrapTmuxPassthrough(t *testing.T) {
+	got := wrapTmuxPassthrough("\x1b_Gm=0;AAAA\x1b\\")
+	want := "\x1bPtmux;\x1b\x1b_Gm=0;AAAA\x1b\x1b\\\x1b\\"
+	if got != want {
+		t.Fatalf("got %q want %q", got, want)
+	}
+}
+
+func TestRenderInlineImage_Blocks(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1/default,1,0")
+	var buf bytes.Buffer
+	if err := renderInlineImage(&buf, "blocks", testJPEG(t, 8, 8, color.White), "x.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	// Plain ANSI is never wrapped for tmux.
+	if strings.Contains(buf.String(), "tmux;") || !strings.Contains(buf.String(), "▀") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := renderInlineImage(&buf, "iterm2", testJPEG(t, 8, 8, color.White), "x.jpg"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "\x1bPtmux;\x1b\x1b]1337;") {
+		t.Fatalf("expected tmux passthrough, got %q", buf.String()[:20])
+	}
+}
@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeExprHelp lists the accepted time expressions for flag help and error messages.
+const timeExprHelp = "now, Unix seconds, RFC3339, YYYY-MM-DD[ HH:MM[:SS]], -2h / +30m / -1d, '10 minutes ago', 'yesterday 14:30', 'monday 9am'"
+
+var (
+	relativeAgoRE = regexp.MustCompile(`^(an?|\d+)\s*([a-z]+)\s+ago$`)
+	clockRE       = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// parseTimeExpr parses the time expressions accepted by --timestamp, --start, --end and friends.
+// now anchors relative expressions; loc is used for everything without an explicit offset.
+//
+// Offsets in hours, minutes and seconds are absolute (-24h is always 86400 seconds ago). Days
+// and weeks are calendar units: -1d and "1 day ago" keep the wall-clock time, so across a DST
+// change they are 23 or 25 hours. Wall-clock times that fall in a DST gap move forward by the
+// gap (02:30 on a spring-forward day becomes 03:30); ambiguous times in a fall-back hour
+// resolve to the first occurrence.
+func parseTimeExpr(raw string, now time.Time, loc *time.Location) (time.Time, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return time.Time{}, errors.New("empty time")
+	}
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return wallTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), loc), nil
+		}
+	}
+
+	lower := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	now = now.In(loc)
+	if lower == "now" {
+		return now, nil
+	}
+	if lower[0] == '-' || lower[0] == '+' {
+		return applyTimeOffset(now, lower[1:], lower[0] == '-', raw)
+	}
+	if rest, ok := strings.CutPrefix(lower, "in "); ok {
+		return applyTimeOffset(now, strings.ReplaceAll(rest, " ", ""), false, raw)
+	}
+	if m := relativeAgoRE.FindStringSubmatch(lower); m != nil {
+		n := 1
+		if m[1] != "a" && m[1] != "an" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		unit, ok := timeUnit(m[2])
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid time %q: unknown unit %q", raw, m[2])
+		}
+		return shiftTime(now, n, unit, true), nil
+	}
+	if rest, ok := strings.CutSuffix(lower, " ago"); ok {
+		return applyTimeOffset(now, strings.ReplaceAll(rest, " ", ""), true, raw)
+	}
+	return parseDayAndClock(lower, now, loc, raw)
+}
+
+// applyTimeOffset applies a duration such as 2h, 1h30m, 3d or 1w.
+func applyTimeOffset(now time.Time, expr string, back bool, raw string) (time.Time, error) {
+	if expr == "" {
+		return time.Time{}, fmt.Errorf("invalid time %q: missing duration", raw)
+	}
+	t := now
+	for expr != "" {
+		i := 0
+		for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+			i++
+		}
+		j := i
+		for j < len(expr) && expr[j] >= 'a' && expr[j] <= 'z' {
+			j++
+		}
+		if i == 0 || j == i {
+			return time.Time{}, fmt.Errorf("invalid time %q: expected a duration like 2h, 1h30m or 3d", raw)
+		}
+		n, err := strconv.Atoi(expr[:i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q: %w", raw, err)
+		}
+		unit, ok := timeUnit(expr[i:j])
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid time %q: unknown unit %q", raw, expr[i:j])
+		}
+		t = shiftTime(t, n, unit, back)
+		expr = expr[j:]
+	}
+	return t, nil
+}
+
+// timeUnit normalizes a unit name to s, m, h, d or w.
+func timeUnit(name string) (string, bool) {
+	switch name {
+	case "s", "sec", "secs", "second", "seconds":
+		return "s", true
+	case "m", "min", "mins", "minute", "minutes":
+		return "m", true
+	case "h", "hr", "hrs", "hour", "hours":
+		return "h", true
+	case "d", "day", "days":
+		return "d", true
+	case "w", "wk", "wks", "week", "weeks":
+		return "w", true
+	}
+	return "", false
+}
+
+func shiftTime(t time.Time, n int, unit string, back bool) time.Time {
+	if back {
+		n = -n
+	}
+	switch unit {
+	case "d", "w":
+		if unit == "w" {
+			n *= 7
+		}
+		y, mo, d := t.Date()
+		return wallTime(y, mo, d+n, t.Hour(), t.Minute(), t.Second(), t.Location())
+	case "h":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "m":
+		return t.Add(time.Duration(n) * time.Minute)
+	default:
+		return t.Add(time.Duration(n) * time.Second)
+	}
+}
+
+// parseDayAndClock handles "[day] [at] [clock]" where day is today, yesterday, tomorrow,
+// a weekday or "last <weekday>". A weekday alone is its most recent occurrence (today
+// included); "last" excludes today. A missing clock means midnight; a missing day means today.
+func parseDayAndClock(s string, now time.Time, loc *time.Location, raw string) (time.Time, error) {
+	y, mo, d := now.Date()
+	day := wallTime(y, mo, d, 0, 0, 0, loc)
+	rest := s
+	dayWord, after, _ := strings.Cut(s, " ")
+	switch dayWord {
+	case "today":
+		rest = after
+	case "yesterday":
+		day, rest = day.AddDate(0, 0, -1), after
+	case "tomorrow":
+		day, rest = day.AddDate(0, 0, 1), after
+	case "last":
+		wdWord, after2, _ := strings.Cut(after, " ")
+		wd, ok := parseWeekday(wdWord)
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid time %q: expected a weekday after \"last\"", raw)
+		}
+		back := (int(day.Weekday()) - int(wd) + 7) % 7
+		if back == 0 {
+			back = 7
+		}
+		day, rest = day.AddDate(0, 0, -back), after2
+	default:
+		if wd, ok := parseWeekday(dayWord); ok {
+			back := (int(day.Weekday()) - int(wd) + 7) % 7
+			day, rest = day.AddDate(0, 0, -back), after
+		}
+	}
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, "at "))
+	if rest == "" {
+		return day, nil
+	}
+	h, m, sec, ok := parseClock(rest)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid time %q (supported: %s)", raw, timeExprHelp)
+	}
+	y, mo, d = day.Date()
+	return wallTime(y, mo, d, h, m, sec, loc), nil
+}
+
+// wallTime is time.Date, except that a wall-clock time skipped by a DST change moves forward by
+// the size of the gap instead of whatever time.Date picks.
+func wallTime(y int, mo time.Month, d, h, m, sec int, loc *time.Location) time.Time {
+	t := time.Date(y, mo, d, h, m, sec, 0, loc)
+	want := time.Date(y, mo, d, h, m, sec, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	if diff := want.Sub(got); diff > 0 {
+		t = t.Add(diff)
+	}
+	return t
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock accepts 14:30, 14:30:05, 9am, 9:15pm, noon and midnight.
+func parseClock(s string) (h, m, sec int, ok bool) {
+	switch s {
+	case "noon":
+		return 12, 0, 0, true
+	case "midnight":
+		return 0, 0, 0, true
+	}
+	cm := clockRE.FindStringSubmatch(s)
+	if cm == nil || (cm[2] == "" && cm[4] == "") {
+		// A bare number is not a time of day ("14" is ambiguous); require 14:00 or 2pm.
+		return 0, 0, 0, false
+	}
+	h, _ = strconv.Atoi(cm[1])
+	if cm[2] != "" {
+		m, _ = strconv.Atoi(cm[2])
+	}
+	if cm[3] != "" {
+		sec, _ = strconv.Atoi(cm[3])
+	}
+	switch cm[4] {
+	case "am", "pm":
+		if h < 1 || h > 12 {
+			return 0, 0, 0, false
+		}
+		if h == 12 {
+			h = 0
+		}
+		if cm[4] == "pm" {
+			h += 12
+		}
+	}
+	if h > 23 || m > 59 || sec > 59 {
+		return 0, 0, 0, false
+	}
+	return h, m, sec, true
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeExpr(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	at := func(loc *time.Location, y int, mo time.Month, d, h, m, s int) time.Time {
+		return time.Date(y, mo, d, h, m, s, 0, loc)
+	}
+	// Sunday 2026-02-15 14:30:00 PST.
+	now := at(la, 2026, 2, 15, 14, 30, 0)
+	// Spring forward: 2026-03-08 02:00 PST -> 03:00 PDT. Fall back: 2026-11-01 02:00 PDT -> 01:00 PST.
+	afterSpring := at(la, 2026, 3, 8, 12, 0, 0)
+	afterFall := at(la, 2026, 11, 1, 12, 0, 0)
+
+	cases := []struct {
+		name string
+		in   string
+		now  time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{"unix", "1736893300", now, la, time.Unix(1736893300, 0)},
+		{"rfc3339", "2026-02-15T14:30:00Z", now, la, at(time.UTC, 2026, 2, 15, 14, 30, 0)},
+		{"rfc3339 offset", "2026-02-15T07:30:00-07:00", now, la, at(time.UTC, 2026, 2, 15, 14, 30, 0)},
+		{"rfc3339 no seconds", "2026-02-15T14:30Z", now, la, at(time.UTC, 2026, 2, 15, 14, 30, 0)},
+		{"naive T", "2026-02-15T14:30:00", now, la, at(la, 2026, 2, 15, 14, 30, 0)},
+		{"naive space", "2026-02-15 14:30:00", now, la, at(la, 2026, 2, 15, 14, 30, 0)},
+		{"naive no seconds", "2026-02-15 14:30", now, la, at(la, 2026, 2, 15, 14, 30, 0)},
+		{"naive T no seconds", "2026-02-15T14:30", now, la, at(la, 2026, 2, 15, 14, 30, 0)},
+		{"date only", "2026-02-15", now, la, at(la, 2026, 2, 15, 0, 0, 0)},
+		{"naive utc", "2026-02-15 14:30", now, time.UTC, at(time.UTC, 2026, 2, 15, 14, 30, 0)},
+		{"now", "now", now, la, now},
+		{"now padded", "  NOW ", now, la, now},
+		{"minus hours", "-2h", now, la, now.Add(-2 * time.Hour)},
+		{"plus minutes", "+30m", now, la, now.Add(30 * time.Minute)},
+		{"compound", "-1h30m", now, la, now.Add(-90 * time.Minute)},
+		{"minus seconds", "-45s", now, la, now.Add(-45 * time.Second)},
+		{"minus day", "-1d", now, la, at(la, 2026, 2, 14, 14, 30, 0)},
+		{"minus week", "-1w", now, la, at(la, 2026, 2, 8, 14, 30, 0)},
+		{"day and hours", "-1d2h", now, la, at(la, 2026, 2, 14, 12, 30, 0)},
+		{"minutes ago", "10 minutes ago", now, la, now.Add(-10 * time.Minute)},
+		{"an hour ago", "an hour ago", now, la, now.Add(-time.Hour)},
+		{"a day ago", "a day ago", now, la, at(la, 2026, 2, 14, 14, 30, 0)},
+		{"short ago", "5m ago", now, la, now.Add(-5 * time.Minute)},
+		{"compound ago", "1h 30m ago", now, la, now.Add(-90 * time.Minute)},
+		{"in", "in 2 hours", now, la, now.Add(2 * time.Hour)},
+		{"today", "today", now, la, at(la, 2026, 2, 15, 0, 0, 0)},
+		{"today clock", "today 09:15", now, la, at(la, 2026, 2, 15, 9, 15, 0)},
+		{"yesterday clock", "yesterday 14:30", now, la, at(la, 2026, 2, 14, 14, 30, 0)},
+		{"yesterday at", "Yesterday at 2:30pm", now, la, at(la, 2026, 2, 14, 14, 30, 0)},
+		{"tomorrow", "tomorrow noon", now, la, at(la, 2026, 2, 16, 12, 0, 0)},
+		{"clock only", "08:05:09", now, la, at(la, 2026, 2, 15, 8, 5, 9)},
+		{"12am", "12am", now, la, at(la, 2026, 2, 15, 0, 0, 0)},
+		{"12pm", "12pm", now, la, at(la, 2026, 2, 15, 12, 0, 0)},
+		{"midnight", "midnight", now, la, at(la, 2026, 2, 15, 0, 0, 0)},
+		{"weekday", "friday 9am", now, la, at(la, 2026, 2, 13, 9, 0, 0)},
+		{"weekday is today", "sunday", now, la, at(la, 2026, 2, 15, 0, 0, 0)},
+		{"last weekday excludes today", "last sun 10:00", now, la, at(la, 2026, 2, 8, 10, 0, 0)},
+		{"weekday short", "mon", now, la, at(la, 2026, 2, 9, 0, 0, 0)},
+		{"now in utc", "yesterday 14:30", now, time.UTC, at(time.UTC, 2026, 2, 14, 14, 30, 0)},
+
+		// DST: calendar days keep the wall clock, hours are absolute.
+		{"spring -1d keeps wall clock", "-1d", afterSpring, la, at(la, 2026, 3, 7, 12, 0, 0)},
+		{"spring 1 day ago is 23h", "1 day ago", afterSpring, la, afterSpring.Add(-23 * time.Hour)},
+		{"spring -24h is absolute", "-24h", afterSpring, la, at(la, 2026, 3, 7, 11, 0, 0)},
+		{"fall -1d keeps wall clock", "-1d", afterFall, la, at(la, 2026, 10, 31, 12, 0, 0)},
+		{"fall 1 day ago is 25h", "1 day ago", afterFall, la, afterFall.Add(-25 * time.Hour)},
+		{"fall -24h is absolute", "-24h", afterFall, la, at(la, 2026, 10, 31, 13, 0, 0)},
+		{"spring gap moves forward", "today 02:30", at(la, 2026, 3, 8, 9, 0, 0), la, at(la, 2026, 3, 8, 3, 30, 0)},
+		{"spring gap naive", "2026-03-08 02:30", now, la, at(la, 2026, 3, 8, 3, 30, 0)},
+		{"fall ambiguous first occurrence", "today 01:30", afterFall, la, time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC)},
+		{"fall ambiguous naive", "2026-11-01 01:30", now, la, time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC)},
+		{"yesterday across spring", "yesterday 12:00", afterSpring, la, at(la, 2026, 3, 7, 12, 0, 0)},
+		{"today midnight after spring", "today", afterSpring, la, time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC)},
+		{"today midnight after fall", "today", afterFall, la, time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC)},
+		{"week across spring", "-1w", afterSpring, la, at(la, 2026, 3, 1, 12, 0, 0)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTimeExpr(tc.in, tc.now, tc.loc)
+			if err != nil {
+				t.Fatalf("parseTimeExpr(%q): %v", tc.in, err)
+			}
+			if !got.Equal(tc.want) {
+				t.Fatalf("parseTimeExpr(%q) = %s, want %s", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeExpr_Rejects(t *testing.T) {
+	now := time.Date(2026, 2, 15, 14, 30, 0, 0, time.UTC)
+	for _, in := range []string{
+		"", "not-a-timestamp", "-", "-2x", "-h", "10 fortnights ago", "in", "yesterday 25:00",
+		"13pm", "last", "last week", "today at", "2026-02-30", "12:60",
+	} {
+		if got, err := parseTimeExpr(in, now, time.UTC); err == nil {
+			t.Errorf("parseTimeExpr(%q) = %s, want error", in, got)
+		}
+	}
+}
@@ -137,22 +137,25 @@ func maybeRefreshTokenOnAuthError(client *http.Client, cfg *Config, rf *rootFlag
 }
 
 func persistProfileToken(rf rootFlags, token string, acquiredAt int64) error {
-	p, err := resolveConfigPath(rf.ConfigPath)
-	if err != nil {
-		return err
+	if rf.noPersist {
+		return nil
 	}
-	cf, err := loadConfig(p)
+	p, err := resolveConfigPath(rf.ConfigPath)
 	if err != nil {
 		return err
 	}
-	normalizeConfigFile(&cf)
-	profileName := firstNonEmpty(rf.Profile, envFirst("", "VERKCLI_PROFILE", "VERKADA_PROFILE"), cf.CurrentProfile, "default")
-	profile, ok := cf.Profiles[profileName]
-	if !ok {
-		return fmt.Errorf("profile %q not found in %s", profileName, p)
-	}
-	profile.Auth.Token = token
-	profile.Auth.TokenAcquiredAt = acquiredAt
-	cf.Profiles[profileName] = profile
-	return writeConfig(p, cf)
+	return updateConfig(p, func(cf *ConfigFile) error {
+		profileName, err := selectedProfileName(rf, *cf)
+		if err != nil {
+			return err
+		}
+		profile, ok := cf.Profiles[profileName]
+		if !ok {
+			return fmt.Errorf("profile %q not found in %s", profileName, p)
+		}
+		profile.Auth.Token = token
+		profile.Auth.TokenAcquiredAt = acquiredAt
+		cf.Profiles[profileName] = profile
+		return nil
+	})
 }
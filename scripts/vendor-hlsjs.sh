@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Vendor hls.js for the `cameras wall` page. The file is embedded into the binary and served
+# from the local wall server, so the page never loads scripts from a CDN.
+# This script is intended for maintainers; commit the resulting file.
+
+VERSION="${VERSION:-1.5.20}"
+DEST="$(cd "$(dirname "$0")/.." && pwd)/internal/cli/wallassets/hls.min.js"
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+tarball="https://registry.npmjs.org/hls.js/-/hls.js-${VERSION}.tgz"
+echo "fetching ${tarball}" >&2
+curl -fsSL -o "$tmp/hls.tgz" "$tarball"
+
+# Check the tarball against the integrity hash the registry publishes for this version.
+want="$(curl -fsSL "https://registry.npmjs.org/hls.js/${VERSION}" | sed -n 's/.*"integrity":"sha512-\([^"]*\)".*/\1/p')"
+got="$(openssl dgst -sha512 -binary "$tmp/hls.tgz" | openssl base64 -A)"
+if [ -z "$want" ] || [ "$want" != "$got" ]; then
+  echo "error: integrity mismatch for hls.js ${VERSION}" >&2
+  exit 1
+fi
+
+tar -xzf "$tmp/hls.tgz" -C "$tmp" package/dist/hls.min.js
+cp "$tmp/package/dist/hls.min.js" "$DEST"
+echo "wrote ${DEST} (hls.js ${VERSION}, sha256 $(sha256sum "$DEST" | cut -d' ' -f1))" >&2